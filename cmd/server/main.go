@@ -1,63 +1,438 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"net/mail"
+	"os"
 
+	"github.com/AashishRichhariya/task-management-api/internal/cache"
+	"github.com/AashishRichhariya/task-management-api/internal/config"
 	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/internal/email"
 	"github.com/AashishRichhariya/task-management-api/internal/handlers"
+	"github.com/AashishRichhariya/task-management-api/internal/health"
 	"github.com/AashishRichhariya/task-management-api/internal/middleware"
 	"github.com/AashishRichhariya/task-management-api/internal/repository"
 	"github.com/AashishRichhariya/task-management-api/internal/service"
 	"github.com/AashishRichhariya/task-management-api/internal/utils"
+	"github.com/AashishRichhariya/task-management-api/migrations"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	// Database setup
-	db, err := database.NewPostgresConnection()
+	// Settings come from the defaults, then the YAML file at CONFIG_FILE, then the environment
+	cfg, err := config.Load(utils.GetEnv("CONFIG_FILE", ""))
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	// "server config" prints the effective settings, with secrets redacted, and exits
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		fmt.Print(cfg)
+		return
+	}
+
+	// DB_BACKEND=sqlite or memory serves just the task API, for development and demos without Postgres
+	switch cfg.Database.Backend {
+	case "sqlite":
+		runSQLiteServer(cfg)
+		return
+	case "memory":
+		runMemoryServer(cfg)
+		return
+	}
+
+	// Database setup; waits up to DB_CONNECT_TIMEOUT for Postgres to come up
+	db, err := database.NewPostgresConnection(cfg.Database)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
 	defer db.Close()
+
+	// "server migrate ..." manages the schema and exits
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+	if migrateCommand(migrator) {
+		return
+	}
+	if err := migrateOnStart(migrator, cfg.Database); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+	// Not ready without the database and the schema this build expects
+	checks := health.NewRegistry(cfg.Health.CheckTimeout)
+	checks.AddReadinessCheck("database", health.DatabaseChecker(db))
+	checks.AddReadinessCheck("migrations", health.CheckerFunc(migrator.CheckAtHead))
+	workerStallTimeout := cfg.Health.WorkerStallTimeout
 	
 	// Dependency injection
-	taskRepo := repository.NewPostgresTaskRepository(db)
-	taskService := service.NewTaskService(taskRepo)
+	taskRepoOptions := []repository.PostgresTaskRepositoryOption{repository.WithOutbox(), repository.WithQueryTimeout(cfg.Database.QueryTimeout)}
+	// Task reads go to the streaming replicas in DB_REPLICA_HOSTS, if any, with clients
+	// reading their own writes for READ_AFTER_WRITE_WINDOW
+	var replicas *database.ReplicaSet
+	if len(cfg.Database.ReplicaHosts) > 0 {
+		if replicas, err = database.NewReplicaSet(db, cfg.Database, database.DefaultReplicaSetConfig()); err != nil {
+			log.Fatal("Failed to set up replicas:", err)
+		}
+		defer replicas.Close()
+		replicas.Start()
+		defer replicas.Stop()
+		taskRepoOptions = append(taskRepoOptions, repository.WithReplicas(replicas))
+		log.Printf("Reading tasks from %d replicas", len(cfg.Database.ReplicaHosts))
+	}
+	taskRepo := repository.NewPostgresTaskRepository(db, taskRepoOptions...)
+	cachedTaskRepo, purgeTaskCache := setupTaskCache(taskRepo, cfg.Cache)
+	if cachedTaskRepo != nil {
+		defer cachedTaskRepo.Close()
+		taskRepo = cachedTaskRepo
+	}
+	// Units of work see the same task repository, in a transaction, and drop what they wrote from cache
+	txManager := repository.NewTxManager(db, repository.DefaultTxManagerConfig(),
+		repository.WithTaskRepositoryOptions(taskRepoOptions...),
+		repository.WithTaskCache(cachedTaskRepo),
+		repository.WithReadYourWrites(replicas),
+	)
+	fieldRepo := repository.NewPostgresCustomFieldRepository(db)
+	boardRepo := repository.NewPostgresBoardRepository(db)
+	userRepo := repository.NewPostgresUserRepository(db)
+	watcherRepo := repository.NewPostgresWatcherRepository(db)
+	notificationRepo := repository.NewPostgresNotificationRepository(db)
+	mentionRepo := repository.NewPostgresMentionRepository(db)
+	webhookRepo := repository.NewPostgresWebhookRepository(db)
+	outboxRepo := repository.NewPostgresOutboxRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, watcherRepo)
+	webhookTargets := service.WebhookTargetPolicy{AllowPrivate: cfg.Webhooks.AllowPrivateTargets}
+	webhookService := service.NewWebhookService(webhookRepo, webhookTargets)
+	webhookDispatcherConfig := service.DefaultWebhookDispatcherConfig()
+	webhookDispatcherConfig.Targets = webhookTargets
+	webhookDispatcher := service.NewWebhookDispatcher(webhookRepo, webhookDispatcherConfig)
+	webhookDispatcher.Start()
+	defer webhookDispatcher.Stop()
+	checks.AddLivenessCheck("webhook_dispatcher", webhookDispatcher.Heartbeat().Checker(workerStallTimeout))
+	// Task events are written to the outbox with each change and relayed from there
+	outboxRelay := service.NewOutboxRelay(outboxRepo, service.DefaultOutboxRelayConfig(), notificationService, webhookService)
+	outboxRelay.Start()
+	defer outboxRelay.Stop()
+	checks.AddLivenessCheck("outbox_relay", outboxRelay.Heartbeat().Checker(workerStallTimeout))
+	// Outbox inserts are announced with NOTIFY so every replica can stream them
+	taskStreamBroker := service.NewTaskStreamBroker(outboxRepo)
+	if err := taskStreamBroker.Start(); err != nil {
+		log.Fatal("Failed to start task stream:", err)
+	}
+	// One LISTEN connection per instance, shared by everything that reacts to NOTIFY
+	notificationBus, err := database.NewNotificationBus(database.ConnectionString(cfg.Database), database.DefaultNotificationBusConfig())
+	if err != nil {
+		log.Fatal("Failed to start notification bus:", err)
+	}
+	defer notificationBus.Close()
+	notificationBus.Subscribe(repository.OutboxChannel, taskStreamBroker.Notify, taskStreamBroker.Resync)
+	if cachedTaskRepo != nil {
+		notificationBus.Subscribe(database.TaskChangesChannel, cachedTaskRepo.HandleTaskChange, purgeTaskCache)
+	}
+	automationRepo := repository.NewPostgresAutomationRepository(db)
+	attachmentRepo := repository.NewPostgresAttachmentRepository(db)
+	automationEngine := service.NewAutomationEngine(automationRepo, webhookRepo)
+	rankRebalancer := service.NewRankRebalancer(taskRepo)
+	rankRebalancer.Start()
+	defer rankRebalancer.Stop()
+	taskService := service.NewTaskService(taskRepo,
+		service.WithCustomFields(fieldRepo),
+		service.WithRankRebalancer(rankRebalancer),
+		service.WithMentions(userRepo, mentionRepo),
+		service.WithAutomation(automationEngine),
+		service.WithTransactions(txManager),
+	)
+	fieldService := service.NewCustomFieldService(fieldRepo)
+	boardService := service.NewBoardService(taskRepo, boardRepo)
+	userService := service.NewUserService(userRepo)
+	watcherService := service.NewWatcherService(taskRepo, watcherRepo)
+	mentionService := service.NewMentionService(mentionRepo)
+	automationService := service.NewAutomationService(automationRepo, webhookRepo, automationEngine)
+	attachmentService := service.NewAttachmentService(taskRepo, attachmentRepo)
 	taskHandler := handlers.NewTaskHandler(taskService)
+	fieldHandler := handlers.NewCustomFieldHandler(fieldService)
+	boardHandler := handlers.NewBoardHandler(boardService)
+	userHandler := handlers.NewUserHandler(userService)
+	watcherHandler := handlers.NewWatcherHandler(watcherService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	mentionHandler := handlers.NewMentionHandler(mentionService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	automationHandler := handlers.NewAutomationHandler(automationService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+
+	// Inbound email creates and updates tasks when INBOUND_SMTP_ADDR is set
+	replyTokens := email.NewReplyTokens(cfg.Email.ReplySecret)
+	if cfg.Email.SMTPAddr != "" {
+		inboundEmail := service.NewInboundEmailService(taskService, attachmentRepo, replyTokens, cfg.Email.AllowedSenders)
+		smtpConfig := email.DefaultServerConfig()
+		smtpConfig.Addr = cfg.Email.SMTPAddr
+		smtpConfig.Hostname = cfg.Email.SMTPHostname
+		smtpConfig.AcceptDomains = cfg.Email.Domains
+		smtpServer := email.NewServer(smtpConfig, inboundEmail)
+		if err := smtpServer.Start(); err != nil {
+			log.Fatal("Failed to start SMTP listener:", err)
+		}
+		defer smtpServer.Stop()
+		log.Println("Receiving email on " + smtpServer.Addr())
+	}
+	// Email digests; preferences can be saved even while no sender is configured
+	digestRepo := repository.NewPostgresDigestRepository(db)
+	digestConfig, digestSender, err := setupDigests(cfg.Digest)
+	if err != nil {
+		log.Fatal("Failed to set up digests:", err)
+	}
+	digestService := service.NewDigestService(digestRepo, digestConfig.Schedule)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	if digestSender != nil {
+		digestScheduler := service.NewDigestScheduler(digestRepo, digestSender, digestConfig)
+		digestScheduler.Start()
+		defer digestScheduler.Stop()
+		checks.AddLivenessCheck("digest_scheduler", digestScheduler.Heartbeat().Checker(workerStallTimeout))
+	}
+	slashCommandService := service.NewSlashCommandService(taskService)
+	slashCommandHandler := handlers.NewSlashCommandHandler(slashCommandService, handlers.DefaultSlashCommandHandlerConfig())
+	taskStreamHandler := handlers.NewTaskStreamHandler(taskStreamBroker)
+	webSocketHandler := handlers.NewWebSocketHandler(taskService, taskStreamBroker, cfg.Server.RequestTimeout)
+	dbStatsHandler := handlers.NewDatabaseStatsHandler(db, replicas)
 	
 	// Router setup
-	router := setupRoutes(taskHandler)
+	router := setupRoutes(cfg, replicas != nil, checks, taskHandler, taskStreamHandler, webSocketHandler, fieldHandler, boardHandler, userHandler, watcherHandler, notificationHandler, mentionHandler, webhookHandler, automationHandler, attachmentHandler, digestHandler, slashCommandHandler, dbStatsHandler, userService)
 
-	port := utils.GetEnv("APP_PORT", "8080")
-	log.Println("Starting server on :" + port)
-	router.Run(":" + port)
+	// Deferred calls then stop the background workers, newest first, and close the
+	// database pool last
+	serve(cfg.Server, router, checks, taskStreamBroker.Close)
 }
 
-func setupRoutes(taskHandler handlers.TaskHandlerInterface) *gin.Engine {
-	router := gin.Default()
-
-	// error handling middleware
-	router.Use(middleware.RecoveryMiddleware())
-	router.Use(middleware.ErrorMiddleware())
-	
-	// Health check endpoint
-	router.GET("/health", handlers.HealthCheck)
+func setupRoutes(
+	cfg config.Config,
+	readFromReplicas bool,
+	checks *health.Registry,
+	taskHandler handlers.TaskHandlerInterface,
+	taskStreamHandler handlers.TaskStreamHandlerInterface,
+	webSocketHandler handlers.WebSocketHandlerInterface,
+	fieldHandler handlers.CustomFieldHandlerInterface,
+	boardHandler handlers.BoardHandlerInterface,
+	userHandler handlers.UserHandlerInterface,
+	watcherHandler handlers.WatcherHandlerInterface,
+	notificationHandler handlers.NotificationHandlerInterface,
+	mentionHandler handlers.MentionHandlerInterface,
+	webhookHandler handlers.WebhookHandlerInterface,
+	automationHandler handlers.AutomationHandlerInterface,
+	attachmentHandler handlers.AttachmentHandlerInterface,
+	digestHandler handlers.DigestHandlerInterface,
+	slashCommandHandler handlers.SlashCommandHandlerInterface,
+	dbStatsHandler handlers.DatabaseStatsHandlerInterface,
+	auth middleware.TokenAuthenticator,
+) *gin.Engine {
+	router := newRouter(checks)
+	if readFromReplicas {
+		router.Use(middleware.ReadYourWrites(cfg.Database))
+	}
 	
+	// Streams stay open for as long as clients listen, so they get no request deadline
+	streams := router.Group("/api/v1")
+	{
+		streams.GET("/tasks/stream", append(middleware.ValidateTaskStreamQuery(), taskStreamHandler.Stream)...)
+
+		// Board clients authenticate when upgrading; browsers pass the token as a subprotocol
+		streams.GET("/ws", middleware.AuthenticateWebSocket(auth), webSocketHandler.Connect)
+	}
+
 	// API v1 routes
-	v1 := router.Group("/api/v1")
+	v1 := router.Group("/api/v1", middleware.RequestDeadline(cfg.Server))
 	{
 		// Task routes
 		tasks := v1.Group("/tasks")
 		{
-			tasks.POST("", append(middleware.ValidateCreateTaskBody(), taskHandler.CreateTask)...)   
-			tasks.GET("/:id", append(middleware.ValidateTaskID(), taskHandler.GetTask)...)          
-			tasks.GET("", append(middleware.ValidateTaskQuery(), taskHandler.GetAllTasks)...)        
-			tasks.PUT("/:id", append(
-				append(middleware.ValidateTaskID(), middleware.ValidateUpdateTaskBody()...), 
-					taskHandler.UpdateTask,
-				)...)
-			tasks.DELETE("/:id", append(middleware.ValidateTaskID(), taskHandler.DeleteTask)...)  
+			setupTaskRoutes(tasks, taskHandler)
+
+			// Watching requires a user
+			tasks.POST("/:id/watch", append(
+				append(middleware.ValidateTaskID(), middleware.Authenticate(auth)),
+				watcherHandler.Watch,
+			)...)
+			tasks.DELETE("/:id/watch", append(
+				append(middleware.ValidateTaskID(), middleware.Authenticate(auth)),
+				watcherHandler.Unwatch,
+			)...)
+			tasks.GET("/:id/watchers", append(
+				append(middleware.ValidateTaskID(), middleware.Authenticate(auth)),
+				watcherHandler.GetWatchers,
+			)...)
+			tasks.GET("/:id/attachments", append(middleware.ValidateTaskID(), attachmentHandler.GetAttachments)...)
+			tasks.GET("/:id/attachments/:attachmentId", append(middleware.ValidateAttachmentParams(), attachmentHandler.DownloadAttachment)...)
+		}
+
+		// Kanban board routes
+		board := v1.Group("/board")
+		{
+			board.GET("", append(middleware.ValidateBoardQuery(), boardHandler.GetBoard)...)
+			board.PUT("/columns/:status", append(middleware.ValidateUpdateBoardColumn(), boardHandler.UpdateColumn)...)
+		}
+
+		// User routes
+		v1.POST("/users", append(middleware.ValidateCreateUserBody(), userHandler.CreateUser)...)
+
+		// Routes for the authenticated user
+		me := v1.Group("/me", middleware.Authenticate(auth))
+		{
+			me.GET("", userHandler.GetCurrentUser)
+			me.GET("/notifications", append(middleware.ValidateNotificationQuery(), notificationHandler.GetNotifications)...)
+			me.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+			me.POST("/notifications/:id/read", append(middleware.ValidateNotificationID(), notificationHandler.MarkRead)...)
+			me.GET("/mentions", append(middleware.ValidateMentionQuery(), mentionHandler.GetMentions)...)
+			me.GET("/digest-preferences", digestHandler.GetPreferences)
+			me.PUT("/digest-preferences", append(middleware.ValidateUpdateDigestPreferencesBody(), digestHandler.UpdatePreferences)...)
+		}
+
+		// Webhook subscription routes; webhooks send task data out, so only users manage them
+		webhooks := v1.Group("/webhooks", middleware.Authenticate(auth))
+		{
+			webhooks.POST("", append(middleware.ValidateCreateWebhookBody(), webhookHandler.CreateWebhook)...)
+			webhooks.GET("", webhookHandler.GetAllWebhooks)
+			webhooks.GET("/:id", append(middleware.ValidateWebhookID(), webhookHandler.GetWebhook)...)
+			webhooks.DELETE("/:id", append(middleware.ValidateWebhookID(), webhookHandler.DeleteWebhook)...)
+			webhooks.GET("/:id/deliveries", append(
+				append(middleware.ValidateWebhookID(), middleware.ValidateWebhookDeliveryQuery()...),
+				webhookHandler.GetDeliveries,
+			)...)
+		}
+
+		// Automation rule routes; rules change tasks and fire webhooks, so only users manage them
+		rules := v1.Group("/automation-rules", middleware.Authenticate(auth))
+		{
+			rules.POST("", append(middleware.ValidateCreateAutomationRuleBody(), automationHandler.CreateRule)...)
+			rules.GET("", automationHandler.GetAllRules)
+			rules.POST("/dry-run", append(middleware.ValidateAutomationDryRunBody(), automationHandler.DryRun)...)
+			rules.GET("/:id", append(middleware.ValidateAutomationRuleID(), automationHandler.GetRule)...)
+			rules.DELETE("/:id", append(middleware.ValidateAutomationRuleID(), automationHandler.DeleteRule)...)
+		}
+
+		// Chat integrations authenticate with request signatures instead of API tokens
+		integrations := v1.Group("/integrations")
+		{
+			integrations.POST("/slash", append(
+				append([]gin.HandlerFunc{middleware.VerifySlackSignature(cfg.Slack)}, middleware.ValidateSlashCommand()...),
+				slashCommandHandler.Handle,
+			)...)
+		}
+
+		// Custom field definition routes; creating and deleting a field changes the tasks
+		// table's indexes, so only users do it
+		fields := v1.Group("/custom-fields")
+		{
+			fields.POST("", append(
+				append(middleware.ValidateCreateCustomFieldBody(), middleware.Authenticate(auth)),
+				fieldHandler.CreateField,
+			)...)
+			fields.GET("", fieldHandler.GetAllFields)
+			fields.GET("/:id", append(middleware.ValidateCustomFieldID(), fieldHandler.GetField)...)
+			fields.DELETE("/:id", append(
+				append(middleware.ValidateCustomFieldID(), middleware.Authenticate(auth)),
+				fieldHandler.DeleteField,
+			)...)
 		}
 	}	
+
+	// Operator endpoints, served once an admin token is configured
+	if cfg.Admin.Token != "" {
+		admin := router.Group("/admin", middleware.RequireAdminToken(cfg.Admin))
+		{
+			admin.GET("/db/stats", dbStatsHandler.GetStats)
+		}
+	}
+	return router
+}
+
+// Creates the router with the middleware and health probes every backend shares
+func newRouter(checks *health.Registry) *gin.Engine {
+	router := gin.New()
+	router.Use(middleware.RequestLogger(), gin.Recovery())
+
+	// error handling middleware
+	router.Use(middleware.RecoveryMiddleware())
+	router.Use(middleware.ErrorMiddleware())
+
+	// Liveness and readiness probes; add ?verbose for each check's result
+	healthHandler := handlers.NewHealthHandler(checks)
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
 	return router
-}
\ No newline at end of file
+}
+
+// Registers task CRUD and moves, the routes every backend serves
+func setupTaskRoutes(tasks *gin.RouterGroup, taskHandler handlers.TaskHandlerInterface) {
+	tasks.POST("", append(middleware.ValidateCreateTaskBody(), taskHandler.CreateTask)...)
+	tasks.GET("/:id", append(middleware.ValidateTaskID(), taskHandler.GetTask)...)
+	tasks.GET("", append(middleware.ValidateTaskQuery(), taskHandler.GetAllTasks)...)
+	tasks.PUT("/:id", append(
+		append(middleware.ValidateTaskID(), middleware.ValidateUpdateTaskBody()...),
+		taskHandler.UpdateTask,
+	)...)
+	tasks.DELETE("/:id", append(middleware.ValidateTaskID(), taskHandler.DeleteTask)...)
+	tasks.POST("/:id/move", append(
+		append(middleware.ValidateTaskID(), middleware.ValidateMoveTaskBody()...),
+		taskHandler.MoveTask,
+	)...)
+}
+
+// Puts a cache in front of task lookups as selected by TASK_CACHE: "none", "memory" or
+// "redis". Returns nil when caching is off, and for the in-memory cache a purge function
+// to run when change notifications may have been missed.
+func setupTaskCache(taskRepo repository.TaskRepository, cacheConfig config.CacheConfig) (*repository.CachedTaskRepository, func()) {
+	repoConfig := repository.DefaultCachedTaskRepositoryConfig()
+	repoConfig.TTL = cacheConfig.TTL
+	repoConfig.NegativeTTL = cacheConfig.NegativeTTL
+
+	switch cacheConfig.Backend {
+	case "memory":
+		lru := cache.NewLRUCache(cacheConfig.Size)
+		log.Printf("Caching task lookups in memory (%d entries)", cacheConfig.Size)
+		return repository.NewCachedTaskRepository(taskRepo, lru, repoConfig), lru.Purge
+	case "redis":
+		redisConfig := cache.DefaultRedisConfig()
+		redisConfig.Addr = cacheConfig.RedisAddr
+		redisConfig.Password = cacheConfig.RedisPassword
+		redisConfig.DB = cacheConfig.RedisDB
+		log.Printf("Caching task lookups in Redis at %s", redisConfig.Addr)
+		// Shared by all instances, so writers' own invalidations keep it current
+		return repository.NewCachedTaskRepository(taskRepo, cache.NewRedisCache(redisConfig), repoConfig), nil
+	default:
+		return nil, nil
+	}
+}
+
+// Builds the digest settings and picks the sender selected by DIGEST_SENDER: "none",
+// "smtp" (through SMTP_ADDR) or "capture" (.eml files in DIGEST_CAPTURE_DIR). The
+// sender is nil when digests are not sent.
+func setupDigests(digestConfig config.DigestConfig) (service.DigestSchedulerConfig, email.Sender, error) {
+	schedulerConfig := service.DefaultDigestSchedulerConfig()
+	schedulerConfig.Schedule.Hour = digestConfig.Hour
+	from, err := mail.ParseAddress(digestConfig.From)
+	if err != nil {
+		return schedulerConfig, nil, err
+	}
+	schedulerConfig.From = *from
+	schedulerConfig.DueField = digestConfig.DueField
+
+	switch digestConfig.Sender {
+	case "smtp":
+		smtpConfig := email.SMTPConfig{
+			Addr:     digestConfig.SMTPAddr,
+			Username: digestConfig.SMTPUsername,
+			Password: digestConfig.SMTPPassword,
+		}
+		log.Printf("Sending digests through %s", smtpConfig.Addr)
+		return schedulerConfig, email.NewSMTPSender(smtpConfig), nil
+	case "capture":
+		sender, err := email.NewCaptureSender(digestConfig.CaptureDir)
+		if err != nil {
+			return schedulerConfig, nil, err
+		}
+		log.Printf("Writing digests to %s instead of sending them", digestConfig.CaptureDir)
+		return schedulerConfig, sender, nil
+	default:
+		return schedulerConfig, nil, nil
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+	"github.com/AashishRichhariya/task-management-api/internal/database"
+)
+
+const migrateUsage = "usage: server migrate up | down [steps] | status"
+
+// Runs "migrate up", "migrate down [steps]" (one step by default) or "migrate status"
+func runMigrateCommand(migrator *database.Migrator, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migrations\n", len(applied))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive integer, got '%s'", args[1])
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %d migrations\n", len(reverted))
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%03d  %-45s %s\n", status.Version, status.Name, applied)
+		}
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+	return nil
+}
+
+// Applies pending migrations at startup unless MIGRATE_ON_START is false
+func migrateOnStart(migrator *database.Migrator, dbConfig config.DatabaseConfig) error {
+	if !dbConfig.MigrateOnStart {
+		return nil
+	}
+	_, err := migrator.Up()
+	return err
+}
+
+// Handles "server migrate ..." if that is what was run, reporting whether it was
+func migrateCommand(migrator *database.Migrator) bool {
+	if len(os.Args) < 2 || os.Args[1] != "migrate" {
+		return false
+	}
+	if err := runMigrateCommand(migrator, os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+	"github.com/AashishRichhariya/task-management-api/internal/health"
+)
+
+// Serves handler until SIGINT or SIGTERM, then shuts down gracefully: readiness fails
+// for the drain period so load balancers stop sending requests, the listener closes,
+// and in-flight requests get the shutdown timeout to finish. onShutdown functions run
+// as the listener closes, to end long-lived streams. The caller's deferred calls stop
+// background workers and close the database once serve returns.
+func serve(config config.ServerConfig, handler http.Handler, checks *health.Registry, onShutdown ...func()) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr:         ":" + config.Port,
+		Handler:      handler,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+		IdleTimeout:  config.IdleTimeout,
+	}
+	for _, f := range onShutdown {
+		server.RegisterOnShutdown(f)
+	}
+
+	failed := make(chan struct{})
+	go func() {
+		log.Println("Starting server on :" + config.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Println("Server failed:", err)
+			close(failed)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // A second signal kills the process
+	select {
+	case <-failed:
+		return
+	default:
+	}
+
+	log.Printf("Shutting down; draining for %v", config.DrainPeriod)
+	checks.Drain()
+	time.Sleep(config.DrainPeriod)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to finish in-flight requests:", err)
+	}
+}
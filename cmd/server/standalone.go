@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/internal/handlers"
+	"github.com/AashishRichhariya/task-management-api/internal/health"
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	sqlitemigrations "github.com/AashishRichhariya/task-management-api/migrations/sqlite"
+)
+
+// Serves tasks from the SQLite file at SQLITE_PATH
+func runSQLiteServer(cfg config.Config) {
+	path := cfg.Database.SQLitePath
+	db, err := database.NewSQLiteConnection(path)
+	if err != nil {
+		log.Fatal("Failed to open database:", err)
+	}
+	defer db.Close()
+
+	// "server migrate ..." manages the schema and exits
+	migrator, err := database.NewSQLiteMigrator(db, sqlitemigrations.FS)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+	if migrateCommand(migrator) {
+		return
+	}
+	if err := migrateOnStart(migrator, cfg.Database); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	checks := health.NewRegistry(cfg.Health.CheckTimeout)
+	checks.AddReadinessCheck("database", health.DatabaseChecker(db))
+	checks.AddReadinessCheck("migrations", health.CheckerFunc(migrator.CheckAtHead))
+
+	log.Printf("Storing tasks in SQLite database %s", path)
+	runStandaloneServer(cfg, repository.NewSQLiteTaskRepository(db), checks)
+}
+
+// Serves tasks from memory. With MEMORY_SNAPSHOT_PATH set they are loaded from that
+// JSON file at startup and written back to it on shutdown.
+func runMemoryServer(cfg config.Config) {
+	taskRepo := repository.NewMemoryTaskRepository()
+	path := cfg.Database.MemorySnapshotPath
+	if path != "" {
+		if err := taskRepo.LoadSnapshot(path); err != nil {
+			log.Fatal("Failed to load tasks:", err)
+		}
+		defer func() {
+			if err := taskRepo.SaveSnapshot(path); err != nil {
+				log.Println("Failed to save tasks:", err)
+				return
+			}
+			log.Printf("Saved tasks to %s", path)
+		}()
+	}
+
+	log.Println("Storing tasks in memory")
+	runStandaloneServer(cfg, taskRepo, health.NewRegistry(cfg.Health.CheckTimeout))
+}
+
+// Serves task CRUD and moves from a backend that only stores tasks, until SIGINT or
+// SIGTERM. Everything built on other tables or on Postgres notifications (boards, users,
+// custom fields, webhooks, streaming, ...) needs the Postgres backend.
+func runStandaloneServer(cfg config.Config, taskRepo repository.TaskRepository, checks *health.Registry) {
+	rankRebalancer := service.NewRankRebalancer(taskRepo)
+	rankRebalancer.Start()
+	defer rankRebalancer.Stop()
+	taskService := service.NewTaskService(taskRepo, service.WithRankRebalancer(rankRebalancer))
+	taskHandler := handlers.NewTaskHandler(taskService)
+
+	router := newRouter(checks)
+	setupTaskRoutes(router.Group("/api/v1/tasks", middleware.RequestDeadline(cfg.Server)), taskHandler)
+
+	serve(cfg.Server, router, checks)
+}
@@ -0,0 +1,14 @@
+package cache
+
+import "time"
+
+// Cache stores opaque values under string keys, each with its own time to live.
+// Implementations are safe for concurrent use.
+type Cache interface {
+	// Reports false for keys that are missing or expired
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	// Stores the value only if the key is missing or expired, and reports whether it did
+	SetIfAbsent(key string, value []byte, ttl time.Duration) (bool, error)
+	Delete(keys ...string) error
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// In-process server speaking enough RESP for RedisCache: AUTH, SELECT, PING, GET, SET (PX/EX/NX) and DEL
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu     sync.Mutex
+	values map[string]fakeRedisValue
+}
+
+type fakeRedisValue struct {
+	data      string
+	expiresAt time.Time
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start fake redis: %v", err)
+	}
+	server := &fakeRedis{listener: listener, password: password, values: make(map[string]fakeRedisValue)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeRedis) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+
+	for {
+		reply, err := readRESPReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			data, _ := item.([]byte)
+			args[i] = string(data)
+		}
+		if len(args) == 0 {
+			fmt.Fprint(conn, "-ERR empty command\r\n")
+			continue
+		}
+
+		command := strings.ToUpper(args[0])
+		if !authenticated && command != "AUTH" {
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+		switch command {
+		case "AUTH":
+			if len(args) != 2 || args[1] != s.password {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+				continue
+			}
+			authenticated = true
+			fmt.Fprint(conn, "+OK\r\n")
+		case "SELECT", "PING":
+			fmt.Fprint(conn, "+OK\r\n")
+		default:
+			fmt.Fprint(conn, s.execute(command, args[1:]))
+		}
+	}
+}
+
+func (s *fakeRedis) execute(command string, args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch command {
+	case "GET":
+		value, ok := s.values[args[0]]
+		if !ok || !time.Now().Before(value.expiresAt) {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value.data), value.data)
+	case "SET":
+		ttl, onlyIfAbsent := 24*time.Hour, false
+		for i := 2; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "PX", "EX":
+				n, _ := strconv.Atoi(args[i+1])
+				ttl = time.Duration(n) * time.Millisecond
+				if strings.ToUpper(args[i]) == "EX" {
+					ttl = time.Duration(n) * time.Second
+				}
+				i++
+			case "NX":
+				onlyIfAbsent = true
+			}
+		}
+		if existing, ok := s.values[args[0]]; onlyIfAbsent && ok && time.Now().Before(existing.expiresAt) {
+			return "$-1\r\n"
+		}
+		s.values[args[0]] = fakeRedisValue{data: args[1], expiresAt: time.Now().Add(ttl)}
+		return "+OK\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args {
+			if _, ok := s.values[key]; ok {
+				delete(s.values, key)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", command)
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+	c.Get("a") // b is now the least recently used
+	c.Set("c", []byte("3"), time.Minute)
+
+	_, ok, _ := c.Get("b")
+	assert.False(t, ok)
+	value, ok, _ := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(value))
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRUCache_Expiry(t *testing.T) {
+	now := time.Now()
+	c := NewLRUCache(10)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", []byte("1"), time.Minute)
+	now = now.Add(time.Minute)
+
+	_, ok, _ := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRUCache_DeleteAndPurge(t *testing.T) {
+	c := NewLRUCache(10)
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+
+	c.Delete("a", "missing")
+	_, ok, _ := c.Get("a")
+	assert.False(t, ok)
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRUCache_SetIfAbsent(t *testing.T) {
+	now := time.Now()
+	c := NewLRUCache(10)
+	c.now = func() time.Time { return now }
+
+	stored, _ := c.SetIfAbsent("a", []byte("1"), time.Minute)
+	assert.True(t, stored)
+	stored, _ = c.SetIfAbsent("a", []byte("2"), time.Minute)
+	assert.False(t, stored)
+
+	// An expired entry counts as absent
+	now = now.Add(time.Minute)
+	stored, _ = c.SetIfAbsent("a", []byte("3"), time.Minute)
+	assert.True(t, stored)
+	value, _, _ := c.Get("a")
+	assert.Equal(t, "3", string(value))
+	assert.Equal(t, 1, c.Len())
+}
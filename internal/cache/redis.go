@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Cache backed by a Redis server (or anything speaking RESP2), shared by every instance.
+// Connections are opened on demand and up to PoolSize idle ones are kept.
+type RedisCache struct {
+	config RedisConfig
+	idle   chan *redisConn
+}
+
+type RedisConfig struct {
+	Addr        string
+	Password    string
+	DB          int
+	PoolSize    int
+	DialTimeout time.Duration
+	IOTimeout   time.Duration // Deadline for each command round trip
+}
+
+func DefaultRedisConfig() RedisConfig {
+	return RedisConfig{
+		Addr:        "localhost:6379",
+		PoolSize:    10,
+		DialTimeout: 2 * time.Second,
+		IOTimeout:   time.Second,
+	}
+}
+
+// An error reply from the server. The connection stays usable after one.
+type RedisError struct {
+	Message string
+}
+
+func (e RedisError) Error() string {
+	return "redis: " + e.Message
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+func NewRedisCache(config RedisConfig) *RedisCache {
+	return &RedisCache{
+		config: config,
+		idle:   make(chan *redisConn, max(config.PoolSize, 1)),
+	}
+}
+
+func (c *RedisCache) Get(key string) ([]byte, bool, error) {
+	reply, err := c.do("GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return value, true, nil
+}
+
+func (c *RedisCache) Set(key string, value []byte, ttl time.Duration) error {
+	_, err := c.do("SET", key, string(value), "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	return err
+}
+
+func (c *RedisCache) SetIfAbsent(key string, value []byte, ttl time.Duration) (bool, error) {
+	reply, err := c.do("SET", key, string(value), "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10), "NX")
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil // A null reply when the key was already set
+}
+
+func (c *RedisCache) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := c.do(append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// Closes the idle connections
+func (c *RedisCache) Close() error {
+	for {
+		select {
+		case rc := <-c.idle:
+			rc.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// Sends one command and reads its reply. Replies are decoded as string (simple string),
+// int64, []byte (bulk string, nil when null) or []any.
+func (c *RedisCache) do(args ...string) (any, error) {
+	rc, err := c.acquire()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := rc.roundTrip(c.config.IOTimeout, args)
+	var redisErr RedisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// The connection is in an unknown state after a network or protocol error
+		rc.conn.Close()
+		return nil, err
+	}
+	c.release(rc)
+	return reply, err
+}
+
+func (c *RedisCache) acquire() (*redisConn, error) {
+	select {
+	case rc := <-c.idle:
+		return rc, nil
+	default:
+	}
+
+	conn, err := net.DialTimeout("tcp", c.config.Addr, c.config.DialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("redis: failed to connect: %w", err)
+	}
+	rc := &redisConn{conn: conn, reader: bufio.NewReader(conn), writer: bufio.NewWriter(conn)}
+
+	if c.config.Password != "" {
+		if _, err := rc.roundTrip(c.config.IOTimeout, []string{"AUTH", c.config.Password}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if c.config.DB != 0 {
+		if _, err := rc.roundTrip(c.config.IOTimeout, []string{"SELECT", strconv.Itoa(c.config.DB)}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return rc, nil
+}
+
+func (c *RedisCache) release(rc *redisConn) {
+	select {
+	case c.idle <- rc:
+	default:
+		rc.conn.Close()
+	}
+}
+
+func (rc *redisConn) roundTrip(timeout time.Duration, args []string) (any, error) {
+	rc.conn.SetDeadline(time.Now().Add(timeout))
+	if err := writeRESPCommand(rc.writer, args); err != nil {
+		return nil, err
+	}
+	if err := rc.writer.Flush(); err != nil {
+		return nil, err
+	}
+	return readRESPReply(rc.reader)
+}
+
+// Commands are sent as an array of bulk strings
+func writeRESPCommand(w *bufio.Writer, args []string) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n", len(arg))
+		w.WriteString(arg)
+		if _, err := w.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readRESPReply(r *bufio.Reader) (any, error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("redis: empty reply line")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, RedisError{Message: line[1:]}
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed integer reply %q", line)
+		}
+		return n, nil
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk string length %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2) // Including the trailing CRLF
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", line)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]any, count)
+		for i := range items {
+			if items[i], err = readRESPReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+// Reads a line without its CRLF terminator
+func readRESPLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redis: malformed line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
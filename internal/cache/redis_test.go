@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisCache(t *testing.T, server *fakeRedis, password string) *RedisCache {
+	config := DefaultRedisConfig()
+	config.Addr = server.Addr()
+	config.Password = password
+	config.DB = 2
+	c := NewRedisCache(config)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestRedisCache_SetGetDelete(t *testing.T) {
+	server := newFakeRedis(t, "hunter2")
+	c := newTestRedisCache(t, server, "hunter2")
+
+	_, ok, err := c.Get("task:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.Set("task:1", []byte("line one\r\nline two"), time.Minute))
+	value, ok, err := c.Get("task:1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "line one\r\nline two", string(value))
+
+	require.NoError(t, c.Delete("task:1", "task:2"))
+	_, ok, err = c.Get("task:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedisCache_SetIfAbsent(t *testing.T) {
+	server := newFakeRedis(t, "")
+	c := newTestRedisCache(t, server, "")
+
+	stored, err := c.SetIfAbsent("task:1", []byte("first"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, stored)
+
+	stored, err = c.SetIfAbsent("task:1", []byte("second"), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, stored)
+
+	value, _, err := c.Get("task:1")
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(value))
+}
+
+func TestRedisCache_Expiry(t *testing.T) {
+	server := newFakeRedis(t, "")
+	c := newTestRedisCache(t, server, "")
+
+	require.NoError(t, c.Set("task:1", []byte("{}"), 20*time.Millisecond))
+	time.Sleep(40 * time.Millisecond)
+
+	_, ok, err := c.Get("task:1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedisCache_ErrorReplies(t *testing.T) {
+	server := newFakeRedis(t, "hunter2")
+
+	_, _, err := newTestRedisCache(t, server, "wrong").Get("task:1")
+	var redisErr RedisError
+	assert.ErrorAs(t, err, &redisErr)
+
+	// An error reply leaves the pooled connection usable
+	c := newTestRedisCache(t, server, "hunter2")
+	_, err = c.do("BOGUS")
+	assert.ErrorAs(t, err, &redisErr)
+	require.NoError(t, c.Set("task:1", []byte("ok"), time.Minute))
+	value, _, err := c.Get("task:1")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(value))
+}
+
+func TestRedisCache_Unreachable(t *testing.T) {
+	config := DefaultRedisConfig()
+	config.Addr = "127.0.0.1:1"
+	config.DialTimeout = 100 * time.Millisecond
+
+	_, _, err := NewRedisCache(config).Get("task:1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+package config
+
+import "time"
+
+// Config is every setting the server reads at startup. Each field has a default, can
+// be set in the YAML file named by CONFIG_FILE under its yaml key, and can be
+// overridden by the environment variable in its env tag. Fields tagged secret are
+// redacted when the config is printed.
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Health   HealthConfig   `yaml:"health"`
+	Cache    CacheConfig    `yaml:"cache"`
+	Email    EmailConfig    `yaml:"email"`
+	Digest   DigestConfig   `yaml:"digest"`
+	Webhooks WebhookConfig  `yaml:"webhooks"`
+	Slack    SlackConfig    `yaml:"slack"`
+	Admin    AdminConfig    `yaml:"admin"`
+}
+
+type ServerConfig struct {
+	Port         string        `yaml:"port" env:"APP_PORT"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`   // For the whole request, body included
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"` // From the end of the request headers to the end of the response
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`   // Between requests on a kept-alive connection
+
+	// Deadline for all the queries one API request makes; 0 for no limit. Streams are exempt.
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"HTTP_REQUEST_TIMEOUT"`
+
+	DrainPeriod     time.Duration `yaml:"drain_period" env:"SHUTDOWN_DRAIN_PERIOD"` // How long readiness fails before the listener closes
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`  // How long in-flight requests get to finish after that
+}
+
+type DatabaseConfig struct {
+	Backend            string `yaml:"backend" env:"DB_BACKEND"` // postgres, sqlite or memory
+	SQLitePath         string `yaml:"sqlite_path" env:"SQLITE_PATH"`
+	MemorySnapshotPath string `yaml:"memory_snapshot_path" env:"MEMORY_SNAPSHOT_PATH"` // Tasks are kept in memory only when empty
+
+	// A postgres:// URL replaces the discrete connection settings below
+	URL         string `yaml:"url" env:"DATABASE_URL" secret:"true"`
+	Host        string `yaml:"host" env:"DB_HOST"`
+	Port        string `yaml:"port" env:"DB_PORT"`
+	User        string `yaml:"user" env:"DB_USER"`
+	Password    string `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name        string `yaml:"name" env:"DB_NAME"`
+	SSLMode     string `yaml:"sslmode" env:"DB_SSLMODE"`
+	SSLRootCert string `yaml:"sslrootcert" env:"DB_SSLROOTCERT"`
+	SSLCert     string `yaml:"sslcert" env:"DB_SSLCERT"`
+	SSLKey      string `yaml:"sslkey" env:"DB_SSLKEY"`
+
+	// Pool limits, applied to the primary and each replica; zero leaves database/sql's defaults
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+
+	ConnectTimeout time.Duration `yaml:"connect_timeout" env:"DB_CONNECT_TIMEOUT"` // How long startup retries while the database is unreachable
+	QueryTimeout   time.Duration `yaml:"query_timeout" env:"DB_QUERY_TIMEOUT"`     // Optional cap per task query; 0 for none
+
+	ReplicaHosts         []string      `yaml:"replica_hosts" env:"DB_REPLICA_HOSTS"` // host or host:port
+	ReadAfterWriteWindow time.Duration `yaml:"read_after_write_window" env:"READ_AFTER_WRITE_WINDOW"`
+
+	MigrateOnStart bool `yaml:"migrate_on_start" env:"MIGRATE_ON_START"`
+}
+
+type HealthConfig struct {
+	CheckTimeout       time.Duration `yaml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT"`        // Per check
+	WorkerStallTimeout time.Duration `yaml:"worker_stall_timeout" env:"WORKER_STALL_TIMEOUT"` // Longest a worker may go without a heartbeat
+}
+
+type CacheConfig struct {
+	Backend       string        `yaml:"backend" env:"TASK_CACHE"` // none, memory or redis
+	TTL           time.Duration `yaml:"ttl" env:"TASK_CACHE_TTL"`
+	NegativeTTL   time.Duration `yaml:"negative_ttl" env:"TASK_CACHE_NEGATIVE_TTL"`
+	Size          int           `yaml:"size" env:"TASK_CACHE_SIZE"` // Entries, for the memory cache
+	RedisAddr     string        `yaml:"redis_addr" env:"REDIS_ADDR"`
+	RedisPassword string        `yaml:"redis_password" env:"REDIS_PASSWORD" secret:"true"`
+	RedisDB       int           `yaml:"redis_db" env:"REDIS_DB"`
+}
+
+// Inbound email, received when SMTPAddr is set
+type EmailConfig struct {
+	SMTPAddr       string   `yaml:"smtp_addr" env:"INBOUND_SMTP_ADDR"`
+	SMTPHostname   string   `yaml:"smtp_hostname" env:"INBOUND_SMTP_HOSTNAME"`
+	Domains        []string `yaml:"domains" env:"INBOUND_EMAIL_DOMAINS"`
+	AllowedSenders []string `yaml:"allowed_senders" env:"INBOUND_EMAIL_ALLOWED_SENDERS"`
+	ReplySecret    string   `yaml:"reply_secret" env:"EMAIL_REPLY_SECRET" secret:"true"`
+}
+
+type DigestConfig struct {
+	Sender       string `yaml:"sender" env:"DIGEST_SENDER"` // none, smtp or capture
+	Hour         int    `yaml:"hour" env:"DIGEST_HOUR"`
+	From         string `yaml:"from" env:"DIGEST_FROM"`
+	DueField     string `yaml:"due_field" env:"DIGEST_DUE_FIELD"`
+	CaptureDir   string `yaml:"capture_dir" env:"DIGEST_CAPTURE_DIR"`
+	SMTPAddr     string `yaml:"smtp_addr" env:"SMTP_ADDR"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD" secret:"true"`
+}
+
+type WebhookConfig struct {
+	// Lets webhooks target loopback, private and link-local addresses, which are refused
+	// by default so subscribers cannot reach internal services through the server
+	AllowPrivateTargets bool `yaml:"allow_private_targets" env:"WEBHOOK_ALLOW_PRIVATE_TARGETS"`
+}
+
+type SlackConfig struct {
+	SigningSecret   string        `yaml:"signing_secret" env:"SLACK_SIGNING_SECRET" secret:"true"` // Slash commands are refused while empty
+	MaxSignatureAge time.Duration `yaml:"max_signature_age" env:"SLACK_MAX_SIGNATURE_AGE"`
+}
+
+type AdminConfig struct {
+	Token string `yaml:"token" env:"ADMIN_TOKEN" secret:"true"` // Operator endpoints are not served while empty
+}
+
+// Defaults for everything but secrets, which have to be given
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            "8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     2 * time.Minute,
+			RequestTimeout:  5 * time.Second,
+			DrainPeriod:     5 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Backend:              "postgres",
+			SQLitePath:           "tasks.db",
+			Host:                 "localhost",
+			Port:                 "5432",
+			User:                 "postgres",
+			Name:                 "taskdb",
+			SSLMode:              "disable",
+			MaxOpenConns:         25,
+			MaxIdleConns:         25,
+			ConnMaxLifetime:      30 * time.Minute,
+			ConnMaxIdleTime:      5 * time.Minute,
+			ConnectTimeout:       30 * time.Second,
+			ReplicaHosts:         []string{},
+			ReadAfterWriteWindow: 5 * time.Second,
+			MigrateOnStart:       true,
+		},
+		Health: HealthConfig{
+			CheckTimeout:       2 * time.Second,
+			WorkerStallTimeout: 5 * time.Minute,
+		},
+		Cache: CacheConfig{
+			Backend:     "none",
+			TTL:         5 * time.Minute,
+			NegativeTTL: 30 * time.Second,
+			Size:        10000,
+			RedisAddr:   "localhost:6379",
+		},
+		Email: EmailConfig{
+			SMTPHostname:   "localhost",
+			Domains:        []string{},
+			AllowedSenders: []string{},
+		},
+		Digest: DigestConfig{
+			Sender:     "none",
+			Hour:       8,
+			From:       "Task Management <tasks@localhost>",
+			CaptureDir: "captured-email",
+			SMTPAddr:   "localhost:25",
+		},
+		Slack: SlackConfig{
+			MaxSignatureAge: 5 * time.Minute,
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Blanks every setting's environment variable, which counts as unset, for the test
+func clearEnv(t *testing.T) {
+	walk(reflect.ValueOf(&Config{}).Elem(), "", func(field reflect.Value, info fieldInfo) {
+		t.Setenv(info.env, "")
+	})
+}
+
+func writeConfigFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	clearEnv(t)
+	path := writeConfigFile(t, `
+server:
+  port: "9000"
+  read_timeout: 20s
+database:
+  password: from-file
+  replica_hosts: [replica1, replica2]
+digest:
+  hour: 6
+`)
+	t.Setenv("APP_PORT", "9100")
+	t.Setenv("DB_REPLICA_HOSTS", "replica3, ,replica4:6432")
+	t.Setenv("DIGEST_HOUR", "") // Empty counts as unset
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "9100", cfg.Server.Port)                 // Environment over file
+	assert.Equal(t, 20*time.Second, cfg.Server.ReadTimeout)  // File over default
+	assert.Equal(t, 30*time.Second, cfg.Server.WriteTimeout) // Default
+	assert.Equal(t, "from-file", cfg.Database.Password)
+	assert.Equal(t, []string{"replica3", "replica4:6432"}, cfg.Database.ReplicaHosts)
+	assert.Equal(t, 6, cfg.Digest.Hour)
+}
+
+func TestLoad_RejectsUnknownKeys(t *testing.T) {
+	clearEnv(t)
+	path := writeConfigFile(t, "database:\n  pasword: typo\n")
+
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "pasword")
+}
+
+func TestLoad_ReportsEnvAndValidationErrorsTogether(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_MAX_OPEN_CONNS", "many")
+	t.Setenv("HTTP_READ_TIMEOUT", "soon")
+
+	_, err := Load("")
+	assert.ErrorContains(t, err, "DB_MAX_OPEN_CONNS")
+	assert.ErrorContains(t, err, "HTTP_READ_TIMEOUT")
+	assert.ErrorContains(t, err, "database.password (DB_PASSWORD)")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "secret"
+	assert.NoError(t, cfg.Validate())
+
+	// Every problem is reported, named by yaml key and environment variable
+	cfg = Default()
+	cfg.Database.MaxIdleConns = 50
+	cfg.Digest.Hour = 24
+	cfg.Digest.Sender = "pigeon"
+	cfg.Email.SMTPAddr = ":2525"
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, "database.password (DB_PASSWORD) must be set unless database.url (DATABASE_URL) is")
+	assert.ErrorContains(t, err, "database.max_idle_conns (DB_MAX_IDLE_CONNS) must not exceed database.max_open_conns (DB_MAX_OPEN_CONNS)")
+	assert.ErrorContains(t, err, "digest.hour (DIGEST_HOUR) must be between 0 and 23, got 24")
+	assert.ErrorContains(t, err, "digest.sender (DIGEST_SENDER) must be one of [none smtp capture], got 'pigeon'")
+	assert.ErrorContains(t, err, "email.reply_secret (EMAIL_REPLY_SECRET) must be set to receive email")
+
+	// A URL replaces the discrete connection settings
+	cfg = Default()
+	cfg.Database.URL = "postgres://tasks:secret@db:5432/taskdb"
+	assert.NoError(t, cfg.Validate())
+	cfg.Database.URL = "mysql://db/taskdb"
+	assert.ErrorContains(t, cfg.Validate(), "database.url (DATABASE_URL) must be a postgres:// URL")
+
+	// The other backends need no Postgres settings
+	cfg = Default()
+	cfg.Database.Backend = "memory"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_ErrorOrderIsStable(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "secret"
+	cfg.Server.ReadTimeout = -time.Second
+	cfg.Server.ShutdownTimeout = -time.Second
+	cfg.Database.ConnMaxLifetime = -time.Second
+	cfg.Database.QueryTimeout = -time.Second
+
+	expected := cfg.Validate().Error()
+	assert.Less(t, strings.Index(expected, "server.read_timeout"), strings.Index(expected, "server.shutdown_timeout"))
+	assert.Less(t, strings.Index(expected, "database.conn_max_lifetime"), strings.Index(expected, "database.query_timeout"))
+	for range 20 {
+		assert.Equal(t, expected, cfg.Validate().Error())
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "db-secret"
+	cfg.Database.URL = "postgres://tasks:url-secret@db:5432/taskdb"
+	cfg.Admin.Token = "admin-secret"
+
+	redacted := cfg.Redacted()
+	assert.Equal(t, "[redacted]", redacted.Database.Password)
+	assert.Equal(t, "postgres://tasks:xxxxx@db:5432/taskdb", redacted.Database.URL)
+	assert.Equal(t, "[redacted]", redacted.Admin.Token)
+	assert.Equal(t, "", redacted.Slack.SigningSecret) // Unset secrets stay visibly unset
+	assert.Equal(t, "db-secret", cfg.Database.Password)
+
+	printed := cfg.String()
+	assert.NotContains(t, printed, "db-secret")
+	assert.NotContains(t, printed, "url-secret")
+	assert.NotContains(t, printed, "admin-secret")
+	assert.Contains(t, printed, "read_timeout: 15s")
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[redacted]"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Builds the config from the defaults, then the YAML file at path if there is one, then
+// the environment, and validates it. Every problem found is reported at once.
+func Load(path string) (Config, error) {
+	config := Default()
+	if path != "" {
+		if err := config.LoadFile(path); err != nil {
+			return config, err
+		}
+	}
+	// Unparsable variables leave their settings as they were, so validation still applies
+	return config, errors.Join(config.LoadEnv(), config.Validate())
+}
+
+// Overrides the settings present in a YAML file; unknown keys are errors
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Overrides the settings whose environment variables are set and not empty
+func (c *Config) LoadEnv() error {
+	var errs []error
+	walk(reflect.ValueOf(c).Elem(), "", func(field reflect.Value, info fieldInfo) {
+		if err := loadEnv(field, info.env); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func loadEnv(field reflect.Value, key string) error {
+	// Empty counts as unset, as with utils.GetEnv
+	if os.Getenv(key) == "" {
+		return nil
+	}
+
+	switch {
+	case field.Type() == durationType:
+		value, err := utils.GetEnvDuration(key, time.Duration(field.Int()))
+		field.SetInt(int64(value))
+		return err
+	case field.Kind() == reflect.Int:
+		value, err := utils.GetEnvInt(key, int(field.Int()))
+		field.SetInt(int64(value))
+		return err
+	case field.Kind() == reflect.Bool:
+		value, err := utils.GetEnvBool(key, field.Bool())
+		field.SetBool(value)
+		return err
+	case field.Kind() == reflect.Slice:
+		// Comma-separated, dropping blanks
+		items := []string{}
+		for _, item := range strings.Split(os.Getenv(key), ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		field.SetString(os.Getenv(key))
+	}
+	return nil
+}
+
+// Returns a copy with the secret settings hidden, for printing
+func (c Config) Redacted() Config {
+	walk(reflect.ValueOf(&c).Elem(), "", func(field reflect.Value, info fieldInfo) {
+		if info.secret && field.String() != "" {
+			field.SetString(redactSecret(field.String()))
+		}
+	})
+	return c
+}
+
+// URLs keep everything but the password, which becomes xxxxx
+func redactSecret(value string) string {
+	if u, err := url.Parse(value); err == nil && u.Scheme != "" && u.User != nil {
+		return u.Redacted()
+	}
+	return redacted
+}
+
+// The effective config as YAML, with secrets redacted
+func (c Config) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+type fieldInfo struct {
+	path   string // Dotted yaml keys, such as database.password
+	env    string
+	secret bool
+}
+
+// Calls fn with each setting in a config struct
+func walk(v reflect.Value, prefix string, fn func(field reflect.Value, info fieldInfo)) {
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		path := prefix + structField.Tag.Get("yaml")
+		if structField.Type.Kind() == reflect.Struct && structField.Type != durationType {
+			walk(v.Field(i), path+".", fn)
+			continue
+		}
+		fn(v.Field(i), fieldInfo{
+			path:   path,
+			env:    structField.Tag.Get("env"),
+			secret: structField.Tag.Get("secret") == "true",
+		})
+	}
+}
+
+// Names a setting by its yaml key and environment variable, for error messages
+func describe(path string) string {
+	name := path
+	walk(reflect.ValueOf(&Config{}).Elem(), "", func(field reflect.Value, info fieldInfo) {
+		if info.path == path {
+			name = fmt.Sprintf("%s (%s)", path, info.env)
+		}
+	})
+	return name
+}
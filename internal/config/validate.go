@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Collects every problem rather than stopping at the first
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, path, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s %s", describe(path), fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) oneOf(value, path string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), path, "must be one of %v, got '%s'", allowed, value)
+}
+
+func (v *validator) port(value, path string) {
+	port, err := strconv.Atoi(value)
+	v.check(err == nil && port > 0 && port < 65536, path, "must be a port number, got '%s'", value)
+}
+
+func (v *validator) notNegative(value int64, path string) {
+	v.check(value >= 0, path, "must not be negative")
+}
+
+func (v *validator) positive(value time.Duration, path string) {
+	v.check(value > 0, path, "must be positive")
+}
+
+// A duration checked the same way as its neighbours. Listed in a slice rather than a
+// map so errors come out in a stable order.
+type durationSetting struct {
+	path  string
+	value time.Duration
+}
+
+// Reports every invalid or missing setting in one error
+func (c Config) Validate() error {
+	v := &validator{}
+
+	s := c.Server
+	v.port(s.Port, "server.port")
+	for _, setting := range []durationSetting{
+		{"server.read_timeout", s.ReadTimeout},
+		{"server.write_timeout", s.WriteTimeout},
+		{"server.idle_timeout", s.IdleTimeout},
+		{"server.request_timeout", s.RequestTimeout},
+		{"server.drain_period", s.DrainPeriod},
+		{"server.shutdown_timeout", s.ShutdownTimeout},
+	} {
+		v.notNegative(int64(setting.value), setting.path)
+	}
+
+	db := c.Database
+	v.oneOf(db.Backend, "database.backend", "postgres", "sqlite", "memory")
+	switch db.Backend {
+	case "postgres":
+		if db.URL != "" {
+			u, err := url.Parse(db.URL)
+			v.check(err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql"), "database.url", "must be a postgres:// URL")
+		} else {
+			v.check(db.Host != "", "database.host", "must be set")
+			v.port(db.Port, "database.port")
+			v.check(db.User != "", "database.user", "must be set")
+			v.check(db.Password != "", "database.password", "must be set unless %s is", describe("database.url"))
+			v.check(db.Name != "", "database.name", "must be set")
+			v.oneOf(db.SSLMode, "database.sslmode", "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+		}
+	case "sqlite":
+		v.check(db.SQLitePath != "", "database.sqlite_path", "must be set")
+	}
+	v.notNegative(int64(db.MaxOpenConns), "database.max_open_conns")
+	v.notNegative(int64(db.MaxIdleConns), "database.max_idle_conns")
+	v.check(db.MaxOpenConns == 0 || db.MaxIdleConns <= db.MaxOpenConns, "database.max_idle_conns", "must not exceed %s", describe("database.max_open_conns"))
+	for _, setting := range []durationSetting{
+		{"database.conn_max_lifetime", db.ConnMaxLifetime},
+		{"database.conn_max_idle_time", db.ConnMaxIdleTime},
+		{"database.connect_timeout", db.ConnectTimeout},
+		{"database.query_timeout", db.QueryTimeout},
+		{"database.read_after_write_window", db.ReadAfterWriteWindow},
+	} {
+		v.notNegative(int64(setting.value), setting.path)
+	}
+
+	v.positive(c.Health.CheckTimeout, "health.check_timeout")
+	v.positive(c.Health.WorkerStallTimeout, "health.worker_stall_timeout")
+
+	cache := c.Cache
+	v.oneOf(cache.Backend, "cache.backend", "none", "memory", "redis")
+	v.check(cache.Backend != "memory" || cache.Size > 0, "cache.size", "must be positive")
+	v.check(cache.Backend != "redis" || cache.RedisAddr != "", "cache.redis_addr", "must be set")
+	v.notNegative(int64(cache.RedisDB), "cache.redis_db")
+	v.notNegative(int64(cache.TTL), "cache.ttl")
+	v.notNegative(int64(cache.NegativeTTL), "cache.negative_ttl")
+
+	v.check(c.Email.SMTPAddr == "" || c.Email.ReplySecret != "", "email.reply_secret", "must be set to receive email")
+
+	digest := c.Digest
+	v.oneOf(digest.Sender, "digest.sender", "none", "smtp", "capture")
+	v.check(digest.Hour >= 0 && digest.Hour <= 23, "digest.hour", "must be between 0 and 23, got %d", digest.Hour)
+	_, err := mail.ParseAddress(digest.From)
+	v.check(err == nil, "digest.from", "must be an email address, got '%s'", digest.From)
+	v.check(digest.Sender != "smtp" || digest.SMTPAddr != "", "digest.smtp_addr", "must be set")
+	v.check(digest.Sender != "capture" || digest.CaptureDir != "", "digest.capture_dir", "must be set")
+
+	v.positive(c.Slack.MaxSignatureAge, "slack.max_signature_age")
+
+	return errors.Join(v.errs...)
+}
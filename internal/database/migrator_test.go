@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/AashishRichhariya/task-management-api/migrations"
+	sqlitemigrations "github.com/AashishRichhariya/task-management-api/migrations/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_PairsAndOrders(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_add_index.sql":     {Data: []byte("CREATE INDEX i ON t(c);")},
+		"002_create_t.sql":      {Data: []byte("CREATE TABLE t (c INT);")},
+		"002_create_t.down.sql": {Data: []byte("DROP TABLE t;")},
+		"README.md":             {Data: []byte("not a migration")},
+		"003_not_sql.txt":       {Data: []byte("ignored")},
+	}
+
+	loaded, err := LoadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+
+	assert.Equal(t, Migration{Version: 2, Name: "create_t", Up: "CREATE TABLE t (c INT);", Down: "DROP TABLE t;"}, loaded[0])
+	assert.Equal(t, 10, loaded[1].Version)
+	assert.Empty(t, loaded[1].Down)
+}
+
+func TestLoadMigrations_RejectsInconsistentFiles(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"down without up": {
+			"001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		},
+		"names differ": {
+			"001_a.sql":      {Data: []byte("CREATE TABLE a ();")},
+			"001_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		},
+		"same version twice": {
+			"001_a.sql":  {Data: []byte("CREATE TABLE a ();")},
+			"0001_a.sql": {Data: []byte("CREATE TABLE a ();")},
+		},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadMigrations(fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	for _, fsys := range []fs.FS{migrations.FS, sqlitemigrations.FS} {
+		loaded, err := LoadMigrations(fsys)
+		require.NoError(t, err)
+		require.NotEmpty(t, loaded)
+
+		for i, migration := range loaded {
+			assert.Equal(t, i+1, migration.Version, "versions are consecutive")
+			assert.NotEmpty(t, migration.Down, "migration %d (%s) can be reverted", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestSQLiteMigrator(t *testing.T) {
+	db, err := NewSQLiteConnection(filepath.Join(t.TempDir(), "tasks.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	fsys := fstest.MapFS{
+		"001_create_a.sql":      {Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY); CREATE INDEX a_id ON a(id);")},
+		"001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"002_create_b.sql":      {Data: []byte("CREATE TABLE b (id INTEGER PRIMARY KEY);")},
+		"002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+	migrator, err := NewSQLiteMigrator(db, fsys)
+	require.NoError(t, err)
+	assert.Error(t, migrator.CheckAtHead(context.Background()), "nothing applied yet")
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+	assert.NoError(t, migrator.CheckAtHead(context.Background()))
+
+	// Applied migrations are remembered
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	reverted, err := migrator.Down(1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, 2, reverted[0].Version)
+	assert.EqualError(t, migrator.CheckAtHead(context.Background()), "1 of 2 migrations pending")
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+
+	_, err = db.Exec("SELECT COUNT(*) FROM b")
+	assert.Error(t, err, "table b was dropped")
+}
@@ -0,0 +1,260 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// NotificationBus shares one LISTEN connection between every subscriber in the process.
+// When the connection drops it is re-established with jittered exponential backoff, and
+// subscribers' onReconnect callbacks run because anything notified meanwhile was lost.
+type NotificationBus struct {
+	connStr string
+	config  NotificationBusConfig
+
+	// connMu serializes LISTEN/UNLISTEN and reconnects. It is never held while
+	// notifications are dispatched, since the connection blocks until they are read.
+	connMu      sync.Mutex
+	mu          sync.Mutex
+	conn        *pq.ListenerConn
+	subscribers map[string]map[*BusSubscription]bool
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+type NotificationBusConfig struct {
+	MinBackoff   time.Duration // Delay before the first reconnect attempt
+	MaxBackoff   time.Duration
+	PingInterval time.Duration // Idle time after which the connection is checked
+}
+
+func DefaultNotificationBusConfig() NotificationBusConfig {
+	return NotificationBusConfig{
+		MinBackoff:   500 * time.Millisecond,
+		MaxBackoff:   30 * time.Second,
+		PingInterval: 90 * time.Second,
+	}
+}
+
+// A subscriber's callbacks run on the bus goroutine, one notification at a time, so they
+// should not block for long and must not subscribe or unsubscribe themselves.
+type BusSubscription struct {
+	bus         *NotificationBus
+	channel     string
+	onNotify    func(payload string)
+	onReconnect func()
+}
+
+// Connects and starts delivering notifications. Fails if the first connection cannot be made.
+func NewNotificationBus(connStr string, config NotificationBusConfig) (*NotificationBus, error) {
+	b := &NotificationBus{
+		connStr:     connStr,
+		config:      config,
+		subscribers: make(map[string]map[*BusSubscription]bool),
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+
+	notifications := make(chan *pq.Notification, 32)
+	if err := b.connect(notifications); err != nil {
+		return nil, err
+	}
+	go b.run(notifications)
+	return b, nil
+}
+
+// Subscribes to a channel, issuing LISTEN for it if this is its first subscriber.
+// onReconnect may be nil.
+func (b *NotificationBus) Subscribe(channel string, onNotify func(payload string), onReconnect func()) *BusSubscription {
+	subscription := &BusSubscription{bus: b, channel: channel, onNotify: onNotify, onReconnect: onReconnect}
+
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+
+	b.mu.Lock()
+	first := b.subscribers[channel] == nil
+	if first {
+		b.subscribers[channel] = make(map[*BusSubscription]bool)
+	}
+	b.subscribers[channel][subscription] = true
+	conn := b.conn
+	b.mu.Unlock()
+
+	// While disconnected the channel is listened to on reconnect, as it is if this fails
+	if first && conn != nil {
+		if _, err := conn.Listen(channel); err != nil {
+			log.Printf("Failed to listen on '%s': %v", channel, err)
+		}
+	}
+	return subscription
+}
+
+// Stops delivering to the subscription, issuing UNLISTEN once its channel has no subscribers
+func (s *BusSubscription) Close() {
+	b := s.bus
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+
+	b.mu.Lock()
+	subscribers := b.subscribers[s.channel]
+	delete(subscribers, s)
+	last := subscribers != nil && len(subscribers) == 0
+	if last {
+		delete(b.subscribers, s.channel)
+	}
+	conn := b.conn
+	b.mu.Unlock()
+
+	if last && conn != nil {
+		if _, err := conn.Unlisten(s.channel); err != nil {
+			log.Printf("Failed to unlisten on '%s': %v", s.channel, err)
+		}
+	}
+}
+
+func (b *NotificationBus) Close() error {
+	close(b.done)
+	<-b.stopped
+	return nil
+}
+
+// Opens a connection and listens on every subscribed channel
+func (b *NotificationBus) connect(notifications chan *pq.Notification) error {
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+
+	conn, err := pq.NewListenerConn(b.connStr, notifications)
+	if err != nil {
+		return fmt.Errorf("failed to open listener connection: %w", err)
+	}
+
+	b.mu.Lock()
+	channels := make([]string, 0, len(b.subscribers))
+	for channel := range b.subscribers {
+		channels = append(channels, channel)
+	}
+	b.mu.Unlock()
+
+	for _, channel := range channels {
+		if _, err := conn.Listen(channel); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to listen on '%s': %w", channel, err)
+		}
+	}
+
+	b.mu.Lock()
+	b.conn = conn
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *NotificationBus) run(notifications chan *pq.Notification) {
+	defer close(b.stopped)
+
+	for {
+		if !b.serve(notifications) {
+			return
+		}
+
+		// The listener connection closes the notification channel once it is gone
+		for attempt := 1; ; attempt++ {
+			select {
+			case <-b.done:
+				return
+			case <-time.After(b.backoff(attempt)):
+			}
+
+			notifications = make(chan *pq.Notification, 32)
+			err := b.connect(notifications)
+			if err == nil {
+				log.Printf("Notification bus reconnected after %d attempt(s)", attempt)
+				break
+			}
+			log.Printf("Notification bus reconnect attempt %d failed: %v", attempt, err)
+		}
+		b.reconnected()
+	}
+}
+
+// Delivers notifications until the connection is lost (true) or the bus is closed (false)
+func (b *NotificationBus) serve(notifications chan *pq.Notification) bool {
+	ping := time.NewTicker(b.config.PingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			b.mu.Lock()
+			b.conn.Close()
+			b.conn = nil
+			b.mu.Unlock()
+			return false
+
+		case notification, ok := <-notifications:
+			if !ok {
+				b.mu.Lock()
+				log.Printf("Notification bus connection lost: %v", b.conn.Err())
+				b.conn = nil
+				b.mu.Unlock()
+				return true
+			}
+			b.dispatch(notification)
+			ping.Reset(b.config.PingInterval)
+
+		case <-ping.C:
+			// A failed ping closes the connection, which ends the loop above.
+			// It runs aside because its reply arrives through this loop's channel.
+			b.mu.Lock()
+			conn := b.conn
+			b.mu.Unlock()
+			go conn.Ping()
+		}
+	}
+}
+
+func (b *NotificationBus) dispatch(notification *pq.Notification) {
+	b.mu.Lock()
+	subscribers := make([]*BusSubscription, 0, len(b.subscribers[notification.Channel]))
+	for subscription := range b.subscribers[notification.Channel] {
+		subscribers = append(subscribers, subscription)
+	}
+	b.mu.Unlock()
+
+	for _, subscription := range subscribers {
+		subscription.onNotify(notification.Extra)
+	}
+}
+
+func (b *NotificationBus) reconnected() {
+	b.mu.Lock()
+	subscribers := []*BusSubscription{}
+	for _, channelSubscribers := range b.subscribers {
+		for subscription := range channelSubscribers {
+			subscribers = append(subscribers, subscription)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, subscription := range subscribers {
+		if subscription.onReconnect != nil {
+			subscription.onReconnect()
+		}
+	}
+}
+
+// Exponential backoff with jitter, so replicas that lost the database together
+// do not all reconnect in the same instant. The delay is drawn from [d/2, d].
+func (b *NotificationBus) backoff(attempt int) time.Duration {
+	delay := b.config.MinBackoff
+	for i := 1; i < attempt && delay < b.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, b.config.MaxBackoff)
+	return delay/2 + rand.N(delay/2+1)
+}
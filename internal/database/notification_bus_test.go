@@ -0,0 +1,30 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationBus_BackoffIsJitteredAndCapped(t *testing.T) {
+	bus := &NotificationBus{config: NotificationBusConfig{MinBackoff: time.Second, MaxBackoff: 8 * time.Second}}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second}
+	for i, ceiling := range expected {
+		for range 50 {
+			delay := bus.backoff(i + 1)
+			assert.GreaterOrEqual(t, delay, ceiling/2, "attempt %d", i+1)
+			assert.LessOrEqual(t, delay, ceiling, "attempt %d", i+1)
+		}
+	}
+}
+
+func TestParseTaskChange(t *testing.T) {
+	change, err := ParseTaskChange(`{"op":"DELETE","id":42}`)
+	assert.NoError(t, err)
+	assert.Equal(t, TaskChange{Op: "DELETE", ID: 42}, change)
+
+	_, err = ParseTaskChange("42")
+	assert.Error(t, err)
+}
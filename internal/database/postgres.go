@@ -3,33 +3,96 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
 
-	"github.com/AashishRichhariya/task-management-api/internal/utils"
+	"github.com/AashishRichhariya/task-management-api/internal/config"
 	_ "github.com/lib/pq" // PostgreSQL driver registration
 )
 
-func NewPostgresConnection() (*sql.DB, error) {
-	// Get connection details from environment variables
-	host := utils.GetEnv("DB_HOST", "localhost")
-	port := utils.GetEnv("DB_PORT", "5432")
-	user := utils.GetEnv("DB_USER", "postgres")
-	password := utils.GetEnv("DB_PASSWORD", "password")
-	dbname := utils.GetEnv("DB_NAME", "taskdb")
-	
-	// Build connection string
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
-	
-	// Open database connection
-	db, err := sql.Open("postgres", connStr)
+// Backoff between attempts to reach the database at startup
+const (
+	minConnectBackoff = 500 * time.Millisecond
+	maxConnectBackoff = 5 * time.Second
+)
+
+// Opens a pool and waits for the database to be reachable, retrying with exponential
+// backoff for up to config.ConnectTimeout, so the server can start alongside it
+func NewPostgresConnection(config config.DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", ConnectionString(config))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
-	
-	// Test the connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+	applyPoolConfig(db, config)
+
+	deadline := time.Now().Add(config.ConnectTimeout)
+	backoff := minConnectBackoff
+	for {
+		err := db.Ping()
+		if err == nil {
+			return db, nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			db.Close()
+			return nil, fmt.Errorf("failed to ping database: %w", err)
+		}
+		log.Printf("Database not reachable yet, retrying in %v: %v", backoff, err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
+
+func applyPoolConfig(db *sql.DB, config config.DatabaseConfig) {
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+}
+
+// Returns the configured URL if there is one, or else builds a connection string from
+// the discrete settings
+func ConnectionString(config config.DatabaseConfig) string {
+	if config.URL != "" {
+		return config.URL
+	}
+
+	settings := []struct{ key, value string }{
+		{"host", config.Host},
+		{"port", config.Port},
+		{"user", config.User},
+		{"password", config.Password},
+		{"dbname", config.Name},
+		{"sslmode", config.SSLMode},
+		{"sslrootcert", config.SSLRootCert},
+		{"sslcert", config.SSLCert},
+		{"sslkey", config.SSLKey},
+	}
+
+	parts := []string{}
+	for _, setting := range settings {
+		if setting.value != "" {
+			parts = append(parts, setting.key+"="+quoteDSNValue(setting.value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Returns dsn pointed at another server, keeping everything else
+func withHost(dsn, host, port string) string {
+	if u, err := url.Parse(dsn); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		u.Host = host + ":" + port
+		return u.String()
+	}
+	// Later settings win in key=value strings
+	return dsn + " host=" + quoteDSNValue(host) + " port=" + quoteDSNValue(port)
+}
+
+// Quotes a key=value connection string value if it is empty or has spaces or quotes
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
 	}
-	
-	return db, nil
-}
\ No newline at end of file
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
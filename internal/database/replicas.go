@@ -0,0 +1,296 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+)
+
+// A position in the Postgres write-ahead log. Replicas that have replayed the WAL up
+// to an LSN see every transaction committed before it.
+type LSN uint64
+
+// Parses the X/Y text form Postgres uses
+func ParseLSN(text string) (LSN, error) {
+	high, low, ok := strings.Cut(text, "/")
+	if ok {
+		h, errHigh := strconv.ParseUint(high, 16, 32)
+		l, errLow := strconv.ParseUint(low, 16, 32)
+		if errHigh == nil && errLow == nil {
+			return LSN(h<<32 | l), nil
+		}
+	}
+	return 0, fmt.Errorf("malformed LSN %q", text)
+}
+
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(l)>>32, uint64(l)&0xFFFFFFFF)
+}
+
+// ReadSession tracks what one request must be able to read. MinLSN is the position of
+// the client's own latest write, so replicas that have not replayed it are skipped;
+// writes made while serving the request are recorded for the client to send back.
+type ReadSession struct {
+	MinLSN  LSN
+	written atomic.Uint64
+}
+
+type readSessionKey struct{}
+
+func WithReadSession(ctx context.Context, session *ReadSession) context.Context {
+	return context.WithValue(ctx, readSessionKey{}, session)
+}
+
+// Returns the request's read session, or nil outside of one
+func ReadSessionFrom(ctx context.Context) *ReadSession {
+	session, _ := ctx.Value(readSessionKey{}).(*ReadSession)
+	return session
+}
+
+type primaryReadsKey struct{}
+
+// Sends ctx's reads to the primary. For reads whose result outlives the request, such as
+// cache fills, which another client may rely on to include its latest write.
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// Reports whether ctx's reads must go to the primary
+func ReadsFromPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return primary
+}
+
+// Returns the WAL position of the latest write made in the session, or 0 if there was none
+func (s *ReadSession) Written() LSN {
+	return LSN(s.written.Load())
+}
+
+// Notes a write at lsn; earlier positions than one already noted are ignored
+func (s *ReadSession) Wrote(lsn LSN) {
+	for {
+		current := s.written.Load()
+		if uint64(lsn) <= current || s.written.CompareAndSwap(current, uint64(lsn)) {
+			return
+		}
+	}
+}
+
+// Notes that ctx's request wrote to primary, if it is in a read session, so its client
+// keeps reading from replicas that have the write. Call it once the write has committed.
+func RecordWrite(ctx context.Context, primary *sql.DB) error {
+	session := ReadSessionFrom(ctx)
+	if session == nil {
+		return nil
+	}
+
+	var text string
+	if err := primary.QueryRowContext(ctx, `SELECT pg_current_wal_lsn()::text`).Scan(&text); err != nil {
+		return fmt.Errorf("failed to read WAL position: %w", err)
+	}
+	lsn, err := ParseLSN(text)
+	if err != nil {
+		return err
+	}
+	session.Wrote(lsn)
+	return nil
+}
+
+type ReplicaSetConfig struct {
+	CheckInterval time.Duration // How often each replica's health and replay position are read
+	CheckTimeout  time.Duration
+}
+
+func DefaultReplicaSetConfig() ReplicaSetConfig {
+	return ReplicaSetConfig{
+		CheckInterval: time.Second,
+		CheckTimeout:  500 * time.Millisecond,
+	}
+}
+
+// ReplicaSet spreads reads over streaming replicas of the primary. A background check
+// tracks which replicas are reachable and how far they have replayed the WAL; reads go
+// to the primary while none is healthy and caught up with the read session.
+type ReplicaSet struct {
+	primary  *sql.DB
+	replicas []*replica
+	config   ReplicaSetConfig
+	next     atomic.Uint32
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+type replica struct {
+	dsn string
+	db  *sql.DB
+
+	mu       sync.RWMutex
+	healthy  bool
+	replayed LSN
+}
+
+// Opens a pool per host in dbConfig.ReplicaHosts, with the primary's credentials, database,
+// TLS and pool settings. Replicas are considered unhealthy until Start has checked them;
+// one that cannot be reached is retried by the background check.
+func NewReplicaSet(primary *sql.DB, dbConfig config.DatabaseConfig, config ReplicaSetConfig) (*ReplicaSet, error) {
+	s := &ReplicaSet{
+		primary: primary,
+		config:  config,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	for _, dsn := range replicaConnectionStrings(dbConfig) {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("failed to open replica: %w", err)
+		}
+		applyPoolConfig(db, dbConfig)
+		s.replicas = append(s.replicas, &replica{dsn: dsn, db: db})
+	}
+	return s, nil
+}
+
+// Checks every replica once and keeps checking them in the background
+func (s *ReplicaSet) Start() {
+	s.checkAll()
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(s.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.checkAll()
+			}
+		}
+	}()
+}
+
+// Stops the background check
+func (s *ReplicaSet) Stop() {
+	close(s.done)
+	<-s.stopped
+}
+
+// Closes the replica pools; the primary belongs to the caller
+func (s *ReplicaSet) Close() error {
+	for _, r := range s.replicas {
+		r.db.Close()
+	}
+	return nil
+}
+
+// Returns the pool to read from for ctx: a healthy replica that has replayed the read
+// session's writes, picked round-robin, or else the primary. Reads marked with
+// WithPrimaryReads always go to the primary.
+func (s *ReplicaSet) Reader(ctx context.Context) *sql.DB {
+	if ReadsFromPrimary(ctx) {
+		return s.primary
+	}
+
+	var minLSN LSN
+	if session := ReadSessionFrom(ctx); session != nil {
+		minLSN = max(session.MinLSN, session.Written())
+	}
+
+	count := len(s.replicas)
+	start := int(s.next.Add(1))
+	for i := 0; i < count; i++ {
+		r := s.replicas[(start+i)%count]
+		if r.usable(minLSN) {
+			return r.db
+		}
+	}
+	return s.primary
+}
+
+func (r *replica) usable(minLSN LSN) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.healthy && r.replayed >= minLSN
+}
+
+func (s *ReplicaSet) checkAll() {
+	var wg sync.WaitGroup
+	for _, r := range s.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.check(r)
+		}()
+	}
+	wg.Wait()
+}
+
+// Reads a replica's replay position; a replica that is unreachable, or has been
+// promoted and so no longer replays, is unhealthy
+func (s *ReplicaSet) check(r *replica) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.CheckTimeout)
+	defer cancel()
+
+	var text sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT pg_last_wal_replay_lsn()::text`).Scan(&text)
+	var replayed LSN
+	if err == nil && !text.Valid {
+		err = fmt.Errorf("not in recovery")
+	}
+	if err == nil {
+		replayed, err = ParseLSN(text.String)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if healthy := err == nil; healthy != r.healthy {
+		if healthy {
+			log.Printf("Replica %s is healthy", redactDSN(r.dsn))
+		} else {
+			log.Printf("Replica %s is unhealthy, reading from the primary instead: %v", redactDSN(r.dsn), err)
+		}
+		r.healthy = healthy
+	}
+	r.replayed = replayed
+}
+
+// Builds the primary's connection string pointed at each replica host (host or host:port)
+func replicaConnectionStrings(config config.DatabaseConfig) []string {
+	primary := ConnectionString(config)
+	dsns := []string{}
+	for _, host := range config.ReplicaHosts {
+		port := config.Port
+		if h, p, ok := strings.Cut(host, ":"); ok {
+			host, port = h, p
+		}
+		dsns = append(dsns, withHost(primary, host, port))
+	}
+	return dsns
+}
+
+// Keeps the host of a connection string for logging
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		return u.Host
+	}
+	host, port := "", ""
+	for _, field := range strings.Fields(dsn) {
+		if value, ok := strings.CutPrefix(field, "host="); ok {
+			host = value
+		}
+		if value, ok := strings.CutPrefix(field, "port="); ok {
+			port = value
+		}
+	}
+	return host + ":" + port
+}
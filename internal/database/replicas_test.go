@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLSN(t *testing.T) {
+	lsn, err := ParseLSN("16/B374D848")
+	require.NoError(t, err)
+	assert.Equal(t, LSN(0x16_B374D848), lsn)
+	assert.Equal(t, "16/B374D848", lsn.String())
+
+	for _, text := range []string{"", "16", "16/", "x/1", "1/100000000"} {
+		_, err := ParseLSN(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestReplicaSet_Reader(t *testing.T) {
+	primary, _ := sql.Open("postgres", "host=primary")
+	behind, _ := sql.Open("postgres", "host=behind")
+	ahead, _ := sql.Open("postgres", "host=ahead")
+	down, _ := sql.Open("postgres", "host=down")
+	replicas := &ReplicaSet{primary: primary, replicas: []*replica{
+		{db: behind, healthy: true, replayed: 100},
+		{db: ahead, healthy: true, replayed: 200},
+		{db: down, healthy: false, replayed: 300},
+	}}
+
+	// Without a session reads spread over the healthy replicas
+	seen := map[*sql.DB]bool{}
+	for i := 0; i < 4; i++ {
+		seen[replicas.Reader(context.Background())] = true
+	}
+	assert.Equal(t, map[*sql.DB]bool{behind: true, ahead: true}, seen)
+
+	// A client's own writes are only read from replicas that have replayed them
+	ctx := WithReadSession(context.Background(), &ReadSession{MinLSN: 150})
+	for i := 0; i < 4; i++ {
+		assert.Same(t, ahead, replicas.Reader(ctx))
+	}
+
+	// including writes made while serving the request
+	session := &ReadSession{}
+	session.Wrote(250)
+	session.Wrote(120)
+	assert.Same(t, primary, replicas.Reader(WithReadSession(context.Background(), session)))
+
+	// Reads that outlive the request, such as cache fills, always go to the primary
+	for i := 0; i < 4; i++ {
+		assert.Same(t, primary, replicas.Reader(WithPrimaryReads(context.Background())))
+	}
+
+	// and the primary serves everything while no replica is healthy
+	replicas.replicas[0].healthy, replicas.replicas[1].healthy = false, false
+	assert.Same(t, primary, replicas.Reader(context.Background()))
+}
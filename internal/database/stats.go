@@ -0,0 +1,53 @@
+package database
+
+import "database/sql"
+
+// Usage of a connection pool, as reported by sql.DB.Stats
+type PoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+func NewPoolStats(stats sql.DBStats) PoolStats {
+	return PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+type ReplicaStats struct {
+	Host     string    `json:"host"`
+	Healthy  bool      `json:"healthy"`
+	Replayed string    `json:"replayed_lsn"`
+	Pool     PoolStats `json:"pool"`
+}
+
+// Returns the health, replay position and pool usage of each replica
+func (s *ReplicaSet) Stats() []ReplicaStats {
+	stats := make([]ReplicaStats, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		r.mu.RLock()
+		stats = append(stats, ReplicaStats{
+			Host:     redactDSN(r.dsn),
+			Healthy:  r.healthy,
+			Replayed: r.replayed.String(),
+			Pool:     NewPoolStats(r.db.Stats()),
+		})
+		r.mu.RUnlock()
+	}
+	return stats
+}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Channel the tasks table trigger notifies on after every insert, update and delete
+const TaskChangesChannel = "task_changes"
+
+// Payload of a task_changes notification
+type TaskChange struct {
+	Op string `json:"op"` // INSERT, UPDATE or DELETE
+	ID int    `json:"id"`
+}
+
+func ParseTaskChange(payload string) (TaskChange, error) {
+	var change TaskChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		return TaskChange{}, fmt.Errorf("malformed task change %q: %w", payload, err)
+	}
+	return change, nil
+}
@@ -0,0 +1,59 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var digestFuncs = map[string]any{
+	"date":     func(t time.Time) string { return t.UTC().Format("Jan 2, 2006") },
+	"dateTime": func(t time.Time) string { return t.UTC().Format("Jan 2 15:04 UTC") },
+	"dueDate": func(field string, task models.Task) string {
+		return fmt.Sprint(task.CustomFields[field])
+	},
+	"recipientName": func(user models.User) string {
+		if user.DisplayName != "" {
+			return user.DisplayName
+		}
+		return user.Username
+	},
+}
+
+var (
+	digestText = texttemplate.Must(texttemplate.New("digest.txt.tmpl").Funcs(digestFuncs).ParseFS(templateFiles, "templates/digest.txt.tmpl"))
+	digestHTML = htmltemplate.Must(htmltemplate.New("digest.html.tmpl").Funcs(digestFuncs).ParseFS(templateFiles, "templates/digest.html.tmpl"))
+)
+
+// Renders a digest as an email to its recipient
+func RenderDigest(from mail.Address, digest models.Digest) (OutgoingMessage, error) {
+	var text, html bytes.Buffer
+	if err := digestText.Execute(&text, digest); err != nil {
+		return OutgoingMessage{}, fmt.Errorf("failed to render digest: %w", err)
+	}
+	if err := digestHTML.Execute(&html, digest); err != nil {
+		return OutgoingMessage{}, fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	subject := fmt.Sprintf("Your %s task digest: %d changed", digest.Frequency, len(digest.Changed))
+	if len(digest.Due) > 0 {
+		subject += fmt.Sprintf(", %d due", len(digest.Due))
+	}
+
+	return OutgoingMessage{
+		From:    from,
+		To:      []mail.Address{{Name: digest.Recipient.DisplayName, Address: digest.Recipient.Email}},
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,189 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+)
+
+// A received email reduced to what tasks are made from
+type Message struct {
+	MessageID   string
+	From        string // Address only, lower-cased
+	FromName    string
+	Subject     string
+	Text        string // The first text/plain part, with CRLFs normalised
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+var wordDecoder = mime.WordDecoder{}
+
+// Parses an RFC 5322 message, walking nested multipart bodies. Parts with a filename
+// or an attachment disposition become attachments; when there is no text/plain part
+// the text of an HTML part is used instead.
+func ParseMessage(r io.Reader) (*Message, error) {
+	raw, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("malformed message: %w", err)
+	}
+
+	msg := &Message{MessageID: strings.Trim(raw.Header.Get("Message-Id"), "<> ")}
+	if subject, err := wordDecoder.DecodeHeader(raw.Header.Get("Subject")); err == nil {
+		msg.Subject = strings.TrimSpace(subject)
+	} else {
+		msg.Subject = strings.TrimSpace(raw.Header.Get("Subject"))
+	}
+	if from, err := raw.Header.AddressList("From"); err == nil && len(from) > 0 {
+		msg.From = strings.ToLower(from[0].Address)
+		msg.FromName = from[0].Name
+	}
+
+	var html string
+	err = walkPart(raw.Header, raw.Body, msg, &html)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Text == "" && html != "" {
+		msg.Text = htmlToText(html)
+	}
+	msg.Text = strings.TrimSpace(strings.ReplaceAll(msg.Text, "\r\n", "\n"))
+	return msg, nil
+}
+
+// Header lookup shared by message and part headers
+type headerGetter interface {
+	Get(key string) string
+}
+
+func walkPart(header headerGetter, body io.Reader, msg *Message, html *string) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("malformed multipart body: %w", err)
+			}
+			if err := walkPart(part.Header, part, msg, html); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s part: %w", mediaType, err)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if filename != "" || disposition == "attachment" {
+		if decoded, err := wordDecoder.DecodeHeader(filename); err == nil {
+			filename = decoded
+		}
+		if filename == "" {
+			filename = "attachment"
+		}
+		msg.Attachments = append(msg.Attachments, Attachment{Filename: filename, ContentType: mediaType, Data: data})
+		return nil
+	}
+
+	switch mediaType {
+	case "text/plain":
+		if msg.Text == "" {
+			msg.Text = string(data)
+		}
+	case "text/html":
+		if *html == "" {
+			*html = string(data)
+		}
+	case "message/rfc822":
+		msg.Attachments = append(msg.Attachments, Attachment{Filename: "forwarded.eml", ContentType: mediaType, Data: data})
+	}
+	return nil
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &lineJoiner{r: body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// Drops the line breaks base64 bodies are wrapped with
+type lineJoiner struct {
+	r io.Reader
+}
+
+func (j *lineJoiner) Read(p []byte) (int, error) {
+	for {
+		n, err := j.r.Read(p)
+		kept := p[:0]
+		for _, b := range p[:n] {
+			if b != '\r' && b != '\n' {
+				kept = append(kept, b)
+			}
+		}
+		if len(kept) > 0 || err != nil {
+			return len(kept), err
+		}
+	}
+}
+
+// Crude HTML to text conversion for messages without a plain text part:
+// drops tags, turns line breaks and block ends into newlines and unescapes common entities
+func htmlToText(html string) string {
+	var out bytes.Buffer
+	inTag := false
+	var tag strings.Builder
+	for _, r := range html {
+		switch {
+		case r == '<':
+			inTag = true
+			tag.Reset()
+		case r == '>' && inTag:
+			inTag = false
+			name := ""
+			if fields := strings.Fields(tag.String()); len(fields) > 0 {
+				name = strings.ToLower(strings.TrimSuffix(fields[0], "/"))
+			}
+			switch name {
+			case "br", "/p", "/div", "/li", "/tr", "/h1", "/h2", "/h3":
+				out.WriteByte('\n')
+			}
+		case inTag:
+			tag.WriteRune(r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+
+	replacer := strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'")
+	return replacer.Replace(out.String())
+}
@@ -0,0 +1,79 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multipartMessage = "From: \"Jane Customer\" <Jane@Example.com>\r\n" +
+	"To: support@tasks.test\r\n" +
+	"Subject: =?UTF-8?Q?Fwd:_Printer_on_fire_=F0=9F=94=A5?=\r\n" +
+	"Message-ID: <abc@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"The printer on floor 3 is =\r\n" +
+	"on fire.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>The printer is <b>on fire</b>.</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/png\r\n" +
+	"Content-Disposition: attachment; filename=\"photo.png\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"iVBORw0K\r\n" +
+	"GgoAAAAN\r\n" +
+	"--outer--\r\n"
+
+func TestParseMessage_Multipart(t *testing.T) {
+	msg, err := ParseMessage(strings.NewReader(multipartMessage))
+	require.NoError(t, err)
+
+	assert.Equal(t, "jane@example.com", msg.From)
+	assert.Equal(t, "Jane Customer", msg.FromName)
+	assert.Equal(t, "Fwd: Printer on fire 🔥", msg.Subject)
+	assert.Equal(t, "abc@example.com", msg.MessageID)
+	assert.Equal(t, "The printer on floor 3 is on fire.", msg.Text)
+
+	require.Len(t, msg.Attachments, 1)
+	assert.Equal(t, "photo.png", msg.Attachments[0].Filename)
+	assert.Equal(t, "image/png", msg.Attachments[0].ContentType)
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d"), msg.Attachments[0].Data)
+}
+
+func TestParseMessage_HTMLOnly(t *testing.T) {
+	raw := "From: a@example.com\r\nSubject: Hi\r\nContent-Type: text/html\r\n\r\n<div>Line one</div><div>Fish &amp; chips</div>"
+
+	msg, err := ParseMessage(strings.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "Line one\nFish & chips", msg.Text)
+}
+
+func TestReplyTokens(t *testing.T) {
+	tokens := NewReplyTokens("secret")
+	tag := tokens.Tag(42)
+
+	id, ok := tokens.Find("Re: Printer on fire " + tag)
+	assert.True(t, ok)
+	assert.Equal(t, 42, id)
+
+	// A tag for another task with this task's signature is rejected
+	_, ok = tokens.Find(strings.Replace(tag, "42", "43", 1))
+	assert.False(t, ok)
+
+	_, ok = NewReplyTokens("other").Find(tag)
+	assert.False(t, ok)
+}
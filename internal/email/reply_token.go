@@ -0,0 +1,47 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Subject tags such as "[task-42.1f2e3d4c5b]" tie emails to a task. The suffix is an
+// HMAC of the task ID, so a reply can only target tasks it was sent about.
+type ReplyTokens struct {
+	secret []byte
+}
+
+var replyTagPattern = regexp.MustCompile(`\[task-(\d+)\.([0-9a-f]{10})\]`)
+
+func NewReplyTokens(secret string) *ReplyTokens {
+	return &ReplyTokens{secret: []byte(secret)}
+}
+
+// The tag to put in the subject of emails about a task
+func (t *ReplyTokens) Tag(taskID int) string {
+	return fmt.Sprintf("[task-%d.%s]", taskID, t.sign(taskID))
+}
+
+// Finds a valid tag in a subject and returns its task ID
+func (t *ReplyTokens) Find(subject string) (int, bool) {
+	for _, match := range replyTagPattern.FindAllStringSubmatch(subject, -1) {
+		taskID, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		if hmac.Equal([]byte(match[2]), []byte(t.sign(taskID))) {
+			return taskID, true
+		}
+	}
+	return 0, false
+}
+
+func (t *ReplyTokens) sign(taskID int) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(strconv.Itoa(taskID)))
+	return hex.EncodeToString(mac.Sum(nil))[:10]
+}
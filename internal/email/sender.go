@@ -0,0 +1,179 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An email to send, with a plain-text body and optionally an HTML alternative
+type OutgoingMessage struct {
+	From    mail.Address
+	To      []mail.Address
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Delivers outgoing email
+type Sender interface {
+	Send(msg OutgoingMessage) error
+}
+
+// Encodes the message as RFC 5322, multipart/alternative when it has an HTML body
+func (m OutgoingMessage) Bytes() ([]byte, error) {
+	if len(m.To) == 0 {
+		return nil, fmt.Errorf("message has no recipients")
+	}
+
+	recipients := make([]string, len(m.To))
+	for i, to := range m.To {
+		recipients[i] = to.String()
+	}
+
+	var buf bytes.Buffer
+	domain := "localhost"
+	if at := strings.LastIndex(m.From.Address, "@"); at >= 0 {
+		domain = m.From.Address[at+1:]
+	}
+	fmt.Fprintf(&buf, "From: %s\r\n", m.From.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", randomID(), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if m.HTML == "" {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, m.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	for _, body := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {body.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(part, body.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type SMTPConfig struct {
+	Addr     string // host:port of the relay
+	Username string // PLAIN auth is used when set; the relay must offer TLS unless it is local
+	Password string
+}
+
+// Sends through an SMTP relay, upgrading to TLS when the relay supports it
+type SMTPSender struct {
+	config SMTPConfig
+}
+
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	return &SMTPSender{config: config}
+}
+
+func (s *SMTPSender) Send(msg OutgoingMessage) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		host, _, _ := strings.Cut(s.config.Addr, ":")
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, host)
+	}
+
+	to := make([]string, len(msg.To))
+	for i, recipient := range msg.To {
+		to[i] = recipient.Address
+	}
+	if err := smtp.SendMail(s.config.Addr, auth, msg.From.Address, to, data); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", strings.Join(to, ", "), err)
+	}
+	return nil
+}
+
+// Writes each message to a .eml file in a directory instead of sending it, for
+// development and tests
+type CaptureSender struct {
+	dir string
+
+	mu  sync.Mutex
+	seq int
+}
+
+func NewCaptureSender(dir string) (*CaptureSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create capture directory: %w", err)
+	}
+	return &CaptureSender{dir: dir}, nil
+}
+
+func (s *CaptureSender) Send(msg OutgoingMessage) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.seq++
+	name := fmt.Sprintf("%s-%04d.eml", time.Now().UTC().Format("20060102T150405"), s.seq)
+	s.mu.Unlock()
+
+	if err := os.WriteFile(filepath.Join(s.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to capture email: %w", err)
+	}
+	return nil
+}
+
+// Paths of the captured messages, oldest first
+func (s *CaptureSender) Messages() ([]string, error) {
+	return filepath.Glob(filepath.Join(s.dir, "*.eml"))
+}
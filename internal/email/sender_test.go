@@ -0,0 +1,97 @@
+package email
+
+import (
+	"net/mail"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessage() OutgoingMessage {
+	return OutgoingMessage{
+		From:    mail.Address{Name: "Tasks", Address: "tasks@example.com"},
+		To:      []mail.Address{{Name: "Jane", Address: "jane@example.com"}},
+		Subject: "Résumé of today",
+		Text:    "Line one\nLine two",
+		HTML:    "<p>Line one</p>",
+	}
+}
+
+func TestSMTPSender_DeliversToServer(t *testing.T) {
+	handler := &recordingHandler{}
+	server := startTestServer(t, handler, nil)
+
+	sender := NewSMTPSender(SMTPConfig{Addr: server.Addr()})
+	require.NoError(t, sender.Send(testMessage()))
+
+	require.Len(t, handler.messages, 1)
+	assert.Equal(t, []string{"jane@example.com"}, handler.envelopes[0].To)
+	msg := handler.messages[0]
+	assert.Equal(t, "tasks@example.com", msg.From)
+	assert.Equal(t, "Résumé of today", msg.Subject)
+	assert.Equal(t, "Line one\nLine two", msg.Text)
+	assert.Empty(t, msg.Attachments, "the HTML alternative is not an attachment")
+}
+
+func TestCaptureSender_WritesEmlFiles(t *testing.T) {
+	sender, err := NewCaptureSender(t.TempDir())
+	require.NoError(t, err)
+
+	plain := testMessage()
+	plain.HTML = ""
+	require.NoError(t, sender.Send(testMessage()))
+	require.NoError(t, sender.Send(plain))
+
+	paths, err := sender.Messages()
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		msg, err := ParseMessage(file)
+		file.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "Line one\nLine two", msg.Text)
+	}
+}
+
+func TestOutgoingMessage_RequiresRecipient(t *testing.T) {
+	msg := testMessage()
+	msg.To = nil
+	_, err := msg.Bytes()
+	assert.Error(t, err)
+}
+
+func TestRenderDigest(t *testing.T) {
+	until := time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)
+	digest := models.Digest{
+		Recipient: models.User{Username: "jane", Email: "jane@example.com"},
+		Frequency: models.DigestDaily,
+		Since:     until.Add(-24 * time.Hour),
+		Until:     until,
+		Changed: []models.Task{
+			{ID: 4, Title: "Fix <script> tag", Status: models.StatusInProgress, UpdatedAt: until.Add(-time.Hour)},
+		},
+		Due: []models.Task{
+			{ID: 7, Title: "Ship it", Status: models.StatusPending, CustomFields: map[string]any{"due": "2026-03-10"}},
+		},
+		DueField: "due",
+	}
+
+	msg, err := RenderDigest(mail.Address{Address: "tasks@example.com"}, digest)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Your daily task digest: 1 changed, 1 due", msg.Subject)
+	assert.Equal(t, "jane@example.com", msg.To[0].Address)
+	assert.Contains(t, msg.Text, "Hi jane,")
+	assert.Contains(t, msg.Text, "#4 [in_progress] Fix <script> tag (updated Mar 9 07:00 UTC)")
+	assert.Contains(t, msg.Text, "#7 [pending] Ship it (due 2026-03-10)")
+	assert.Contains(t, msg.HTML, "Fix &lt;script&gt; tag")
+	assert.False(t, strings.Contains(msg.HTML, "<script>"), "titles are escaped in HTML")
+}
@@ -0,0 +1,299 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Receives parsed messages. An error rejects the message; the sender sees its text.
+type MessageHandler interface {
+	HandleMessage(envelope Envelope, msg *Message) error
+}
+
+// Who a message was sent by and to, as given in the SMTP conversation
+type Envelope struct {
+	From string
+	To   []string
+}
+
+// Minimal SMTP receiver (RFC 5321 without extensions beyond SIZE and 8BITMIME).
+// It accepts mail for any recipient in AcceptDomains, or any recipient at all when
+// that is empty; it is meant to sit behind a relay, not to face the internet.
+type Server struct {
+	config  ServerConfig
+	handler MessageHandler
+
+	listener net.Listener
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	conns    map[net.Conn]bool
+	closing  bool
+}
+
+type ServerConfig struct {
+	Addr            string
+	Hostname        string // Announced in the greeting
+	AcceptDomains   []string
+	MaxMessageBytes int64
+	MaxRecipients   int
+	Timeout         time.Duration // Idle time allowed between commands
+}
+
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Addr:            ":2525",
+		Hostname:        "localhost",
+		MaxMessageBytes: 10 << 20,
+		MaxRecipients:   50,
+		Timeout:         5 * time.Minute,
+	}
+}
+
+func NewServer(config ServerConfig, handler MessageHandler) *Server {
+	return &Server{config: config, handler: handler, conns: make(map[net.Conn]bool)}
+}
+
+// Starts listening and serving in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for SMTP on %s: %w", s.config.Addr, err)
+	}
+	s.listener = listener
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				s.mu.Lock()
+				closing := s.closing
+				s.mu.Unlock()
+				if !closing {
+					log.Printf("SMTP accept failed: %v", err)
+				}
+				return
+			}
+			if !s.track(conn) {
+				conn.Close()
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer s.untrack(conn)
+				s.serve(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// The address being listened on, useful when Addr picked a free port
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Stops accepting connections, drops open sessions and waits for them to end
+func (s *Server) Stop() {
+	s.mu.Lock()
+	s.closing = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return false
+	}
+	s.conns[conn] = true
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	conn.Close()
+}
+
+// State of one SMTP session
+type smtpSession struct {
+	server   *Server
+	conn     net.Conn
+	text     *textproto.Conn
+	greeted  bool
+	envelope *Envelope
+}
+
+func (s *Server) serve(conn net.Conn) {
+	session := &smtpSession{server: s, conn: conn, text: textproto.NewConn(conn)}
+	session.reply(220, "%s ESMTP ready", s.config.Hostname)
+
+	for {
+		conn.SetDeadline(time.Now().Add(s.config.Timeout))
+		line, err := session.text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if !session.handle(strings.ToUpper(verb), strings.TrimSpace(arg)) {
+			return
+		}
+	}
+}
+
+// Runs one command, returning false once the session is over
+func (session *smtpSession) handle(verb, arg string) bool {
+	config := session.server.config
+
+	switch verb {
+	case "HELO":
+		session.greeted = true
+		session.envelope = nil
+		session.reply(250, "%s", config.Hostname)
+	case "EHLO":
+		session.greeted = true
+		session.envelope = nil
+		session.replyLines(250, config.Hostname, fmt.Sprintf("SIZE %d", config.MaxMessageBytes), "8BITMIME")
+	case "MAIL":
+		if !session.greeted {
+			session.reply(503, "5.5.1 Say hello first")
+			return true
+		}
+		from, ok := parsePath(arg, "FROM:")
+		if !ok {
+			session.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+			return true
+		}
+		session.envelope = &Envelope{From: strings.ToLower(from)}
+		session.reply(250, "2.1.0 OK")
+	case "RCPT":
+		if session.envelope == nil {
+			session.reply(503, "5.5.1 Need MAIL first")
+			return true
+		}
+		to, ok := parsePath(arg, "TO:")
+		if !ok || to == "" {
+			session.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+			return true
+		}
+		if !session.server.accepts(to) {
+			session.reply(550, "5.1.1 Mailbox unavailable")
+			return true
+		}
+		if len(session.envelope.To) >= config.MaxRecipients {
+			session.reply(452, "4.5.3 Too many recipients")
+			return true
+		}
+		session.envelope.To = append(session.envelope.To, strings.ToLower(to))
+		session.reply(250, "2.1.5 OK")
+	case "DATA":
+		if session.envelope == nil || len(session.envelope.To) == 0 {
+			session.reply(503, "5.5.1 Need RCPT first")
+			return true
+		}
+		session.reply(354, "End data with <CR><LF>.<CR><LF>")
+		session.receive()
+		session.envelope = nil
+	case "RSET":
+		session.envelope = nil
+		session.reply(250, "2.0.0 OK")
+	case "NOOP":
+		session.reply(250, "2.0.0 OK")
+	case "QUIT":
+		session.reply(221, "2.0.0 Bye")
+		return false
+	default:
+		session.reply(502, "5.5.2 Command not recognized")
+	}
+	return true
+}
+
+// Reads the message data and hands it to the handler
+func (session *smtpSession) receive() {
+	config := session.server.config
+	reader := session.text.DotReader()
+	limited := &io.LimitedReader{R: reader, N: config.MaxMessageBytes + 1}
+
+	data, err := io.ReadAll(limited)
+	if err != nil {
+		return // The connection is gone
+	}
+	if int64(len(data)) > config.MaxMessageBytes {
+		io.Copy(io.Discard, reader) // Skip to the end of the data
+		session.reply(552, "5.3.4 Message exceeds %d bytes", config.MaxMessageBytes)
+		return
+	}
+
+	msg, err := ParseMessage(bytes.NewReader(data))
+	if err != nil {
+		session.reply(554, "5.6.0 %v", err)
+		return
+	}
+	if err := session.server.handler.HandleMessage(*session.envelope, msg); err != nil {
+		log.Printf("Rejected email from %s: %v", session.envelope.From, err)
+		session.reply(554, "5.7.0 %s", strings.ReplaceAll(err.Error(), "\n", " "))
+		return
+	}
+	session.reply(250, "2.0.0 Message accepted")
+}
+
+func (session *smtpSession) reply(code int, format string, args ...any) {
+	session.text.PrintfLine("%d %s", code, fmt.Sprintf(format, args...))
+}
+
+func (session *smtpSession) replyLines(code int, lines ...string) {
+	for i, line := range lines {
+		separator := "-"
+		if i == len(lines)-1 {
+			separator = " "
+		}
+		session.text.PrintfLine("%d%s%s", code, separator, line)
+	}
+}
+
+func (s *Server) accepts(address string) bool {
+	if len(s.config.AcceptDomains) == 0 {
+		return true
+	}
+	_, domain, _ := strings.Cut(strings.ToLower(address), "@")
+	for _, accepted := range s.config.AcceptDomains {
+		if domain == strings.ToLower(accepted) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extracts the address from "FROM:<a@b> SIZE=123"; the null path <> is allowed
+func parsePath(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	path := strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(path, "<") {
+		return "", false
+	}
+	end := strings.Index(path, ">")
+	if end < 0 {
+		return "", false
+	}
+	return path[1:end], true
+}
@@ -0,0 +1,76 @@
+package email
+
+import (
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingHandler struct {
+	mu        sync.Mutex
+	envelopes []Envelope
+	messages  []*Message
+	err       error
+}
+
+func (h *recordingHandler) HandleMessage(envelope Envelope, msg *Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err != nil {
+		return h.err
+	}
+	h.envelopes = append(h.envelopes, envelope)
+	h.messages = append(h.messages, msg)
+	return nil
+}
+
+func startTestServer(t *testing.T, handler MessageHandler, configure func(*ServerConfig)) *Server {
+	config := DefaultServerConfig()
+	config.Addr = "127.0.0.1:0"
+	if configure != nil {
+		configure(&config)
+	}
+	server := NewServer(config, handler)
+	require.NoError(t, server.Start())
+	t.Cleanup(server.Stop)
+	return server
+}
+
+func TestServer_ReceivesMail(t *testing.T) {
+	handler := &recordingHandler{}
+	server := startTestServer(t, handler, nil)
+
+	body := "From: jane@example.com\r\nSubject: Broken\r\n\r\nIt broke.\r\n.leading dot\r\n"
+	err := smtp.SendMail(server.Addr(), nil, "Jane@Example.com", []string{"support@tasks.test"}, []byte(body))
+	require.NoError(t, err)
+
+	require.Len(t, handler.messages, 1)
+	assert.Equal(t, Envelope{From: "jane@example.com", To: []string{"support@tasks.test"}}, handler.envelopes[0])
+	assert.Equal(t, "Broken", handler.messages[0].Subject)
+	assert.Equal(t, "It broke.\n.leading dot", handler.messages[0].Text)
+}
+
+func TestServer_Rejections(t *testing.T) {
+	handler := &recordingHandler{}
+	server := startTestServer(t, handler, func(config *ServerConfig) {
+		config.AcceptDomains = []string{"tasks.test"}
+		config.MaxMessageBytes = 200
+	})
+
+	err := smtp.SendMail(server.Addr(), nil, "a@example.com", []string{"someone@elsewhere.test"}, []byte("Subject: x\r\n\r\nx\r\n"))
+	assert.ErrorContains(t, err, "550")
+
+	large := "Subject: big\r\n\r\n" + strings.Repeat("x", 300) + "\r\n"
+	err = smtp.SendMail(server.Addr(), nil, "a@example.com", []string{"support@tasks.test"}, []byte(large))
+	assert.ErrorContains(t, err, "552")
+
+	handler.err = assert.AnError
+	err = smtp.SendMail(server.Addr(), nil, "a@example.com", []string{"support@tasks.test"}, []byte("Subject: x\r\n\r\nx\r\n"))
+	assert.ErrorContains(t, err, "554")
+
+	assert.Empty(t, handler.messages)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	attachmentService service.AttachmentServiceInterface
+}
+
+type AttachmentHandlerInterface interface {
+	GetAttachments(c *gin.Context)
+	DownloadAttachment(c *gin.Context)
+}
+
+func NewAttachmentHandler(attachmentService service.AttachmentServiceInterface) AttachmentHandlerInterface {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// GET /tasks/:id/attachments
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	taskID := middleware.GetTaskID(c)
+
+	attachments, err := h.attachmentService.GetAttachments(c.Request.Context(), taskID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Attachments retrieved successfully",
+		Data:    attachments,
+	})
+}
+
+// GET /tasks/:id/attachments/:attachmentId
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	taskID := middleware.GetTaskID(c)
+	id := middleware.GetAttachmentID(c)
+
+	attachment, err := h.attachmentService.GetAttachment(c.Request.Context(), taskID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})
+	if disposition == "" {
+		disposition = fmt.Sprintf("attachment; filename=attachment-%d", attachment.ID)
+	}
+	c.Header("Content-Disposition", disposition)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, attachment.ContentType, attachment.Data)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AutomationHandler struct {
+	automationService service.AutomationServiceInterface
+}
+
+type AutomationHandlerInterface interface {
+	CreateRule(c *gin.Context)
+	GetRule(c *gin.Context)
+	GetAllRules(c *gin.Context)
+	DeleteRule(c *gin.Context)
+	DryRun(c *gin.Context)
+}
+
+func NewAutomationHandler(automationService service.AutomationServiceInterface) AutomationHandlerInterface {
+	return &AutomationHandler{
+		automationService: automationService,
+	}
+}
+
+// POST /automation-rules
+func (h *AutomationHandler) CreateRule(c *gin.Context) {
+	req := middleware.GetCreateAutomationRuleRequest(c)
+
+	enabled := req.Enabled == nil || *req.Enabled
+	rule, err := h.automationService.CreateRule(req.Name, req.Trigger, req.Conditions, req.Actions, enabled)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Automation rule created successfully",
+		Data:    rule,
+	})
+}
+
+// GET /automation-rules/:id
+func (h *AutomationHandler) GetRule(c *gin.Context) {
+	id := middleware.GetAutomationRuleID(c)
+
+	rule, err := h.automationService.GetRuleByID(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Automation rule retrieved successfully",
+		Data:    rule,
+	})
+}
+
+// GET /automation-rules
+func (h *AutomationHandler) GetAllRules(c *gin.Context) {
+	rules, err := h.automationService.GetAllRules()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Automation rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+// DELETE /automation-rules/:id
+func (h *AutomationHandler) DeleteRule(c *gin.Context) {
+	id := middleware.GetAutomationRuleID(c)
+
+	if err := h.automationService.DeleteRule(id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Automation rule deleted successfully",
+	})
+}
+
+// POST /automation-rules/dry-run
+func (h *AutomationHandler) DryRun(c *gin.Context) {
+	req := middleware.GetAutomationDryRunRequest(c)
+
+	result, err := h.automationService.DryRun(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Automation dry run completed",
+		Data:    result,
+	})
+}
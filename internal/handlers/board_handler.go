@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type BoardHandler struct {
+	boardService service.BoardServiceInterface
+}
+
+type BoardHandlerInterface interface {
+	GetBoard(c *gin.Context)
+	UpdateColumn(c *gin.Context)
+}
+
+func NewBoardHandler(boardService service.BoardServiceInterface) BoardHandlerInterface {
+	return &BoardHandler{
+		boardService: boardService,
+	}
+}
+
+// GET /board?limit=20&status=in_progress&cursor=...
+func (h *BoardHandler) GetBoard(c *gin.Context) {
+	query := middleware.GetBoardQuery(c)
+
+	board, err := h.boardService.GetBoard(c.Request.Context(), query.Limit, query.Status, query.Cursor)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.BoardResponse{
+		Message: "Board retrieved successfully",
+		Data:    board,
+	})
+}
+
+// PUT /board/columns/:status
+func (h *BoardHandler) UpdateColumn(c *gin.Context) {
+	status := middleware.GetBoardColumnStatus(c)
+	req := middleware.GetUpdateBoardColumnRequest(c)
+
+	settings, err := h.boardService.UpdateColumn(status, req.WIPLimit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Board column updated successfully",
+		Data:    settings,
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CustomFieldHandler struct {
+	fieldService service.CustomFieldServiceInterface
+}
+
+type CustomFieldHandlerInterface interface {
+	CreateField(c *gin.Context)
+	GetField(c *gin.Context)
+	GetAllFields(c *gin.Context)
+	DeleteField(c *gin.Context)
+}
+
+func NewCustomFieldHandler(fieldService service.CustomFieldServiceInterface) CustomFieldHandlerInterface {
+	return &CustomFieldHandler{
+		fieldService: fieldService,
+	}
+}
+
+// POST /custom-fields
+func (h *CustomFieldHandler) CreateField(c *gin.Context) {
+	req := middleware.GetCreateCustomFieldRequest(c)
+
+	field, err := h.fieldService.CreateField(req.Key, req.Name, req.Type, req.Options, req.Required)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Custom field created successfully",
+		Data:    field,
+	})
+}
+
+// GET /custom-fields/:id
+func (h *CustomFieldHandler) GetField(c *gin.Context) {
+	id := middleware.GetCustomFieldID(c)
+
+	field, err := h.fieldService.GetFieldByID(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Custom field retrieved successfully",
+		Data:    field,
+	})
+}
+
+// GET /custom-fields
+func (h *CustomFieldHandler) GetAllFields(c *gin.Context) {
+	fields, err := h.fieldService.GetAllFields(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Custom fields retrieved successfully",
+		Data:    fields,
+	})
+}
+
+// DELETE /custom-fields/:id
+func (h *CustomFieldHandler) DeleteField(c *gin.Context) {
+	id := middleware.GetCustomFieldID(c)
+
+	if err := h.fieldService.DeleteField(id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Custom field deleted successfully",
+	})
+}
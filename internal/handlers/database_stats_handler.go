@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type DatabaseStatsHandler struct {
+	primary  *sql.DB
+	replicas *database.ReplicaSet
+}
+
+type DatabaseStatsHandlerInterface interface {
+	GetStats(c *gin.Context)
+}
+
+type databaseStatsResponse struct {
+	Primary  database.PoolStats      `json:"primary"`
+	Replicas []database.ReplicaStats `json:"replicas"`
+}
+
+// replicas may be nil when reads are not spread over replicas
+func NewDatabaseStatsHandler(primary *sql.DB, replicas *database.ReplicaSet) DatabaseStatsHandlerInterface {
+	return &DatabaseStatsHandler{
+		primary:  primary,
+		replicas: replicas,
+	}
+}
+
+// GET /admin/db/stats
+func (h *DatabaseStatsHandler) GetStats(c *gin.Context) {
+	response := databaseStatsResponse{
+		Primary:  database.NewPoolStats(h.primary.Stats()),
+		Replicas: []database.ReplicaStats{},
+	}
+	if h.replicas != nil {
+		response.Replicas = h.replicas.Stats()
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Database stats retrieved successfully",
+		Data:    response,
+	})
+}
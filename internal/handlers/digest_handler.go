@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type DigestHandler struct {
+	digestService service.DigestServiceInterface
+}
+
+type DigestHandlerInterface interface {
+	GetPreferences(c *gin.Context)
+	UpdatePreferences(c *gin.Context)
+}
+
+func NewDigestHandler(digestService service.DigestServiceInterface) DigestHandlerInterface {
+	return &DigestHandler{
+		digestService: digestService,
+	}
+}
+
+// GET /me/digest-preferences
+func (h *DigestHandler) GetPreferences(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+
+	preferences, err := h.digestService.GetPreferences(user.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Digest preferences retrieved successfully",
+		Data:    preferences,
+	})
+}
+
+// PUT /me/digest-preferences
+func (h *DigestHandler) UpdatePreferences(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	req := middleware.GetUpdateDigestPreferencesRequest(c)
+
+	statuses := make([]models.TaskStatus, len(req.Statuses))
+	for i, status := range req.Statuses {
+		statuses[i] = models.TaskStatus(status)
+	}
+
+	preferences, err := h.digestService.UpdatePreferences(user.ID, models.DigestFrequency(req.Frequency), statuses, req.WatchedOnly)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Digest preferences updated successfully",
+		Data:    preferences,
+	})
+}
@@ -4,33 +4,65 @@ import (
 	"net/http"
 	"os"
 
-	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/internal/health"
 
 	"github.com/gin-gonic/gin"
 )
 
-func HealthCheck(c *gin.Context) {
+type HealthHandler struct {
+	checks   *health.Registry
+	instance string
+}
+
+type HealthHandlerInterface interface {
+	Livez(c *gin.Context)
+	Readyz(c *gin.Context)
+}
+
+type healthResponse struct {
+	Status   string          `json:"status"`
+	Service  string          `json:"service"`
+	Instance string          `json:"instance"`
+	Checks   []health.Result `json:"checks,omitempty"`
+}
+
+func NewHealthHandler(checks *health.Registry) HealthHandlerInterface {
 	hostname, err := os.Hostname()
 	if err != nil {
 		hostname = "unknown"
 	}
 
-	// Check database connection
-	dbStatus := "connected"
-	db, err := database.NewPostgresConnection()
-	if err != nil {
-		dbStatus = "disconnected"
-	} else {
-		if err := db.Ping(); err != nil {
-			dbStatus = "disconnected"
-		}
-		db.Close()
+	return &HealthHandler{
+		checks:   checks,
+		instance: hostname,
+	}
+}
+
+// GET /livez
+func (h *HealthHandler) Livez(c *gin.Context) {
+	h.respond(c, h.checks.Live(c.Request.Context()))
+}
+
+// GET /readyz
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	h.respond(c, h.checks.Ready(c.Request.Context()))
+}
+
+// Answers 200 or 503, listing each check's result when ?verbose is given
+func (h *HealthHandler) respond(c *gin.Context, report health.Report) {
+	response := healthResponse{
+		Status:   "ok",
+		Service:  "task-management-api",
+		Instance: h.instance,
+	}
+	status := http.StatusOK
+	if !report.Healthy {
+		response.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	}
+	if _, verbose := c.GetQuery("verbose"); verbose {
+		response.Checks = report.Results
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":   "healthy",
-		"service":  "task-management-api",
-		"instance": hostname,
-		"database": dbStatus,
-	})
-}
\ No newline at end of file
+	c.JSON(status, response)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type MentionHandler struct {
+	mentionService service.MentionServiceInterface
+}
+
+type MentionHandlerInterface interface {
+	GetMentions(c *gin.Context)
+}
+
+func NewMentionHandler(mentionService service.MentionServiceInterface) MentionHandlerInterface {
+	return &MentionHandler{
+		mentionService: mentionService,
+	}
+}
+
+// GET /me/mentions?page=1&limit=20
+func (h *MentionHandler) GetMentions(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	query := middleware.GetMentionQuery(c)
+
+	result, err := h.mentionService.GetMentions(user.ID, query.Page, query.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Mentions retrieved successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationServiceInterface
+}
+
+type NotificationHandlerInterface interface {
+	GetNotifications(c *gin.Context)
+	MarkRead(c *gin.Context)
+	MarkAllRead(c *gin.Context)
+}
+
+func NewNotificationHandler(notificationService service.NotificationServiceInterface) NotificationHandlerInterface {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GET /me/notifications?page=1&limit=20&unread=true
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	query := middleware.GetNotificationQuery(c)
+
+	result, err := h.notificationService.GetNotifications(user.ID, query.Unread, query.Page, query.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Notifications retrieved successfully",
+		Data:    result,
+	})
+}
+
+// POST /me/notifications/:id/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	id := middleware.GetNotificationID(c)
+
+	if err := h.notificationService.MarkRead(user.ID, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Notification marked as read",
+	})
+}
+
+// POST /me/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+
+	updated, err := h.notificationService.MarkAllRead(user.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Notifications marked as read",
+		Data:    gin.H{"updated": updated},
+	})
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SlashCommandHandlerConfig struct {
+	AckTimeout       time.Duration // How long to wait for a result before acknowledging; below the platform's 3s limit
+	ResponseURLHosts []string      // Hosts response URLs may point at; they must also use HTTPS
+	Client           *http.Client  // Posts to response URLs
+}
+
+func DefaultSlashCommandHandlerConfig() SlashCommandHandlerConfig {
+	return SlashCommandHandlerConfig{
+		AckTimeout:       2500 * time.Millisecond,
+		ResponseURLHosts: []string{"hooks.slack.com"},
+		Client:           &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type SlashCommandHandler struct {
+	slashService service.SlashCommandServiceInterface
+	config       SlashCommandHandlerConfig
+}
+
+type SlashCommandHandlerInterface interface {
+	Handle(c *gin.Context)
+}
+
+func NewSlashCommandHandler(slashService service.SlashCommandServiceInterface, config SlashCommandHandlerConfig) SlashCommandHandlerInterface {
+	return &SlashCommandHandler{
+		slashService: slashService,
+		config:       config,
+	}
+}
+
+// POST /integrations/slash
+//
+// Commands that finish within AckTimeout are answered in the response. Slower ones
+// are acknowledged straight away and answered later through the command's response URL.
+func (h *SlashCommandHandler) Handle(c *gin.Context) {
+	// The platform retries when it thinks we timed out; the first attempt is still running
+	if c.GetHeader(models.SlackRetryNumHeader) != "" {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	cmd := middleware.GetSlashCommand(c)
+	// A slow command carries on after the acknowledgement ends the request
+	ctx := context.WithoutCancel(c.Request.Context())
+	result := make(chan *models.SlashResponse, 1)
+	go func() {
+		result <- h.execute(ctx, cmd)
+	}()
+
+	if !h.allowedResponseURL(cmd.ResponseURL) {
+		c.IndentedJSON(http.StatusOK, <-result)
+		return
+	}
+
+	timer := time.NewTimer(h.config.AckTimeout)
+	defer timer.Stop()
+
+	select {
+	case response := <-result:
+		c.IndentedJSON(http.StatusOK, response)
+	case <-timer.C:
+		c.IndentedJSON(http.StatusOK, slashText(models.SlashResponseEphemeral, "Working on it…"))
+		go func() {
+			response := <-result
+			response.ReplaceOriginal = response.ResponseType == models.SlashResponseEphemeral
+			if err := h.respond(cmd.ResponseURL, response); err != nil {
+				log.Printf("Failed to answer %s command: %v", cmd.Command, err)
+			}
+		}()
+	}
+}
+
+// Runs the command, turning errors into a message for the user who ran it
+func (h *SlashCommandHandler) execute(ctx context.Context, cmd models.SlashCommandRequest) *models.SlashResponse {
+	response, err := h.slashService.Execute(ctx, cmd)
+	if err == nil {
+		return response
+	}
+
+	status, body := middleware.DescribeError(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("%s command '%s' failed: %v", cmd.Command, cmd.Text, err)
+	}
+	return slashText(models.SlashResponseEphemeral, ":warning: "+body.Message)
+}
+
+func (h *SlashCommandHandler) allowedResponseURL(responseURL string) bool {
+	u, err := url.Parse(responseURL)
+	return err == nil && u.Scheme == "https" && slices.Contains(h.config.ResponseURLHosts, u.Host)
+}
+
+func (h *SlashCommandHandler) respond(responseURL string, response *models.SlashResponse) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.config.Client.Post(responseURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("response URL answered %s", resp.Status)
+	}
+	return nil
+}
+
+// A plain message; the text is escaped
+func slashText(responseType, text string) *models.SlashResponse {
+	return &models.SlashResponse{
+		ResponseType: responseType,
+		Text:         text,
+		Blocks: []models.MessageBlock{
+			{Type: "section", Text: &models.BlockText{Type: "mrkdwn", Text: models.EscapeMrkdwn(text)}},
+		},
+	}
+}
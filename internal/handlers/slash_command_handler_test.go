@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSigningSecret = "slack-secret"
+
+func setupSlashRouter(taskService service.TaskServiceInterface, handlerConfig SlashCommandHandlerConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+
+	handler := NewSlashCommandHandler(service.NewSlashCommandService(taskService), handlerConfig)
+	router.POST("/integrations/slash", append(
+		append([]gin.HandlerFunc{middleware.VerifySlackSignature(config.SlackConfig{SigningSecret: testSigningSecret, MaxSignatureAge: 5 * time.Minute})}, middleware.ValidateSlashCommand()...),
+		handler.Handle,
+	)...)
+	return router
+}
+
+func slashRequest(text, responseURL string, signedAt time.Time, secret string) *http.Request {
+	body := url.Values{
+		"command":      {"/task"},
+		"text":         {text},
+		"user_id":      {"U123"},
+		"response_url": {responseURL},
+	}.Encode()
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/integrations/slash", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(models.SlackTimestampHeader, timestamp)
+	req.Header.Set(models.SlackSignatureHeader, middleware.SlackSignature(secret, timestamp, []byte(body)))
+	return req
+}
+
+func decodeSlashResponse(t *testing.T, body io.Reader) models.SlashResponse {
+	var response models.SlashResponse
+	require.NoError(t, json.NewDecoder(body).Decode(&response))
+	return response
+}
+
+func TestSlashCommand_RejectsBadSignatures(t *testing.T) {
+	router := setupSlashRouter(new(MockTaskService), DefaultSlashCommandHandlerConfig())
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"wrong secret", slashRequest("help", "", time.Now(), "other-secret")},
+		{"stale timestamp", slashRequest("help", "", time.Now().Add(-10*time.Minute), testSigningSecret)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, tt.req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+
+	// A body changed after signing no longer matches
+	req := slashRequest("help", "", time.Now(), testSigningSecret)
+	req.Body = io.NopCloser(strings.NewReader("command=%2Ftask&text=delete+1"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSlashCommand_AnswersInline(t *testing.T) {
+	mockService := new(MockTaskService)
+	task := &models.Task{ID: 7, Title: "Fix login bug", Status: models.StatusPending, UpdatedAt: time.Now()}
+	mockService.On("CreateTask", "Fix login bug", "", "pending", map[string]any(nil)).Return(task, nil)
+	router := setupSlashRouter(mockService, DefaultSlashCommandHandlerConfig())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, slashRequest("create Fix login bug", "", time.Now(), testSigningSecret))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := decodeSlashResponse(t, w.Body)
+	assert.Equal(t, models.SlashResponseInChannel, response.ResponseType)
+	assert.Equal(t, "<@U123> created task #7: Fix login bug", response.Text)
+	assert.NotEmpty(t, response.Blocks)
+	mockService.AssertExpectations(t)
+}
+
+func TestSlashCommand_ErrorsAreEphemeral(t *testing.T) {
+	mockService := new(MockTaskService)
+	mockService.On("GetTaskByID", 99).Return(nil, models.TaskNotFoundError{ID: 99})
+	router := setupSlashRouter(mockService, DefaultSlashCommandHandlerConfig())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, slashRequest("show 99", "", time.Now(), testSigningSecret))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := decodeSlashResponse(t, w.Body)
+	assert.Equal(t, models.SlashResponseEphemeral, response.ResponseType)
+	assert.Contains(t, response.Text, "task with id 99 not found")
+}
+
+func TestSlashCommand_SlowCommandsAnswerThroughResponseURL(t *testing.T) {
+	posted := make(chan models.SlashResponse, 1)
+	responseServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted <- decodeSlashResponse(t, r.Body)
+	}))
+	defer responseServer.Close()
+
+	mockService := new(MockTaskService)
+	mockService.On("GetAllTasks", 1, 10, "in_progress", "updated_at", "desc", map[string]string(nil)).
+		After(200*time.Millisecond).
+		Return(&models.PaginatedTasksResponse{
+			Tasks:      []models.Task{{ID: 3, Title: "Slow", Status: models.StatusInProgress}},
+			Pagination: models.PaginationMeta{Page: 1, Limit: 10, Total: 1, Pages: 1},
+		}, nil)
+
+	responseURL, _ := url.Parse(responseServer.URL)
+	config := DefaultSlashCommandHandlerConfig()
+	config.AckTimeout = 20 * time.Millisecond
+	config.ResponseURLHosts = []string{responseURL.Host}
+	config.Client = responseServer.Client()
+	router := setupSlashRouter(mockService, config)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, slashRequest("list in_progress", responseServer.URL+"/hook", time.Now(), testSigningSecret))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Working on it…", decodeSlashResponse(t, w.Body).Text)
+
+	select {
+	case response := <-posted:
+		assert.Equal(t, "Tasks in `in_progress` (1)", response.Text)
+		assert.True(t, response.ReplaceOriginal)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the result to be posted to the response URL")
+	}
+}
+
+func TestSlashCommand_IgnoresRetries(t *testing.T) {
+	router := setupSlashRouter(new(MockTaskService), DefaultSlashCommandHandlerConfig())
+
+	req := slashRequest("create Duplicate", "", time.Now(), testSigningSecret)
+	req.Header.Set(models.SlackRetryNumHeader, "1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -19,6 +19,7 @@ type TaskHandlerInterface interface {
 	GetAllTasks(c *gin.Context)
 	UpdateTask(c *gin.Context)
 	DeleteTask(c *gin.Context)
+	MoveTask(c *gin.Context)
 }
 
 
@@ -32,7 +33,7 @@ func NewTaskHandler(taskService service.TaskServiceInterface) TaskHandlerInterfa
 func (h *TaskHandler) CreateTask(c *gin.Context) {
 	req := middleware.GetCreateTaskRequest(c)
 
-	task, err := h.taskService.CreateTask(req.Title, req.Description, req.Status)
+	task, err := h.taskService.CreateTask(c.Request.Context(), req.Title, req.Description, req.Status, req.CustomFields)
 	if err != nil {
 		c.Error(err)
 		return
@@ -48,7 +49,7 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 func (h *TaskHandler) GetTask(c *gin.Context) {
 	id := middleware.GetTaskID(c)
 
-	task, err := h.taskService.GetTaskByID(id)
+	task, err := h.taskService.GetTaskByID(c.Request.Context(), id)
 	if err != nil {
 		c.Error(err)  
 		return
@@ -60,16 +61,18 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 	})
 }
 
-// GET /tasks?page=1&limit=10&status=completed
+// GET /tasks?page=1&limit=10&status=completed&cf.severity=high&sort_by=cf.severity
 func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 	query := middleware.GetTaskQuery(c)
 	
 	response, err := h.taskService.GetAllTasks(
+		c.Request.Context(),
 		query.Page,
 		query.Limit,
 		query.Status,
 		query.SortBy,
 		query.SortOrder,
+		query.CustomFields,
 	)
 	if err != nil {
 		c.Error(err)
@@ -87,7 +90,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	id := middleware.GetTaskID(c)
 	req := middleware.GetUpdateTaskRequest(c)
 	
-	task, err := h.taskService.UpdateTask(id, req.Title, req.Description, req.Status)
+	task, err := h.taskService.UpdateTask(c.Request.Context(), id, req.Title, req.Description, req.Status, req.CustomFields)
 	if err != nil {
 		c.Error(err)
 		return
@@ -103,7 +106,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	id := middleware.GetTaskID(c)
 	
-	err := h.taskService.DeleteTask(id)
+	err := h.taskService.DeleteTask(c.Request.Context(), id)
 	if err != nil {
 		c.Error(err)
 		return
@@ -112,4 +115,21 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
 		Message: "Task deleted successfully",
 	})
+}
+
+// POST /tasks/:id/move
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	id := middleware.GetTaskID(c)
+	req := middleware.GetMoveTaskRequest(c)
+
+	task, err := h.taskService.MoveTask(c.Request.Context(), id, req.Before, req.After, req.Status)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Task moved successfully",
+		Data:    task,
+	})
 }
\ No newline at end of file
@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/AashishRichhariya/task-management-api/internal/config"
 	"github.com/AashishRichhariya/task-management-api/internal/middleware"
 	"github.com/AashishRichhariya/task-management-api/internal/models"
 	"github.com/gin-gonic/gin"
@@ -20,15 +23,15 @@ type MockTaskService struct {
 	mock.Mock
 }
 
-func (m *MockTaskService) CreateTask(title, description, status string) (*models.Task, error) {
-	args := m.Called(title, description, status)
+func (m *MockTaskService) CreateTask(_ context.Context, title, description, status string, customFields map[string]any) (*models.Task, error) {
+	args := m.Called(title, description, status, customFields)
 	if task := args.Get(0); task != nil {
 		return task.(*models.Task), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockTaskService) GetTaskByID(id int) (*models.Task, error) {
+func (m *MockTaskService) GetTaskByID(_ context.Context, id int) (*models.Task, error) {
 	args := m.Called(id)
 	if task := args.Get(0); task != nil {
 		return task.(*models.Task), args.Error(1)
@@ -36,27 +39,35 @@ func (m *MockTaskService) GetTaskByID(id int) (*models.Task, error) {
 	return nil, args.Error(1)
 }
 
-func (m *MockTaskService) GetAllTasks(page, limit int, status, sortBy, sortOrder string) (*models.PaginatedTasksResponse, error) {
-	args := m.Called(page, limit, status, sortBy, sortOrder)
+func (m *MockTaskService) GetAllTasks(_ context.Context, page, limit int, status, sortBy, sortOrder string, customFields map[string]string) (*models.PaginatedTasksResponse, error) {
+	args := m.Called(page, limit, status, sortBy, sortOrder, customFields)
 	if response := args.Get(0); response != nil {
 		return response.(*models.PaginatedTasksResponse), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockTaskService) UpdateTask(id int, title, description, status string) (*models.Task, error) {
-	args := m.Called(id, title, description, status)
+func (m *MockTaskService) UpdateTask(_ context.Context, id int, title, description, status string, customFields map[string]any) (*models.Task, error) {
+	args := m.Called(id, title, description, status, customFields)
 	if task := args.Get(0); task != nil {
 		return task.(*models.Task), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockTaskService) DeleteTask(id int) error {
+func (m *MockTaskService) DeleteTask(_ context.Context, id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockTaskService) MoveTask(_ context.Context, id, beforeID, afterID int, status string) (*models.Task, error) {
+	args := m.Called(id, beforeID, afterID, status)
+	if task := args.Get(0); task != nil {
+		return task.(*models.Task), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func TestCreateTask_Success(t *testing.T) {
 	mockService := new(MockTaskService)
 	handler := NewTaskHandler(mockService)
@@ -70,7 +81,7 @@ func TestCreateTask_Success(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 	
-	mockService.On("CreateTask", "Test Task", "Test Description", "pending").Return(task, nil)
+	mockService.On("CreateTask", "Test Task", "Test Description", "pending", map[string]any(nil)).Return(task, nil)
 	
 	requestBody := models.CreateTaskRequest{
 		Title:       "Test Task",
@@ -172,6 +183,61 @@ func TestGetTask_NotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetTask_Timeout(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := NewTaskHandler(mockService)
+
+	mockService.On("GetTaskByID", 1).Return(nil, fmt.Errorf("failed to get task: %w", context.DeadlineExceeded))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	router.GET("/tasks/:id", append(middleware.ValidateTaskID(), handler.GetTask)...)
+
+	req, _ := http.NewRequest("GET", "/tasks/1", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+
+	var errorResponse models.ErrorResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &errorResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, "Request timed out", errorResponse.Error)
+	mockService.AssertExpectations(t)
+}
+
+func TestRequestDeadline_SpansEveryQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	router.GET("/slow", middleware.RequestDeadline(config.ServerConfig{RequestTimeout: 50 * time.Millisecond}), func(c *gin.Context) {
+		// Three queries that each fit in the deadline, but not together
+		for range 3 {
+			select {
+			case <-time.After(30 * time.Millisecond):
+			case <-c.Request.Context().Done():
+				c.Error(fmt.Errorf("query failed: %w", c.Request.Context().Err()))
+				return
+			}
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/slow", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+}
+
+func TestDescribeError_ClientGone(t *testing.T) {
+	status, response := middleware.DescribeError(fmt.Errorf("failed to get task: %w", context.Canceled))
+
+	assert.Equal(t, 499, status)
+	assert.Equal(t, "Request cancelled", response.Error)
+}
+
 func TestGetTask_InvalidID(t *testing.T) {
 	mockService := new(MockTaskService)
 	handler := NewTaskHandler(mockService)
@@ -214,7 +280,7 @@ func TestGetAllTasks_Success(t *testing.T) {
 		},
 	}
 	
-	mockService.On("GetAllTasks", 1, 10, "", "created_at", "desc").Return(paginatedResponse, nil)
+	mockService.On("GetAllTasks", 1, 10, "", "created_at", "desc", map[string]string(nil)).Return(paginatedResponse, nil)
 	
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -232,5 +298,66 @@ func TestGetAllTasks_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Tasks retrieved successfully", response.Message)
 	
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAllTasks_CustomFieldQuery(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := NewTaskHandler(mockService)
+
+	paginatedResponse := &models.PaginatedTasksResponse{Tasks: []models.Task{}}
+	mockService.On("GetAllTasks", 1, 10, "", "cf.severity", "asc", map[string]string{"severity": "high"}).Return(paginatedResponse, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	router.GET("/tasks", append(middleware.ValidateTaskQuery(), handler.GetAllTasks)...)
+
+	req, _ := http.NewRequest("GET", "/tasks?cf.severity=high&sort_by=cf.severity&sort_order=asc", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAllTasks_InvalidSortField(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := NewTaskHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	router.GET("/tasks", append(middleware.ValidateTaskQuery(), handler.GetAllTasks)...)
+
+	req, _ := http.NewRequest("GET", "/tasks?sort_by=priority", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	mockService.AssertNotCalled(t, "GetAllTasks")
+}
+
+func TestMoveTask_Success(t *testing.T) {
+	mockService := new(MockTaskService)
+	handler := NewTaskHandler(mockService)
+
+	task := &models.Task{ID: 3, Title: "Moved", Status: models.StatusInProgress, Rank: "0:i"}
+	mockService.On("MoveTask", 3, 5, 0, "in_progress").Return(task, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	router.POST("/tasks/:id/move", append(
+		append(middleware.ValidateTaskID(), middleware.ValidateMoveTaskBody()...),
+		handler.MoveTask,
+	)...)
+
+	req, _ := http.NewRequest("POST", "/tasks/3/move", bytes.NewBufferString(`{"before":5,"status":"in_progress"}`))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
 	mockService.AssertExpectations(t)
 }
\ No newline at end of file
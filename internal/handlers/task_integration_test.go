@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Serves the task routes from a real service over the in-memory repository
+func setupTaskIntegrationRouter() *gin.Engine {
+	handler := NewTaskHandler(service.NewTaskService(repository.NewMemoryTaskRepository()))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	router.POST("/tasks", append(middleware.ValidateCreateTaskBody(), handler.CreateTask)...)
+	router.GET("/tasks", append(middleware.ValidateTaskQuery(), handler.GetAllTasks)...)
+	router.GET("/tasks/:id", append(middleware.ValidateTaskID(), handler.GetTask)...)
+	router.DELETE("/tasks/:id", append(middleware.ValidateTaskID(), handler.DeleteTask)...)
+	router.POST("/tasks/:id/move", append(append(middleware.ValidateTaskID(), middleware.ValidateMoveTaskBody()...), handler.MoveTask)...)
+	return router
+}
+
+func serveJSON(router *gin.Engine, method, path string, body any) *httptest.ResponseRecorder {
+	var encoded []byte
+	if body != nil {
+		encoded, _ = json.Marshal(body)
+	}
+	req, _ := http.NewRequest(method, path, bytes.NewReader(encoded))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func decodeData(t *testing.T, recorder *httptest.ResponseRecorder, data any) {
+	t.Helper()
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.NoError(t, json.Unmarshal(response.Data, data))
+}
+
+func TestTaskRoutes_Integration(t *testing.T) {
+	router := setupTaskIntegrationRouter()
+
+	ids := map[string]int{}
+	for _, task := range []map[string]string{
+		{"title": "charlie", "status": "pending"},
+		{"title": "alpha", "status": "completed"},
+		{"title": "bravo", "status": "pending"},
+	} {
+		recorder := serveJSON(router, "POST", "/tasks", task)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+		var created models.Task
+		decodeData(t, recorder, &created)
+		ids[created.Title] = created.ID
+	}
+
+	// Sorting, filtering and pagination go through to the repository
+	var page models.PaginatedTasksResponse
+	recorder := serveJSON(router, "GET", "/tasks?sort_by=title&sort_order=asc&limit=2", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	decodeData(t, recorder, &page)
+	require.Len(t, page.Tasks, 2)
+	assert.Equal(t, []string{"alpha", "bravo"}, []string{page.Tasks[0].Title, page.Tasks[1].Title})
+	assert.Equal(t, 3, page.Pagination.Total)
+	assert.True(t, page.Pagination.HasNext)
+
+	recorder = serveJSON(router, "GET", "/tasks?status=pending&sort_by=id&sort_order=desc", nil)
+	decodeData(t, recorder, &page)
+	require.Len(t, page.Tasks, 2)
+	assert.Equal(t, ids["bravo"], page.Tasks[0].ID)
+
+	// Moving bravo above charlie reorders the column
+	recorder = serveJSON(router, "POST", fmt.Sprintf("/tasks/%d/move", ids["bravo"]), map[string]int{"before": ids["charlie"]})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	recorder = serveJSON(router, "GET", "/tasks?status=pending&sort_by=rank&sort_order=asc", nil)
+	decodeData(t, recorder, &page)
+	assert.Equal(t, "bravo", page.Tasks[0].Title)
+
+	// Deleted tasks are gone
+	recorder = serveJSON(router, "DELETE", fmt.Sprintf("/tasks/%d", ids["alpha"]), nil)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = serveJSON(router, "GET", fmt.Sprintf("/tasks/%d", ids["alpha"]), nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	recorder = serveJSON(router, "DELETE", fmt.Sprintf("/tasks/%d", ids["alpha"]), nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// Comment lines sent while idle keep proxies from closing the connection
+const taskStreamHeartbeat = 15 * time.Second
+
+type TaskStreamHandler struct {
+	broker *service.TaskStreamBroker
+}
+
+type TaskStreamHandlerInterface interface {
+	Stream(c *gin.Context)
+}
+
+func NewTaskStreamHandler(broker *service.TaskStreamBroker) TaskStreamHandlerInterface {
+	return &TaskStreamHandler{
+		broker: broker,
+	}
+}
+
+// GET /tasks/stream?status=in_progress&type=task.created
+func (h *TaskStreamHandler) Stream(c *gin.Context) {
+	query := middleware.GetTaskStreamQuery(c)
+	filter := models.TaskEventFilter{
+		Status: models.TaskStatus(query.Status),
+		Type:   models.TaskEventType(query.Type),
+	}
+
+	// Subscribe before replaying so nothing committed in between is missed
+	subscription := h.broker.Subscribe(filter)
+	defer h.broker.Unsubscribe(subscription)
+
+	// Streams outlive the server's read and write timeouts
+	controller := http.NewResponseController(c.Writer)
+	if err := errors.Join(controller.SetReadDeadline(time.Time{}), controller.SetWriteDeadline(time.Time{})); err != nil {
+		log.Printf("Task stream will be cut off by the server's timeouts: %v", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Tell nginx not to buffer the stream
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	send := func(event models.TaskEvent) error {
+		c.Render(-1, sse.Event{
+			Id:    strconv.FormatInt(event.ID, 10),
+			Event: string(event.Type),
+			Data:  event,
+		})
+		c.Writer.Flush()
+		return ctx.Err()
+	}
+
+	// Events replayed here may also be queued on the subscription already
+	replayed := make(map[int64]bool)
+	if query.LastEventID > 0 {
+		err := h.broker.Replay(query.LastEventID, filter, func(event models.TaskEvent) error {
+			replayed[event.ID] = true
+			return send(event)
+		})
+		if err != nil {
+			return // The client reconnects and resumes from the last event it received
+		}
+	}
+
+	heartbeat := time.NewTicker(taskStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-subscription.Closed:
+			return // Dropped for falling behind; the client resumes on reconnect
+		case event := <-subscription.Events:
+			if replayed[event.ID] {
+				continue
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			c.Writer.WriteString(": keep-alive\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
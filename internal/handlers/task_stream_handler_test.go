@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Outbox holding a fixed list of messages
+type stubOutboxRepository struct {
+	messages []models.OutboxMessage
+}
+
+func newStubOutboxRepository(events ...models.TaskEvent) *stubOutboxRepository {
+	repo := &stubOutboxRepository{}
+	for i, event := range events {
+		payload, _ := json.Marshal(event)
+		repo.messages = append(repo.messages, models.OutboxMessage{ID: int64(i + 1), EventType: event.Type, Payload: payload})
+	}
+	return repo
+}
+
+func (s *stubOutboxRepository) PublishBatch(int, func(int) time.Duration, func(models.OutboxMessage) error) (int, error) {
+	return 0, nil
+}
+
+func (s *stubOutboxRepository) DeletePublished(time.Time) (int, error) { return 0, nil }
+
+func (s *stubOutboxRepository) GetMessagesAfter(afterID int64, limit int) ([]models.OutboxMessage, error) {
+	messages := []models.OutboxMessage{}
+	for _, message := range s.messages {
+		if message.ID > afterID && len(messages) < limit {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+func (s *stubOutboxRepository) GetMessagesByIDs(ids []int64) ([]models.OutboxMessage, error) {
+	messages := []models.OutboxMessage{}
+	for _, message := range s.messages {
+		for _, id := range ids {
+			if message.ID == id {
+				messages = append(messages, message)
+			}
+		}
+	}
+	return messages, nil
+}
+
+func (s *stubOutboxRepository) GetLatestMessageID() (int64, error) {
+	return int64(len(s.messages)), nil
+}
+
+func (s *stubOutboxRepository) GetLookbackStart(id int64, window time.Duration) (int64, error) {
+	return id, nil
+}
+
+func TestTaskStream_ResumesFromLastEventID(t *testing.T) {
+	outbox := newStubOutboxRepository(
+		models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: 1, Status: models.StatusPending}},
+		models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: 2, Status: models.StatusCompleted}},
+		models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: 3, Status: models.StatusPending}},
+	)
+	handler := NewTaskStreamHandler(service.NewTaskStreamBroker(outbox))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	router.GET("/tasks/:id", func(c *gin.Context) {}) // The stream must not clash with task routes
+	router.GET("/tasks/stream", append(middleware.ValidateTaskStreamQuery(), handler.Stream)...)
+
+	// The stream runs until the client goes away
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/tasks/stream?status=pending", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+
+	body := recorder.Body.String()
+	assert.NotContains(t, body, "id:1\n")
+	assert.NotContains(t, body, "id:2\n") // Filtered out by status
+	assert.Contains(t, body, "id:3\nevent:task.created\n")
+}
+
+func TestTaskStream_InvalidLastEventID(t *testing.T) {
+	handler := NewTaskStreamHandler(service.NewTaskStreamBroker(newStubOutboxRepository()))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tasks/stream", append(middleware.ValidateTaskStreamQuery(), handler.Stream)...)
+
+	req, _ := http.NewRequest("GET", "/tasks/stream", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.True(t, strings.Contains(recorder.Body.String(), "Last-Event-ID"))
+}
+
+func TestTaskStream_OutlivesWriteTimeoutBehindReadYourWrites(t *testing.T) {
+	outbox := newStubOutboxRepository(models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: 1, Status: models.StatusPending}})
+	broker := service.NewTaskStreamBroker(outbox)
+	require.NoError(t, broker.Start())
+	defer broker.Close()
+	handler := NewTaskStreamHandler(broker)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ReadYourWrites(config.DatabaseConfig{ReadAfterWriteWindow: time.Second}))
+	router.GET("/tasks/stream", append(middleware.ValidateTaskStreamQuery(), handler.Stream)...)
+
+	server := httptest.NewUnstartedServer(router)
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/tasks/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// An event sent after the write timeout still reaches the client
+	time.Sleep(3 * server.Config.WriteTimeout)
+	broker.Notify("1")
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err, "stream closed before the event arrived")
+		if line == "id:1\n" {
+			break
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type UserHandler struct {
+	userService service.UserServiceInterface
+}
+
+type UserHandlerInterface interface {
+	CreateUser(c *gin.Context)
+	GetCurrentUser(c *gin.Context)
+}
+
+func NewUserHandler(userService service.UserServiceInterface) UserHandlerInterface {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// POST /users
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	req := middleware.GetCreateUserRequest(c)
+
+	user, err := h.userService.CreateUser(req.Username, req.Email, req.DisplayName)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, models.SuccessResponse{
+		Message: "User created successfully; store the API token, it is not shown again",
+		Data:    user,
+	})
+}
+
+// GET /me
+func (h *UserHandler) GetCurrentUser(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "User retrieved successfully",
+		Data:    middleware.GetCurrentUser(c),
+	})
+}
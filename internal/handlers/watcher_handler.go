@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WatcherHandler struct {
+	watcherService service.WatcherServiceInterface
+}
+
+type WatcherHandlerInterface interface {
+	Watch(c *gin.Context)
+	Unwatch(c *gin.Context)
+	GetWatchers(c *gin.Context)
+}
+
+func NewWatcherHandler(watcherService service.WatcherServiceInterface) WatcherHandlerInterface {
+	return &WatcherHandler{
+		watcherService: watcherService,
+	}
+}
+
+// POST /tasks/:id/watch
+func (h *WatcherHandler) Watch(c *gin.Context) {
+	taskID := middleware.GetTaskID(c)
+	user := middleware.GetCurrentUser(c)
+
+	if err := h.watcherService.Watch(c.Request.Context(), taskID, user.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Task watched successfully",
+	})
+}
+
+// DELETE /tasks/:id/watch
+func (h *WatcherHandler) Unwatch(c *gin.Context) {
+	taskID := middleware.GetTaskID(c)
+	user := middleware.GetCurrentUser(c)
+
+	if err := h.watcherService.Unwatch(c.Request.Context(), taskID, user.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Task unwatched successfully",
+	})
+}
+
+// GET /tasks/:id/watchers
+func (h *WatcherHandler) GetWatchers(c *gin.Context) {
+	taskID := middleware.GetTaskID(c)
+
+	watchers, err := h.watcherService.GetWatchers(c.Request.Context(), taskID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Watchers retrieved successfully",
+		Data:    watchers,
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookServiceInterface
+}
+
+type WebhookHandlerInterface interface {
+	CreateWebhook(c *gin.Context)
+	GetWebhook(c *gin.Context)
+	GetAllWebhooks(c *gin.Context)
+	DeleteWebhook(c *gin.Context)
+	GetDeliveries(c *gin.Context)
+}
+
+func NewWebhookHandler(webhookService service.WebhookServiceInterface) WebhookHandlerInterface {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// POST /webhooks
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	req := middleware.GetCreateWebhookRequest(c)
+
+	webhook, err := h.webhookService.CreateWebhook(user.ID, req.URL, req.Secret, req.EventTypes)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, models.SuccessResponse{
+		Message: "Webhook created successfully; store the secret, it is not shown again",
+		Data:    webhook,
+	})
+}
+
+// GET /webhooks/:id
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	id := middleware.GetWebhookID(c)
+
+	webhook, err := h.webhookService.GetWebhookByID(user.ID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Webhook retrieved successfully",
+		Data:    webhook,
+	})
+}
+
+// GET /webhooks
+func (h *WebhookHandler) GetAllWebhooks(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+
+	webhooks, err := h.webhookService.GetAllWebhooks(user.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Webhooks retrieved successfully",
+		Data:    webhooks,
+	})
+}
+
+// DELETE /webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	id := middleware.GetWebhookID(c)
+
+	if err := h.webhookService.DeleteWebhook(user.ID, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Webhook deleted successfully",
+	})
+}
+
+// GET /webhooks/:id/deliveries?page=1&limit=20
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	id := middleware.GetWebhookID(c)
+	query := middleware.GetWebhookDeliveryQuery(c)
+
+	result, err := h.webhookService.GetDeliveries(user.ID, id, query.Page, query.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, models.SuccessResponse{
+		Message: "Webhook deliveries retrieved successfully",
+		Data:    result,
+	})
+}
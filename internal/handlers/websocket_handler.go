@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait      = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = wsPongWait * 9 / 10 // Must be shorter than wsPongWait
+	wsMaxMessageSize = 64 << 10
+	wsSendBufferSize = 64
+)
+
+type WebSocketHandler struct {
+	taskService    service.TaskServiceInterface
+	broker         *service.TaskStreamBroker
+	upgrader       websocket.Upgrader
+	commandTimeout time.Duration // Zero for none
+}
+
+type WebSocketHandlerInterface interface {
+	Connect(c *gin.Context)
+}
+
+// Each command gets commandTimeout to run, like an HTTP request under RequestDeadline
+func NewWebSocketHandler(taskService service.TaskServiceInterface, broker *service.TaskStreamBroker, commandTimeout time.Duration) WebSocketHandlerInterface {
+	return &WebSocketHandler{
+		taskService:    taskService,
+		broker:         broker,
+		commandTimeout: commandTimeout,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			// Selected when the client authenticates with a subprotocol; the one carrying
+			// the token is never echoed back
+			Subprotocols: []string{middleware.WebSocketProtocol},
+		},
+	}
+}
+
+// GET /ws (upgraded to a WebSocket)
+func (h *WebSocketHandler) Connect(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return // The upgrader has already responded with an error
+	}
+
+	client := &wsClient{
+		handler:      h,
+		conn:         conn,
+		user:         user,
+		send:         make(chan models.WSServerMessage, wsSendBufferSize),
+		quit:         make(chan struct{}),
+		subscription: models.NewWSSubscription(),
+		replayed:     make(map[int64]bool),
+	}
+	client.run()
+}
+
+// One connected client. The reader goroutine handles commands; the writer goroutine owns
+// all writes to the connection, including events and heartbeats.
+type wsClient struct {
+	handler *WebSocketHandler
+	conn    *websocket.Conn
+	user    *models.User
+	send    chan models.WSServerMessage
+
+	quit        chan struct{}
+	quitOnce    sync.Once
+	closeCode   int
+	closeReason string
+
+	mu           sync.Mutex
+	subscription *models.WSSubscription
+	replayed     map[int64]bool // Replayed events whose live copy has not come through yet
+}
+
+func (cl *wsClient) run() {
+	events := cl.handler.broker.Subscribe(models.TaskEventFilter{})
+	defer cl.handler.broker.Unsubscribe(events)
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		cl.writeLoop(events)
+	}()
+
+	cl.readLoop()
+	cl.close(websocket.CloseNormalClosure, "")
+	<-writerDone
+}
+
+// Asks the writer to send a close frame and shut the connection down
+func (cl *wsClient) close(code int, reason string) {
+	cl.quitOnce.Do(func() {
+		cl.closeCode = code
+		cl.closeReason = reason
+		close(cl.quit)
+	})
+}
+
+// Queues a reply without blocking; a client that lets its queue fill up is disconnected
+func (cl *wsClient) enqueue(message models.WSServerMessage) {
+	select {
+	case cl.send <- message:
+	default:
+		cl.close(websocket.CloseTryAgainLater, "slow consumer")
+	}
+}
+
+func (cl *wsClient) readLoop() {
+	cl.conn.SetReadLimit(wsMaxMessageSize)
+	cl.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	cl.conn.SetPongHandler(func(string) error {
+		return cl.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := cl.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("WebSocket of user %d closed: %v", cl.user.ID, err)
+			}
+			return
+		}
+
+		var message models.WSClientMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			cl.enqueue(models.WSServerMessage{Type: models.WSMessageError, Error: "Invalid message", Message: err.Error()})
+			continue
+		}
+		cl.handle(message)
+	}
+}
+
+func (cl *wsClient) handle(message models.WSClientMessage) {
+	// The upgrade request is long over, so each message stands on its own
+	ctx := context.Background()
+	if cl.handler.commandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cl.handler.commandTimeout)
+		defer cancel()
+	}
+
+	switch message.Type {
+	case models.WSMessagePing:
+		cl.enqueue(models.WSServerMessage{Type: models.WSMessagePong, ID: message.ID})
+
+	case models.WSMessageSubscribe, models.WSMessageUnsubscribe:
+		if err := cl.updateSubscription(message); err != nil {
+			cl.replyError(message.ID, err)
+			return
+		}
+		cl.enqueue(models.WSServerMessage{Type: models.WSMessageResult, ID: message.ID})
+
+	case models.WSMessageMove:
+		task, err := cl.handler.taskService.MoveTask(ctx, message.TaskID, message.Before, message.After, message.Status)
+		cl.replyTask(message.ID, task, err)
+
+	case models.WSMessageSetStatus:
+		if !models.TaskStatus(message.Status).IsValid() {
+			cl.replyError(message.ID, models.ValidationError{Field: "status", Message: fmt.Sprintf("unknown status '%s'", message.Status)})
+			return
+		}
+		task, err := cl.handler.taskService.UpdateTask(ctx, message.TaskID, "", "", message.Status, nil)
+		cl.replyTask(message.ID, task, err)
+
+	default:
+		cl.replyError(message.ID, models.ValidationError{Field: "type", Message: fmt.Sprintf("unknown message type '%s'", message.Type)})
+	}
+}
+
+func (cl *wsClient) updateSubscription(message models.WSClientMessage) error {
+	statuses := make([]models.TaskStatus, 0, len(message.Statuses))
+	for _, status := range message.Statuses {
+		if !models.TaskStatus(status).IsValid() {
+			return models.ValidationError{Field: "statuses", Message: fmt.Sprintf("unknown status '%s'", status)}
+		}
+		statuses = append(statuses, models.TaskStatus(status))
+	}
+
+	cl.mu.Lock()
+	subscription := cl.subscription
+	if message.Type == models.WSMessageUnsubscribe {
+		for _, id := range message.TaskIDs {
+			delete(subscription.TaskIDs, id)
+		}
+		for _, status := range statuses {
+			delete(subscription.Statuses, status)
+		}
+		if len(message.TaskIDs) == 0 && len(statuses) == 0 {
+			*subscription = *models.NewWSSubscription()
+		}
+		cl.mu.Unlock()
+		return nil
+	}
+
+	for _, id := range message.TaskIDs {
+		subscription.TaskIDs[id] = true
+	}
+	for _, status := range statuses {
+		subscription.Statuses[status] = true
+	}
+	if len(message.TaskIDs) == 0 && len(statuses) == 0 {
+		subscription.All = true
+	}
+	cl.mu.Unlock()
+
+	if message.LastEventID <= 0 {
+		return nil
+	}
+
+	// Replay what the client missed. This blocks the reader, not the writer, so live
+	// events keep flowing; anything replayed is not sent again live.
+	return cl.handler.broker.Replay(message.LastEventID, models.TaskEventFilter{}, func(event models.TaskEvent) error {
+		cl.mu.Lock()
+		matches := cl.subscription.Matches(event)
+		if matches {
+			cl.replayed[event.ID] = true
+		}
+		cl.mu.Unlock()
+		if !matches {
+			return nil
+		}
+
+		select {
+		case cl.send <- models.WSServerMessage{Type: models.WSMessageEvent, Event: &event}:
+			return nil
+		case <-cl.quit:
+			return fmt.Errorf("connection closed")
+		}
+	})
+}
+
+func (cl *wsClient) replyTask(id string, task *models.Task, err error) {
+	if err != nil {
+		cl.replyError(id, err)
+		return
+	}
+	cl.enqueue(models.WSServerMessage{Type: models.WSMessageResult, ID: id, Task: task})
+}
+
+func (cl *wsClient) replyError(id string, err error) {
+	_, response := middleware.DescribeError(err)
+	cl.enqueue(models.WSServerMessage{Type: models.WSMessageError, ID: id, Error: response.Error, Message: response.Message})
+}
+
+func (cl *wsClient) writeLoop(events *service.TaskStreamSubscription) {
+	ping := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ping.Stop()
+		cl.conn.Close() // Unblocks the reader
+	}()
+
+	for {
+		select {
+		case <-cl.quit:
+			cl.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			cl.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(cl.closeCode, cl.closeReason))
+			return
+
+		case <-events.Closed:
+			// The server is shutting down, or the broker dropped us for not keeping up with events
+			if cl.handler.broker.Closing() {
+				cl.close(websocket.CloseGoingAway, "server shutting down")
+			} else {
+				cl.close(websocket.CloseTryAgainLater, "slow consumer")
+			}
+
+		case event := <-events.Events:
+			cl.mu.Lock()
+			wanted := !cl.replayed[event.ID] && cl.subscription.Matches(event)
+			delete(cl.replayed, event.ID) // Each event comes through live once
+			cl.mu.Unlock()
+			if wanted && !cl.write(models.WSServerMessage{Type: models.WSMessageEvent, Event: &event}) {
+				return
+			}
+
+		case message := <-cl.send:
+			if !cl.write(message) {
+				return
+			}
+
+		case <-ping.C:
+			cl.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := cl.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (cl *wsClient) write(message models.WSServerMessage) bool {
+	cl.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return cl.conn.WriteJSON(message) == nil
+}
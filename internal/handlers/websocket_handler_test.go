@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/middleware"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Accepts a single token
+type stubAuthenticator struct{}
+
+func (stubAuthenticator) AuthenticateToken(token string) (*models.User, error) {
+	if token != "secret-token" {
+		return nil, models.UnauthorizedError{Message: "invalid token"}
+	}
+	return &models.User{ID: 7, Username: "alice"}, nil
+}
+
+func setupWebSocketServer(t *testing.T, taskService service.TaskServiceInterface, broker *service.TaskStreamBroker) string {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	router.GET("/ws", middleware.AuthenticateWebSocket(stubAuthenticator{}), NewWebSocketHandler(taskService, broker, time.Second).Connect)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+}
+
+func readServerMessage(t *testing.T, conn *websocket.Conn) models.WSServerMessage {
+	var message models.WSServerMessage
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	require.NoError(t, conn.ReadJSON(&message))
+	return message
+}
+
+func TestWebSocket_RejectsMissingToken(t *testing.T) {
+	url := setupWebSocketServer(t, new(MockTaskService), service.NewTaskStreamBroker(newStubOutboxRepository()))
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestWebSocket_AuthenticatesWithSubprotocol(t *testing.T) {
+	url := setupWebSocketServer(t, new(MockTaskService), service.NewTaskStreamBroker(newStubOutboxRepository()))
+
+	dialer := websocket.Dialer{Subprotocols: []string{middleware.WebSocketProtocol, middleware.WebSocketTokenPrefix + "secret-token"}}
+	conn, resp, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The server picks the marker protocol and never echoes the token
+	assert.Equal(t, middleware.WebSocketProtocol, conn.Subprotocol())
+	assert.NotContains(t, resp.Header.Get("Sec-WebSocket-Protocol"), "secret-token")
+
+	dialer.Subprotocols = []string{middleware.WebSocketProtocol, middleware.WebSocketTokenPrefix + "wrong-token"}
+	_, resp, err = dialer.Dial(url, nil)
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRequestLogger_RedactsAccessTokens(t *testing.T) {
+	var logged bytes.Buffer
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = &logged
+	defer func() { gin.DefaultWriter = defaultWriter }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestLogger())
+	router.GET("/ws", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ws?status=pending&access_token=secret-token", nil))
+
+	assert.Contains(t, logged.String(), "/ws?status=pending&access_token=[redacted]")
+	assert.NotContains(t, logged.String(), "secret-token")
+}
+
+func TestWebSocket_DeliversSubscribedEvents(t *testing.T) {
+	outbox := newStubOutboxRepository(
+		models.TaskEvent{Type: models.EventTaskUpdated, Task: models.Task{ID: 1, Status: models.StatusPending}},
+		models.TaskEvent{Type: models.EventTaskUpdated, Task: models.Task{ID: 2, Status: models.StatusPending}},
+	)
+	broker := service.NewTaskStreamBroker(outbox)
+	require.NoError(t, broker.Start())
+	defer broker.Close()
+	url := setupWebSocketServer(t, new(MockTaskService), broker)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?access_token=secret-token", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(models.WSClientMessage{Type: models.WSMessageSubscribe, ID: "1", TaskIDs: []int{2}}))
+	result := readServerMessage(t, conn)
+	assert.Equal(t, models.WSMessageResult, result.Type)
+	assert.Equal(t, "1", result.ID)
+
+	broker.Notify("1")
+	broker.Notify("2")
+
+	message := readServerMessage(t, conn)
+	assert.Equal(t, models.WSMessageEvent, message.Type)
+	require.NotNil(t, message.Event)
+	assert.Equal(t, 2, message.Event.Task.ID)
+}
+
+func TestWebSocket_Commands(t *testing.T) {
+	mockService := new(MockTaskService)
+	moved := &models.Task{ID: 3, Title: "Task", Status: models.StatusInProgress}
+	mockService.On("MoveTask", 3, 5, 0, "in_progress").Return(moved, nil)
+	mockService.On("UpdateTask", 4, "", "", "completed", map[string]any(nil)).Return(nil, models.TaskNotFoundError{ID: 4})
+	url := setupWebSocketServer(t, mockService, service.NewTaskStreamBroker(newStubOutboxRepository()))
+
+	header := http.Header{"Authorization": {"Bearer secret-token"}}
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(models.WSClientMessage{Type: models.WSMessageMove, ID: "a", TaskID: 3, Before: 5, Status: "in_progress"}))
+	result := readServerMessage(t, conn)
+	assert.Equal(t, models.WSMessageResult, result.Type)
+	assert.Equal(t, "a", result.ID)
+	require.NotNil(t, result.Task)
+	assert.Equal(t, models.StatusInProgress, result.Task.Status)
+
+	require.NoError(t, conn.WriteJSON(models.WSClientMessage{Type: models.WSMessageSetStatus, ID: "b", TaskID: 4, Status: "completed"}))
+	failure := readServerMessage(t, conn)
+	assert.Equal(t, models.WSMessageError, failure.Type)
+	assert.Equal(t, "b", failure.ID)
+	assert.Equal(t, "Task not found", failure.Error)
+
+	require.NoError(t, conn.WriteJSON(models.WSClientMessage{Type: models.WSMessageSetStatus, ID: "c", TaskID: 4, Status: "bogus"}))
+	invalid := readServerMessage(t, conn)
+	assert.Equal(t, models.WSMessageError, invalid.Type)
+	assert.Equal(t, "c", invalid.ID)
+
+	require.NoError(t, conn.WriteJSON(models.WSClientMessage{Type: models.WSMessagePing, ID: "d"}))
+	assert.Equal(t, models.WSMessagePong, readServerMessage(t, conn).Type)
+
+	mockService.AssertExpectations(t)
+}
+
+// Blocks moves until their context ends
+type slowTaskService struct {
+	MockTaskService
+}
+
+func (s *slowTaskService) MoveTask(ctx context.Context, id, beforeID, afterID int, status string) (*models.Task, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestWebSocket_CommandsTimeOut(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws", middleware.AuthenticateWebSocket(stubAuthenticator{}),
+		NewWebSocketHandler(new(slowTaskService), service.NewTaskStreamBroker(newStubOutboxRepository()), 50*time.Millisecond).Connect)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?access_token=secret-token", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(models.WSClientMessage{Type: models.WSMessageMove, ID: "a", TaskID: 3, Status: "pending"}))
+	failure := readServerMessage(t, conn)
+	assert.Equal(t, models.WSMessageError, failure.Type)
+	assert.Equal(t, "a", failure.ID)
+	assert.Equal(t, "Request timed out", failure.Error)
+}
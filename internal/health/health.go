@@ -0,0 +1,180 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A Checker reports whether one dependency or part of the server is working. It should
+// give up when ctx is done.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Pings the shared pool, so probes reuse its connections instead of opening their own
+func DatabaseChecker(db *sql.DB) Checker {
+	return CheckerFunc(db.PingContext)
+}
+
+type Result struct {
+	Name     string `json:"name"`
+	Healthy  bool   `json:"healthy"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Healthy bool
+	Results []Result // In registration order
+}
+
+// Registry holds the checks behind the liveness and readiness probes. Liveness checks
+// catch a process that needs restarting, such as a stalled worker; readiness checks
+// add what the server needs to serve requests, such as the database. Every liveness
+// check is also a readiness check.
+type Registry struct {
+	timeout  time.Duration // Per check
+	draining atomic.Bool
+
+	mu     sync.RWMutex
+	checks []registeredCheck
+}
+
+type registeredCheck struct {
+	name     string
+	checker  Checker
+	liveness bool
+}
+
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+func (r *Registry) AddLivenessCheck(name string, checker Checker) {
+	r.add(registeredCheck{name: name, checker: checker, liveness: true})
+}
+
+func (r *Registry) AddReadinessCheck(name string, checker Checker) {
+	r.add(registeredCheck{name: name, checker: checker})
+}
+
+func (r *Registry) add(check registeredCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+}
+
+// Fails readiness from now on, so load balancers stop sending requests to a server
+// that is shutting down while it finishes the ones it has
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// Runs the liveness checks
+func (r *Registry) Live(ctx context.Context) Report {
+	return r.run(ctx, true)
+}
+
+// Runs every check
+func (r *Registry) Ready(ctx context.Context) Report {
+	return r.run(ctx, false)
+}
+
+// Runs the checks concurrently, each bounded by the registry's timeout
+func (r *Registry) run(ctx context.Context, livenessOnly bool) Report {
+	r.mu.RLock()
+	checks := []registeredCheck{}
+	for _, check := range r.checks {
+		if check.liveness || !livenessOnly {
+			checks = append(checks, check)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := Report{Healthy: true, Results: make([]Result, len(checks))}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Results[i] = r.runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	if !livenessOnly && r.draining.Load() {
+		report.Results = append(report.Results, Result{Name: "shutdown", Error: "shutting down", Duration: "0s"})
+	}
+	for _, result := range report.Results {
+		report.Healthy = report.Healthy && result.Healthy
+	}
+	return report
+}
+
+func (r *Registry) runCheck(ctx context.Context, check registeredCheck) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- check.checker.Check(ctx)
+	}()
+
+	// A checker that ignores ctx is left behind rather than holding up the probe
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{Name: check.name, Healthy: err == nil, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Heartbeat records when a background worker last went round its loop, so a worker
+// that has stopped or is stuck can be told apart from one that is idle
+type Heartbeat struct {
+	last atomic.Int64 // Unix nanoseconds; 0 before the first beat
+}
+
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Returns when the worker last beat, or the zero time if it never has
+func (h *Heartbeat) Last() time.Time {
+	last := h.last.Load()
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+// Fails while the heartbeat is older than maxAge
+func (h *Heartbeat) Checker(maxAge time.Duration) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		last := h.Last()
+		if last.IsZero() {
+			return fmt.Errorf("not started")
+		}
+		if age := time.Since(last); age > maxAge {
+			return fmt.Errorf("no heartbeat for %v", age.Round(time.Second))
+		}
+		return nil
+	})
+}
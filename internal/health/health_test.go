@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_LivenessAndReadiness(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.AddLivenessCheck("worker", CheckerFunc(func(ctx context.Context) error { return nil }))
+	registry.AddReadinessCheck("database", CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") }))
+
+	// A failing readiness check does not make the process unhealthy
+	live := registry.Live(context.Background())
+	assert.True(t, live.Healthy)
+	require.Len(t, live.Results, 1)
+	assert.Equal(t, "worker", live.Results[0].Name)
+
+	ready := registry.Ready(context.Background())
+	assert.False(t, ready.Healthy)
+	require.Len(t, ready.Results, 2)
+	assert.True(t, ready.Results[0].Healthy)
+	assert.Equal(t, "database", ready.Results[1].Name)
+	assert.Equal(t, "connection refused", ready.Results[1].Error)
+}
+
+func TestRegistry_Drain(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.AddReadinessCheck("database", CheckerFunc(func(ctx context.Context) error { return nil }))
+	assert.True(t, registry.Ready(context.Background()).Healthy)
+
+	registry.Drain()
+	ready := registry.Ready(context.Background())
+	assert.False(t, ready.Healthy)
+	assert.Equal(t, "shutdown", ready.Results[len(ready.Results)-1].Name)
+	assert.True(t, registry.Live(context.Background()).Healthy, "a draining server is still alive")
+}
+
+func TestRegistry_TimesOutSlowChecks(t *testing.T) {
+	registry := NewRegistry(50 * time.Millisecond)
+	block := make(chan struct{})
+	defer close(block)
+	registry.AddReadinessCheck("ignores context", CheckerFunc(func(ctx context.Context) error {
+		<-block
+		return nil
+	}))
+
+	start := time.Now()
+	report := registry.Ready(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, report.Healthy)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Results[0].Error)
+}
+
+func TestHeartbeat_Checker(t *testing.T) {
+	var heartbeat Heartbeat
+	checker := heartbeat.Checker(time.Minute)
+	assert.EqualError(t, checker.Check(context.Background()), "not started")
+
+	heartbeat.Beat()
+	assert.NoError(t, checker.Check(context.Background()))
+
+	heartbeat.last.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	assert.EqualError(t, checker.Check(context.Background()), "no heartbeat for 2m0s")
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocket subprotocols for authenticating in the browser, which cannot set headers on
+// upgrade requests: clients offer WebSocketProtocol together with the token prefixed by
+// WebSocketTokenPrefix, and the server selects WebSocketProtocol. This keeps the token
+// out of the URL and so out of access logs.
+const (
+	WebSocketProtocol    = "bearer"
+	WebSocketTokenPrefix = "access_token."
+)
+
+// Resolves an API token to the user it was issued to
+type TokenAuthenticator interface {
+	AuthenticateToken(token string) (*models.User, error)
+}
+
+// Requires an "Authorization: Bearer <token>" header and stores the caller in context
+func Authenticate(auth TokenAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			c.Error(models.UnauthorizedError{Message: "expected an 'Authorization: Bearer <token>' header"})
+			c.Abort()
+			return
+		}
+
+		authenticate(c, auth, token)
+	}
+}
+
+// Like Authenticate, but also accepts the token as a WebSocket subprotocol (see
+// WebSocketProtocol), or from older clients as an access_token query parameter, which
+// RequestLogger keeps out of the log
+func AuthenticateWebSocket(auth TokenAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok {
+			token, ok = webSocketProtocolToken(c)
+		}
+		if !ok {
+			token = c.Query("access_token")
+		}
+		if token == "" {
+			c.Error(models.UnauthorizedError{Message: "expected an 'Authorization: Bearer <token>' header or '" + WebSocketTokenPrefix + "<token>' subprotocol"})
+			c.Abort()
+			return
+		}
+
+		authenticate(c, auth, token)
+	}
+}
+
+// Requires the operator's admin token as a bearer token, for endpoints that are not
+// tied to a user
+func RequireAdminToken(config config.AdminConfig) gin.HandlerFunc {
+	adminToken := config.Token
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c)
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(adminToken)) != 1 {
+			c.Error(models.UnauthorizedError{Message: "expected the admin token in an 'Authorization: Bearer <token>' header"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	return strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+}
+
+func webSocketProtocolToken(c *gin.Context) (string, bool) {
+	for _, header := range c.Request.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			if token, ok := strings.CutPrefix(strings.TrimSpace(protocol), WebSocketTokenPrefix); ok {
+				return token, true
+			}
+		}
+	}
+	return "", false
+}
+
+func authenticate(c *gin.Context, auth TokenAuthenticator, token string) {
+	user, err := auth.AuthenticateToken(strings.TrimSpace(token))
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return
+	}
+
+	// Store authenticated user in context
+	c.Set("currentUser", user)
+	c.Next()
+}
+
+// Helper for handlers behind Authenticate
+func GetCurrentUser(c *gin.Context) *models.User {
+	return c.MustGet("currentUser").(*models.User)
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// Gives the request's context a deadline of HTTP_REQUEST_TIMEOUT, shared by every query
+// the request makes, so a handler that makes several can still only run that long.
+// Queries that hit it fail the request with 504. Not for streams, which stay open.
+func RequestDeadline(config config.ServerConfig) gin.HandlerFunc {
+	timeout := config.RequestTimeout
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
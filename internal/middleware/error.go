@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 
@@ -26,31 +28,68 @@ func ErrorMiddleware() gin.HandlerFunc {
 
 		// Check if there were any errors
 		if len(c.Errors) > 0 {
-			err := c.Errors.Last().Err
-
-			switch e := err.(type) {
-			case models.TaskNotFoundError:
-				c.JSON(http.StatusNotFound, models.ErrorResponse{
-					Error:   "Task not found",
-					Message: e.Error(),
-				})
-			case models.ValidationError:
-				c.JSON(http.StatusBadRequest, models.ErrorResponse{
-					Error:   "Validation failed",
-					Message: e.Error(),
-				})
-			case models.BusinessError:
-				c.JSON(http.StatusBadRequest, models.ErrorResponse{
-					Error:   "Business logic error",
-					Message: e.Error(),
-				})
-			default:
-				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-					Error:   "Internal server error",
-					Message: "Something went wrong",
-				})
-			}
+			status, response := DescribeError(c.Errors.Last().Err)
+			c.JSON(status, response)
 			return
 		}
 	}
-}
\ No newline at end of file
+}
+
+// Not a standard status: the client went away before the response, as nginx reports it
+const statusClientClosedRequest = 499
+
+// Maps an application error to its HTTP status and response body
+func DescribeError(err error) (int, models.ErrorResponse) {
+	// Queries carry the request's deadline, and give up with it
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, models.ErrorResponse{
+			Error:   "Request timed out",
+			Message: "The request took too long. Please try again later.",
+		}
+	}
+	// And are cancelled when the client disconnects, which is not a server failure
+	if errors.Is(err, context.Canceled) {
+		return statusClientClosedRequest, models.ErrorResponse{
+			Error:   "Request cancelled",
+			Message: "The client closed the request.",
+		}
+	}
+
+	switch e := err.(type) {
+	case models.TaskNotFoundError:
+		return http.StatusNotFound, models.ErrorResponse{
+			Error:   "Task not found",
+			Message: e.Error(),
+		}
+	case models.NotFoundError:
+		return http.StatusNotFound, models.ErrorResponse{
+			Error:   "Resource not found",
+			Message: e.Error(),
+		}
+	case models.ValidationError:
+		return http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Validation failed",
+			Message: e.Error(),
+		}
+	case models.UnauthorizedError:
+		return http.StatusUnauthorized, models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: e.Error(),
+		}
+	case models.RankExhaustedError:
+		return http.StatusConflict, models.ErrorResponse{
+			Error:   "Rank space exhausted",
+			Message: e.Error(),
+		}
+	case models.BusinessError:
+		return http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Business logic error",
+			Message: e.Error(),
+		}
+	default:
+		return http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Something went wrong",
+		}
+	}
+}
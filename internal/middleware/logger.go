@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Query parameters that carry credentials
+var credentialParam = regexp.MustCompile(`(^|&)(access_token)=[^&]*`)
+
+// Logs each request like gin's default logger, with credentials in the query string
+// replaced so they never reach the log
+func RequestLogger() gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(param gin.LogFormatterParams) string {
+			return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+				param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+				param.StatusCode,
+				param.Latency.Truncate(time.Microsecond),
+				param.ClientIP,
+				param.Method,
+				redactQuery(param.Path),
+				param.ErrorMessage,
+			)
+		},
+	})
+}
+
+func redactQuery(path string) string {
+	path, query, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	return path + "?" + credentialParam.ReplaceAllString(query, "${1}${2}=[redacted]")
+}
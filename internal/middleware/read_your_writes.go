@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/config"
+	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/gin-gonic/gin"
+)
+
+// Carries the WAL position of a client's latest write, in both directions
+const (
+	ReadAfterHeader = "X-Read-After-LSN"
+	readAfterCookie = "read_after_lsn"
+)
+
+// Gives each request a read session so its client reads its own writes from replicas.
+// A response to a request that wrote carries the write's WAL position in a cookie that
+// expires after the read-after-write window, and in the X-Read-After-LSN header for
+// clients without cookies, which should send it back for as long as they need to see the
+// write. While it is sent, reads skip replicas that have not replayed the write.
+func ReadYourWrites(config config.DatabaseConfig) gin.HandlerFunc {
+	window := config.ReadAfterWriteWindow
+	return func(c *gin.Context) {
+		session := &database.ReadSession{}
+		value := c.GetHeader(ReadAfterHeader)
+		if value == "" {
+			value, _ = c.Cookie(readAfterCookie)
+		}
+		if lsn, err := database.ParseLSN(value); err == nil {
+			session.MinLSN = lsn
+		}
+
+		c.Request = c.Request.WithContext(database.WithReadSession(c.Request.Context(), session))
+		c.Writer = &readSessionWriter{ResponseWriter: c.Writer, session: session, window: window}
+		c.Next()
+	}
+}
+
+// Adds the session's write position to the response headers, which can only be
+// changed until the status is written
+type readSessionWriter struct {
+	gin.ResponseWriter
+	session *database.ReadSession
+	window  time.Duration
+	added   bool
+}
+
+func (w *readSessionWriter) WriteHeader(code int) {
+	if lsn := w.session.Written(); lsn != 0 && !w.added {
+		w.added = true
+		http.SetCookie(w, &http.Cookie{
+			Name:     readAfterCookie,
+			Value:    lsn.String(),
+			Path:     "/",
+			MaxAge:   max(int(w.window.Seconds()), 1),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		w.Header().Set(ReadAfterHeader, lsn.String())
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Lets http.ResponseController reach the connection, e.g. for streams to clear the
+// server's write deadline
+func (w *readSessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
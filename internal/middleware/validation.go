@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/AashishRichhariya/task-management-api/internal/models"
@@ -9,6 +10,50 @@ import (
 )
 
 func ValidateTaskID() []gin.HandlerFunc {
+	return validateIDParam("taskID")
+}
+
+func ValidateCustomFieldID() []gin.HandlerFunc {
+	return validateIDParam("customFieldID")
+}
+
+func ValidateNotificationID() []gin.HandlerFunc {
+	return validateIDParam("notificationID")
+}
+
+func ValidateWebhookID() []gin.HandlerFunc {
+	return validateIDParam("webhookID")
+}
+
+func ValidateAutomationRuleID() []gin.HandlerFunc {
+	return validateIDParam("automationRuleID")
+}
+
+// Binds the :id and :attachmentId URI parameters
+func ValidateAttachmentParams() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var param models.AttachmentParam
+
+			if err := c.ShouldBindUri(&param); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid ID parameter",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			// Store validated IDs in context
+			c.Set("taskID", param.TaskID)
+			c.Set("attachmentID", param.AttachmentID)
+			c.Next()
+		},
+	}
+}
+
+// Binds the :id URI parameter and stores it in context under contextKey
+func validateIDParam(contextKey string) []gin.HandlerFunc {
 	return []gin.HandlerFunc{
 		func(c *gin.Context) {
 			var param models.TaskIDParam
@@ -23,7 +68,7 @@ func ValidateTaskID() []gin.HandlerFunc {
 			}
 			
 			// Store validated ID in context
-			c.Set("taskID", param.ID)
+			c.Set(contextKey, param.ID)
 			c.Next()
 		},
 	}
@@ -43,6 +88,27 @@ func ValidateTaskQuery() []gin.HandlerFunc {
 				return
 			}
 			
+			if query.SortBy != "" && !models.IsValidTaskSortField(query.SortBy) {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid query parameters",
+					Message: "sort_by must be one of id, title, status, created_at, updated_at, rank or cf.<field key>",
+				})
+				c.Abort()
+				return
+			}
+
+			// Collect custom field filters (cf.<key>=value)
+			for name, values := range c.Request.URL.Query() {
+				key, ok := strings.CutPrefix(name, models.CustomFieldQueryPrefix)
+				if !ok || len(values) == 0 {
+					continue
+				}
+				if query.CustomFields == nil {
+					query.CustomFields = make(map[string]string)
+				}
+				query.CustomFields[key] = values[0]
+			}
+
 			query.SetDefaults()
 			
 			// Store in context
@@ -109,6 +175,341 @@ func ValidateUpdateTaskBody() []gin.HandlerFunc {
 	}
 }
 
+func ValidateCreateCustomFieldBody() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var req models.CreateCustomFieldRequest
+
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			req.Key = strings.TrimSpace(req.Key)
+			req.Name = strings.TrimSpace(req.Name)
+
+			// Store in context
+			c.Set("createCustomFieldReq", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateMoveTaskBody() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var req models.MoveTaskRequest
+
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			// Store in context
+			c.Set("moveTaskReq", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateBoardQuery() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var query models.BoardQueryParams
+
+			if err := c.ShouldBindQuery(&query); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid query parameters",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			query.SetDefaults()
+
+			// Store in context
+			c.Set("boardQuery", query)
+			c.Next()
+		},
+	}
+}
+
+func ValidateUpdateBoardColumn() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var param models.BoardColumnParam
+
+			if err := c.ShouldBindUri(&param); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid status parameter",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			var req models.UpdateBoardColumnRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			// Store in context
+			c.Set("boardColumnStatus", param.Status)
+			c.Set("updateBoardColumnReq", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateCreateUserBody() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var req models.CreateUserRequest
+
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			req.Username = strings.TrimSpace(req.Username)
+			req.DisplayName = strings.TrimSpace(req.DisplayName)
+
+			// Store in context
+			c.Set("createUserReq", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateNotificationQuery() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var query models.NotificationQueryParams
+
+			if err := c.ShouldBindQuery(&query); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid query parameters",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			query.SetDefaults()
+
+			// Store in context
+			c.Set("notificationQuery", query)
+			c.Next()
+		},
+	}
+}
+
+func ValidateMentionQuery() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var query models.MentionQueryParams
+
+			if err := c.ShouldBindQuery(&query); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid query parameters",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			query.SetDefaults()
+
+			// Store in context
+			c.Set("mentionQuery", query)
+			c.Next()
+		},
+	}
+}
+
+func ValidateUpdateDigestPreferencesBody() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var req models.UpdateDigestPreferencesRequest
+
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			// Store in context
+			c.Set("updateDigestPreferencesReq", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateSlashCommand() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var req models.SlashCommandRequest
+
+			if err := c.ShouldBind(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			// Store in context
+			c.Set("slashCommand", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateCreateWebhookBody() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var req models.CreateWebhookRequest
+
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			req.URL = strings.TrimSpace(req.URL)
+
+			// Store in context
+			c.Set("createWebhookReq", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateCreateAutomationRuleBody() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var req models.CreateAutomationRuleRequest
+
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			// Store in context
+			c.Set("createAutomationRuleReq", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateAutomationDryRunBody() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var req models.AutomationDryRunRequest
+
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid request body",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			// Store in context
+			c.Set("automationDryRunReq", req)
+			c.Next()
+		},
+	}
+}
+
+func ValidateWebhookDeliveryQuery() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var query models.WebhookDeliveryQueryParams
+
+			if err := c.ShouldBindQuery(&query); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid query parameters",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			query.SetDefaults()
+
+			// Store in context
+			c.Set("webhookDeliveryQuery", query)
+			c.Next()
+		},
+	}
+}
+
+func ValidateTaskStreamQuery() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		func(c *gin.Context) {
+			var query models.TaskStreamQueryParams
+
+			if err := c.ShouldBindQuery(&query); err != nil {
+				c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   "Invalid query parameters",
+					Message: err.Error(),
+				})
+				c.Abort()
+				return
+			}
+
+			// EventSource sends the ID of the last event it received when reconnecting
+			if header := c.GetHeader("Last-Event-ID"); header != "" {
+				lastEventID, err := strconv.ParseInt(header, 10, 64)
+				if err != nil || lastEventID < 0 {
+					c.IndentedJSON(http.StatusBadRequest, models.ErrorResponse{
+						Error:   "Invalid Last-Event-ID header",
+						Message: "must be a non-negative event ID",
+					})
+					c.Abort()
+					return
+				}
+				query.LastEventID = lastEventID
+			}
+
+			// Store in context
+			c.Set("taskStreamQuery", query)
+			c.Next()
+		},
+	}
+}
+
 // Helper functions for handlers to extract validated data
 func GetTaskID(c *gin.Context) int {
 	return c.MustGet("taskID").(int)
@@ -124,4 +525,83 @@ func GetCreateTaskRequest(c *gin.Context) models.CreateTaskRequest {
 
 func GetUpdateTaskRequest(c *gin.Context) models.UpdateTaskRequest {
 	return c.MustGet("updateTaskReq").(models.UpdateTaskRequest)
-}
\ No newline at end of file
+}
+
+func GetMoveTaskRequest(c *gin.Context) models.MoveTaskRequest {
+	return c.MustGet("moveTaskReq").(models.MoveTaskRequest)
+}
+
+func GetCustomFieldID(c *gin.Context) int {
+	return c.MustGet("customFieldID").(int)
+}
+
+func GetCreateCustomFieldRequest(c *gin.Context) models.CreateCustomFieldRequest {
+	return c.MustGet("createCustomFieldReq").(models.CreateCustomFieldRequest)
+}
+
+func GetBoardQuery(c *gin.Context) models.BoardQueryParams {
+	return c.MustGet("boardQuery").(models.BoardQueryParams)
+}
+
+func GetBoardColumnStatus(c *gin.Context) string {
+	return c.MustGet("boardColumnStatus").(string)
+}
+
+func GetUpdateBoardColumnRequest(c *gin.Context) models.UpdateBoardColumnRequest {
+	return c.MustGet("updateBoardColumnReq").(models.UpdateBoardColumnRequest)
+}
+
+func GetCreateUserRequest(c *gin.Context) models.CreateUserRequest {
+	return c.MustGet("createUserReq").(models.CreateUserRequest)
+}
+
+func GetNotificationID(c *gin.Context) int {
+	return c.MustGet("notificationID").(int)
+}
+
+func GetNotificationQuery(c *gin.Context) models.NotificationQueryParams {
+	return c.MustGet("notificationQuery").(models.NotificationQueryParams)
+}
+
+func GetMentionQuery(c *gin.Context) models.MentionQueryParams {
+	return c.MustGet("mentionQuery").(models.MentionQueryParams)
+}
+
+func GetWebhookID(c *gin.Context) int {
+	return c.MustGet("webhookID").(int)
+}
+
+func GetCreateWebhookRequest(c *gin.Context) models.CreateWebhookRequest {
+	return c.MustGet("createWebhookReq").(models.CreateWebhookRequest)
+}
+
+func GetWebhookDeliveryQuery(c *gin.Context) models.WebhookDeliveryQueryParams {
+	return c.MustGet("webhookDeliveryQuery").(models.WebhookDeliveryQueryParams)
+}
+
+func GetTaskStreamQuery(c *gin.Context) models.TaskStreamQueryParams {
+	return c.MustGet("taskStreamQuery").(models.TaskStreamQueryParams)
+}
+func GetAutomationRuleID(c *gin.Context) int {
+	return c.MustGet("automationRuleID").(int)
+}
+
+func GetCreateAutomationRuleRequest(c *gin.Context) models.CreateAutomationRuleRequest {
+	return c.MustGet("createAutomationRuleReq").(models.CreateAutomationRuleRequest)
+}
+
+func GetAutomationDryRunRequest(c *gin.Context) models.AutomationDryRunRequest {
+	return c.MustGet("automationDryRunReq").(models.AutomationDryRunRequest)
+}
+
+func GetAttachmentID(c *gin.Context) int {
+	return c.MustGet("attachmentID").(int)
+}
+
+func GetUpdateDigestPreferencesRequest(c *gin.Context) models.UpdateDigestPreferencesRequest {
+	return c.MustGet("updateDigestPreferencesReq").(models.UpdateDigestPreferencesRequest)
+}
+
+func GetSlashCommand(c *gin.Context) models.SlashCommandRequest {
+	return c.MustGet("slashCommand").(models.SlashCommandRequest)
+}
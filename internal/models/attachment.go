@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// A file attached to a task. Data is only loaded for downloads.
+type TaskAttachment struct {
+	ID          int       `json:"id" db:"id"`
+	TaskID      int       `json:"task_id" db:"task_id"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int       `json:"size" db:"size"`
+	Source      string    `json:"source" db:"source"` // Where it came from, e.g. the sender of an email
+	Data        []byte    `json:"-" db:"data"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Condition operators
+const (
+	RuleOpEquals    = "equals"
+	RuleOpNotEquals = "not_equals"
+	RuleOpContains  = "contains" // Case-insensitive substring
+	RuleOpIn        = "in"       // Value is a list
+	RuleOpExists    = "exists"   // The field has a non-empty value
+	RuleOpChanged   = "changed"  // The field changed in this event
+)
+
+// Action types
+const (
+	RuleActionSetStatus   = "set_status"
+	RuleActionCreateTask  = "create_task"
+	RuleActionFireWebhook = "fire_webhook"
+)
+
+// Event type of the webhook deliveries queued by fire_webhook actions
+const AutomationWebhookEvent = "automation.rule_fired"
+
+// Fields a condition can test besides custom fields (cf.<key>)
+var ruleConditionFields = map[string]bool{
+	"title":           true,
+	"description":     true,
+	"status":          true,
+	"previous_status": true,
+}
+
+// Runs its actions whenever an event of the trigger type matches every condition
+type AutomationRule struct {
+	ID         int             `json:"id" db:"id"`
+	Name       string          `json:"name" db:"name"`
+	Trigger    TaskEventType   `json:"trigger" db:"trigger_event"`
+	Conditions []RuleCondition `json:"conditions" db:"conditions"`
+	Actions    []RuleAction    `json:"actions" db:"actions"`
+	Enabled    bool            `json:"enabled" db:"enabled"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Tests a task field: title, description, status, previous_status or cf.<key>
+type RuleCondition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value,omitempty"`
+}
+
+// Title and description of create_task may refer to the triggering task as {{id}},
+// {{title}} and {{status}}
+type RuleAction struct {
+	Type        string `json:"type"`
+	Status      string `json:"status,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	WebhookID   int    `json:"webhook_id,omitempty"`
+}
+
+func (r AutomationRule) Matches(event TaskEvent) bool {
+	if !r.Enabled || event.Type != r.Trigger {
+		return false
+	}
+	for _, condition := range r.Conditions {
+		if !condition.Matches(event) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c RuleCondition) Matches(event TaskEvent) bool {
+	if c.Operator == RuleOpChanged {
+		for _, change := range event.Changes {
+			if change.Field == c.Field || (c.Field == "previous_status" && change.Field == "status") {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, ok := ruleFieldValue(c.Field, event)
+	switch c.Operator {
+	case RuleOpExists:
+		return ok && ruleValueString(value) != ""
+	case RuleOpEquals:
+		return ok && ruleValueString(value) == ruleValueString(c.Value)
+	case RuleOpNotEquals:
+		return !ok || ruleValueString(value) != ruleValueString(c.Value)
+	case RuleOpContains:
+		return ok && strings.Contains(strings.ToLower(ruleValueString(value)), strings.ToLower(ruleValueString(c.Value)))
+	case RuleOpIn:
+		options, _ := c.Value.([]any)
+		for _, option := range options {
+			if ok && ruleValueString(value) == ruleValueString(option) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func (c RuleCondition) Validate() error {
+	if !ruleConditionFields[c.Field] {
+		key, isCustom := strings.CutPrefix(c.Field, CustomFieldQueryPrefix)
+		if !isCustom || !IsValidCustomFieldKey(key) {
+			return ValidationError{Field: "conditions", Message: fmt.Sprintf("unknown field '%s'", c.Field)}
+		}
+	}
+
+	switch c.Operator {
+	case RuleOpExists, RuleOpChanged:
+	case RuleOpEquals, RuleOpNotEquals, RuleOpContains:
+		if c.Value == nil {
+			return ValidationError{Field: "conditions", Message: fmt.Sprintf("operator '%s' needs a value", c.Operator)}
+		}
+	case RuleOpIn:
+		if options, ok := c.Value.([]any); !ok || len(options) == 0 {
+			return ValidationError{Field: "conditions", Message: "operator 'in' needs a non-empty list value"}
+		}
+	default:
+		return ValidationError{Field: "conditions", Message: fmt.Sprintf("unknown operator '%s'", c.Operator)}
+	}
+	return nil
+}
+
+func (a RuleAction) Validate() error {
+	switch a.Type {
+	case RuleActionSetStatus:
+		if !TaskStatus(a.Status).IsValid() {
+			return ValidationError{Field: "actions", Message: fmt.Sprintf("set_status needs a valid status, got '%s'", a.Status)}
+		}
+	case RuleActionCreateTask:
+		if strings.TrimSpace(a.Title) == "" {
+			return ValidationError{Field: "actions", Message: "create_task needs a title"}
+		}
+		if a.Status != "" && !TaskStatus(a.Status).IsValid() {
+			return ValidationError{Field: "actions", Message: fmt.Sprintf("unknown status '%s'", a.Status)}
+		}
+	case RuleActionFireWebhook:
+		if a.WebhookID < 1 {
+			return ValidationError{Field: "actions", Message: "fire_webhook needs a webhook_id"}
+		}
+	default:
+		return ValidationError{Field: "actions", Message: fmt.Sprintf("unknown action type '%s'", a.Type)}
+	}
+	return nil
+}
+
+// Fills in the {{placeholders}} of a create_task action from the triggering task
+func (a RuleAction) Render(task Task) RuleAction {
+	replacer := strings.NewReplacer(
+		"{{id}}", strconv.Itoa(task.ID),
+		"{{title}}", task.Title,
+		"{{status}}", string(task.Status),
+	)
+	a.Title = replacer.Replace(a.Title)
+	a.Description = replacer.Replace(a.Description)
+	return a
+}
+
+func ruleFieldValue(field string, event TaskEvent) (any, bool) {
+	switch field {
+	case "title":
+		return event.Task.Title, true
+	case "description":
+		return event.Task.Description, true
+	case "status":
+		return string(event.Task.Status), true
+	case "previous_status":
+		if event.Previous == nil {
+			return nil, false
+		}
+		return string(event.Previous.Status), true
+	}
+
+	key, _ := strings.CutPrefix(field, CustomFieldQueryPrefix)
+	value, ok := event.Task.CustomFields[key]
+	return value, ok && value != nil
+}
+
+// Compares values by their text so that JSON numbers, booleans and strings match
+// however they were written in the rule
+func ruleValueString(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,27 @@
+package models
+
+// Per-column board settings
+type BoardColumnSettings struct {
+	Status   TaskStatus `json:"status" db:"status"`
+	WIPLimit *int       `json:"wip_limit" db:"wip_limit"`
+}
+
+type BoardColumn struct {
+	Status      TaskStatus `json:"status"`
+	Count       int        `json:"count"`
+	WIPLimit    *int       `json:"wip_limit"`
+	WIPExceeded bool       `json:"wip_exceeded"`
+	Tasks       []Task     `json:"tasks"`
+	HasMore     bool       `json:"has_more"`
+	NextCursor  string     `json:"next_cursor,omitempty"`
+}
+
+type Board struct {
+	Columns []BoardColumn `json:"columns"`
+}
+
+// Keyset position within a column; encoded as an opaque cursor for clients
+type BoardCursor struct {
+	Rank string `json:"r"`
+	ID   int    `json:"i"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	FieldTypeText    CustomFieldType = "text"
+	FieldTypeNumber  CustomFieldType = "number"
+	FieldTypeDate    CustomFieldType = "date"
+	FieldTypeEnum    CustomFieldType = "enum"
+	FieldTypeBoolean CustomFieldType = "boolean"
+)
+
+// Date values are stored as ISO dates so they sort correctly as strings
+const CustomFieldDateLayout = "2006-01-02"
+
+// Query parameter prefix used to filter and sort by custom fields, e.g. cf.severity=high
+const CustomFieldQueryPrefix = "cf."
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// Longest key a new field may have. Its index is named "idx_tasks_cf_<key>", which must
+// fit Postgres' 63-byte identifier limit; longer names are truncated and can collide.
+const MaxCustomFieldKeyLength = 50
+
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case FieldTypeText, FieldTypeNumber, FieldTypeDate, FieldTypeEnum, FieldTypeBoolean:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t CustomFieldType) String() string {
+	return string(t)
+}
+
+// Keys end up in index names and JSONB paths, so they are restricted to a safe identifier set
+func IsValidCustomFieldKey(key string) bool {
+	return customFieldKeyPattern.MatchString(key)
+}
+
+// Like IsValidCustomFieldKey, but also holds the key to MaxCustomFieldKeyLength, for new fields
+func IsValidNewCustomFieldKey(key string) bool {
+	return IsValidCustomFieldKey(key) && len(key) <= MaxCustomFieldKeyLength
+}
+
+type CustomFieldDefinition struct {
+	ID        int             `json:"id" db:"id"`
+	Key       string          `json:"key" db:"key"`
+	Name      string          `json:"name" db:"name"`
+	Type      CustomFieldType `json:"type" db:"type"`
+	Options   []string        `json:"options,omitempty" db:"options"`
+	Required  bool            `json:"required" db:"required"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+func (d CustomFieldDefinition) HasOption(value string) bool {
+	for _, option := range d.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
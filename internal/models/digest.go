@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// What a user wants in their digest and when. Empty statuses means every status.
+type DigestPreferences struct {
+	UserID       int             `json:"user_id" db:"user_id"`
+	Frequency    DigestFrequency `json:"frequency" db:"frequency"`
+	Statuses     []TaskStatus    `json:"statuses" db:"statuses"`
+	WatchedOnly  bool            `json:"watched_only" db:"watched_only"`
+	LastSentAt   *time.Time      `json:"last_sent_at" db:"last_sent_at"`
+	NextDigestAt *time.Time      `json:"next_digest_at" db:"next_digest_at"`
+	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Preferences of a user who has never saved any
+func DefaultDigestPreferences(userID int) DigestPreferences {
+	return DigestPreferences{
+		UserID:    userID,
+		Frequency: DigestOff,
+		Statuses:  []TaskStatus{},
+	}
+}
+
+// Length of the window a digest of this frequency covers
+func (f DigestFrequency) Period() time.Duration {
+	if f == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// When digests go out, in UTC: daily ones at Hour, weekly ones at Hour on Weekday
+type DigestSchedule struct {
+	Hour    int
+	Weekday time.Weekday
+}
+
+// The first send time of the frequency strictly after the given time
+func (s DigestSchedule) Next(frequency DigestFrequency, after time.Time) time.Time {
+	after = after.UTC()
+	next := time.Date(after.Year(), after.Month(), after.Day(), s.Hour, 0, 0, 0, time.UTC)
+	if !next.After(after) {
+		next = next.AddDate(0, 0, 1)
+	}
+	if frequency == DigestWeekly {
+		next = next.AddDate(0, 0, (int(s.Weekday)-int(next.Weekday())+7)%7)
+	}
+	return next
+}
+
+// A claimed digest together with who it goes to
+type DigestJob struct {
+	Preferences DigestPreferences
+	Recipient   User
+}
+
+// The content of one digest email
+type Digest struct {
+	Recipient User
+	Frequency DigestFrequency
+	Since     time.Time
+	Until     time.Time
+	Changed   []Task // Updated within the window, most recent first
+	Due       []Task // Open tasks due before the next digest, soonest first
+	DueField  string // Custom field holding the due date
+}
+
+func (d Digest) IsEmpty() bool {
+	return len(d.Changed) == 0 && len(d.Due) == 0
+}
@@ -25,4 +25,22 @@ type BusinessError struct {
 
 func (e BusinessError) Error() string {
 	return e.Message
+}
+
+// Generic not-found error for resources other than tasks
+type NotFoundError struct {
+	Resource string
+	ID       int
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("%s with id %d not found", e.Resource, e.ID)
+}
+
+type UnauthorizedError struct {
+	Message string
+}
+
+func (e UnauthorizedError) Error() string {
+	return e.Message
 }
\ No newline at end of file
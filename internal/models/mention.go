@@ -0,0 +1,75 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	MentionSourceDescription = "description"
+	mentionExcerptLength     = 200
+)
+
+// A handle is "@" followed by a username, and must not be glued to a preceding word,
+// so addresses like bob@example.com are not mentions
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@-])@([A-Za-z0-9_][A-Za-z0-9_.-]*)`)
+
+type Mention struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	TaskID    int       `json:"task_id" db:"task_id"`
+	TaskTitle string    `json:"task_title" db:"task_title"`
+	Source    string    `json:"source" db:"source"`
+	Excerpt   string    `json:"excerpt" db:"excerpt"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Returns the distinct handles mentioned in text, lowercased, in order of first appearance.
+// Trailing dots are sentence punctuation rather than part of the handle.
+func ParseMentions(text string) []string {
+	handles := []string{}
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[1], "."))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
+
+// Returns the handles mentioned in after but not in before
+func NewMentions(before, after string) []string {
+	previous := make(map[string]bool)
+	for _, handle := range ParseMentions(before) {
+		previous[handle] = true
+	}
+
+	added := []string{}
+	for _, handle := range ParseMentions(after) {
+		if !previous[handle] {
+			added = append(added, handle)
+		}
+	}
+	return added
+}
+
+// Returns the line of text mentioning handle, shortened to a readable length
+func MentionExcerpt(text, handle string) string {
+	line := text
+	for _, candidate := range strings.Split(text, "\n") {
+		if strings.Contains(strings.ToLower(candidate), "@"+handle) {
+			line = candidate
+			break
+		}
+	}
+
+	line = strings.TrimSpace(line)
+	if runes := []rune(line); len(runes) > mentionExcerptLength {
+		line = string(runes[:mentionExcerptLength-3]) + "..."
+	}
+	return line
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type Notification struct {
+	ID        int           `json:"id" db:"id"`
+	UserID    int           `json:"user_id" db:"user_id"`
+	TaskID    int           `json:"task_id" db:"task_id"`
+	Event     TaskEventType `json:"event" db:"event"`
+	Message   string        `json:"message" db:"message"`
+	Changes   []FieldChange `json:"changes,omitempty" db:"changes"`
+	ReadAt    *time.Time    `json:"read_at" db:"read_at"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
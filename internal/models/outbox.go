@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// A task event stored in the same transaction as the change it describes, waiting
+// to be relayed to the outbox sinks
+type OutboxMessage struct {
+	ID          int64           `json:"id" db:"id"`
+	AggregateID int             `json:"aggregate_id" db:"aggregate_id"`
+	EventType   TaskEventType   `json:"event_type" db:"event_type"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	PublishedAt *time.Time      `json:"published_at" db:"published_at"`
+}
+
+// Decodes the stored event, tagging it with the message ID
+func (m OutboxMessage) Event() (TaskEvent, error) {
+	var event TaskEvent
+	if err := json.Unmarshal(m.Payload, &event); err != nil {
+		return TaskEvent{}, err
+	}
+	event.ID = m.ID
+	return event, nil
+}
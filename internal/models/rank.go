@@ -0,0 +1,216 @@
+package models
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Ranks order tasks within a status column. They have the form "<bucket>:<value>" and are
+// compared byte-wise (the column uses the "C" collation). Values are base-36 fractions, so a
+// value strictly between any two others always exists; it just gets longer. When a value grows
+// past RankRebalanceLength the column is rebalanced in the background into the next bucket.
+const (
+	rankAlphabet         = "0123456789abcdefghijklmnopqrstuvwxyz"
+	rankBase             = len(rankAlphabet)
+	rankSeparator        = ':'
+	RankBucketCount      = 3
+	RankRebalanceLength  = 16
+	MaxRankValueLength   = 128
+	rankEvenSpacingWidth = 6
+)
+
+type RankExhaustedError struct {
+	Status TaskStatus
+}
+
+func (e RankExhaustedError) Error() string {
+	return fmt.Sprintf("no rank space left in column '%s', it is being rebalanced", e.Status)
+}
+
+// Splits a rank into its bucket and value
+func ParseRank(rank string) (int, string, error) {
+	if len(rank) < 3 || rank[1] != rankSeparator || rank[0] < '0' || rank[0] >= '0'+RankBucketCount {
+		return 0, "", fmt.Errorf("malformed rank %q", rank)
+	}
+	value := rank[2:]
+	for i := 0; i < len(value); i++ {
+		if strings.IndexByte(rankAlphabet, value[i]) < 0 {
+			return 0, "", fmt.Errorf("malformed rank %q", rank)
+		}
+	}
+	return int(rank[0] - '0'), value, nil
+}
+
+func FormatRank(bucket int, value string) string {
+	return string(rune('0'+bucket)) + string(rankSeparator) + value
+}
+
+// Returns the half-open range [lower, upper) containing every rank in a bucket
+func RankBucketRange(bucket int) (string, string) {
+	return FormatRank(bucket, ""), FormatRank(bucket, "")[:1] + string(rankSeparator+1)
+}
+
+// Returns a rank strictly between prev and next; an empty bound is open-ended
+func RankBetween(prev, next string) (string, error) {
+	switch {
+	case prev == "" && next == "":
+		return FormatRank(0, midRankValue("", "")), nil
+
+	case prev == "":
+		bucket, value, err := ParseRank(next)
+		if err != nil {
+			return "", err
+		}
+		return FormatRank(bucket, midRankValue("", value)), nil
+
+	case next == "":
+		bucket, value, err := ParseRank(prev)
+		if err != nil {
+			return "", err
+		}
+		return FormatRank(bucket, midRankValue(value, "")), nil
+	}
+
+	prevBucket, prevValue, err := ParseRank(prev)
+	if err != nil {
+		return "", err
+	}
+	nextBucket, nextValue, err := ParseRank(next)
+	if err != nil {
+		return "", err
+	}
+	if prev >= next {
+		return "", fmt.Errorf("rank %q is not before %q", prev, next)
+	}
+
+	// Neighbors straddle a bucket boundary while a rebalance is running;
+	// anything after prev in its own bucket still sorts before next
+	if prevBucket != nextBucket {
+		return FormatRank(prevBucket, midRankValue(prevValue, "")), nil
+	}
+	return FormatRank(prevBucket, midRankValue(prevValue, nextValue)), nil
+}
+
+// Reports whether a rank has grown long enough that its column should be rebalanced
+func RankNeedsRebalance(rank string) bool {
+	_, value, err := ParseRank(rank)
+	return err == nil && len(value) > RankRebalanceLength
+}
+
+// Reports whether a rank is too long to be stored
+func RankExhausted(rank string) bool {
+	_, value, err := ParseRank(rank)
+	return err != nil || len(value) > MaxRankValueLength
+}
+
+// Chooses the source and target buckets for a rebalance given the buckets present in a
+// column. Two adjacent buckets mean an earlier rebalance was interrupted and is resumed.
+// Moving to a higher bucket migrates from the tail of the column, moving to a lower one
+// from the head, so the column stays correctly ordered at every step.
+func RankRebalanceBuckets(present []int) (from, to int, tailFirst bool) {
+	has := make(map[int]bool)
+	for _, bucket := range present {
+		has[bucket] = true
+	}
+
+	// The source is the present bucket whose predecessor is absent
+	from = 0
+	for bucket := 0; bucket < RankBucketCount; bucket++ {
+		if has[bucket] && !has[(bucket+RankBucketCount-1)%RankBucketCount] {
+			from = bucket
+			break
+		}
+	}
+	to = (from + 1) % RankBucketCount
+	return from, to, to > from
+}
+
+// Returns the value of the i-th (1-based) of n evenly spaced ranks
+func EvenRankValue(i, n int) string {
+	return EvenRankValueBetween("", "", i, n)
+}
+
+// Returns the value of the i-th (1-based) of n ranks spaced evenly strictly between the
+// values lower and upper; an empty bound is open-ended. A rebalance that is resumed
+// spaces the rest of the column beyond the values it has already assigned.
+func EvenRankValueBetween(lower, upper string, i, n int) string {
+	width := rankEvenSpacingWidth
+	var low, high, step *big.Int
+	for {
+		// lower is rounded up and upper down to width digits, so the values stay strictly inside
+		low = rankFraction(lower, width)
+		if len(lower) > width {
+			low.Add(low, big.NewInt(1))
+		}
+		high = rankFraction(upper, width)
+		if upper == "" {
+			high.Exp(big.NewInt(int64(rankBase)), big.NewInt(int64(width)), nil)
+		}
+		step = new(big.Int).Sub(high, low)
+		step.Quo(step, big.NewInt(int64(n+1)))
+		if step.Sign() > 0 {
+			break
+		}
+		width++
+	}
+
+	value := step.Mul(step, big.NewInt(int64(i)))
+	value.Add(value, low)
+	digits := make([]byte, width)
+	base := big.NewInt(int64(rankBase))
+	digit := new(big.Int)
+	for j := width - 1; j >= 0; j-- {
+		value.QuoRem(value, base, digit)
+		digits[j] = rankAlphabet[digit.Int64()]
+	}
+
+	// A trailing midpoint digit keeps values from ending in zero, so there is always room below them
+	return string(digits) + string(rankAlphabet[rankBase/2])
+}
+
+// Returns the first width digits of a value as an integer, padding it with zeros
+func rankFraction(value string, width int) *big.Int {
+	fraction := new(big.Int)
+	base := big.NewInt(int64(rankBase))
+	for j := 0; j < width; j++ {
+		digit := 0
+		if j < len(value) {
+			digit = strings.IndexByte(rankAlphabet, value[j])
+		}
+		fraction.Mul(fraction, base)
+		fraction.Add(fraction, big.NewInt(int64(digit)))
+	}
+	return fraction
+}
+
+// Returns a value strictly between a and b; an empty b is unbounded above.
+// Generated values never end in '0', which guarantees a lower neighbor always exists.
+func midRankValue(a, b string) string {
+	var out []byte
+	upperBounded := b != ""
+
+	for i := 0; ; i++ {
+		da := 0
+		if i < len(a) {
+			da = strings.IndexByte(rankAlphabet, a[i])
+		}
+		db := rankBase
+		if upperBounded && i < len(b) {
+			db = strings.IndexByte(rankAlphabet, b[i])
+		}
+
+		if da == db {
+			out = append(out, rankAlphabet[da])
+			continue
+		}
+		if db-da > 1 {
+			out = append(out, rankAlphabet[(da+db)/2])
+			return string(out)
+		}
+
+		// Adjacent digits: keep a's digit, anything above a's remainder now fits
+		out = append(out, rankAlphabet[da])
+		upperBounded = false
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMidRankValue(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected string
+	}{
+		{"", "", "i"},
+		{"", "i", "9"},
+		{"i", "", "r"},
+		{"a", "c", "b"},
+		{"a", "b", "ai"},   // Adjacent digits
+		{"az", "b", "azi"}, // Nothing fits above a's last digit
+		{"1", "10001", "10000i"},
+		{"", "01", "00i"},
+	}
+
+	for _, test := range tests {
+		value := midRankValue(test.a, test.b)
+		assert.Equal(t, test.expected, value, "midRankValue(%q, %q)", test.a, test.b)
+		assert.Greater(t, value, test.a)
+		if test.b != "" {
+			assert.Less(t, value, test.b)
+		}
+		assert.NotEqual(t, byte('0'), value[len(value)-1])
+	}
+}
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next string
+		expected   string
+	}{
+		{"empty column", "", "", "0:i"},
+		{"head of column", "", "1:i", "1:9"},
+		{"tail of column", "1:i", "", "1:r"},
+		{"between neighbors", "1:a", "1:c", "1:b"},
+		{"across buckets while rebalancing", "0:x", "1:5", "0:y"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rank, err := RankBetween(test.prev, test.next)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, rank)
+		})
+	}
+
+	_, err := RankBetween("1:c", "1:a")
+	assert.Error(t, err, "out of order neighbors")
+	_, err = RankBetween("1:a", "3:c")
+	assert.Error(t, err, "malformed rank")
+}
+
+func TestRankRebalanceBuckets(t *testing.T) {
+	tests := []struct {
+		present   []int
+		from, to  int
+		tailFirst bool
+	}{
+		{[]int{0}, 0, 1, true},
+		{[]int{1}, 1, 2, true},
+		{[]int{2}, 2, 0, false},
+		{[]int{0, 1}, 0, 1, true}, // Resuming
+		{[]int{1, 2}, 1, 2, true},
+		{[]int{2, 0}, 2, 0, false},
+	}
+
+	for _, test := range tests {
+		from, to, tailFirst := RankRebalanceBuckets(test.present)
+		assert.Equal(t, []any{test.from, test.to, test.tailFirst}, []any{from, to, tailFirst}, "buckets %v", test.present)
+	}
+}
+
+func TestEvenRankValue(t *testing.T) {
+	for _, n := range []int{1, 2, 100, 10000} {
+		previous := ""
+		for i := 1; i <= n; i++ {
+			value := EvenRankValue(i, n)
+			require.Greater(t, value, previous, "value %d of %d", i, n)
+			previous = value
+		}
+	}
+
+	// Columns too long for the default width get longer values
+	assert.Len(t, EvenRankValue(1, 3_000_000_000), rankEvenSpacingWidth+2)
+}
+
+func TestEvenRankValueBetween(t *testing.T) {
+	tests := []struct {
+		name         string
+		lower, upper string
+		n            int
+	}{
+		{"open-ended", "", "", 50},
+		{"below a value", "", "i1m65ci", 200},
+		{"above a value", "i1m65ci", "", 200},
+		{"between close values", "a0000001", "a0000002", 100},
+		{"below a long value", "", "00000000001", 10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			previous := test.lower
+			for i := 1; i <= test.n; i++ {
+				value := EvenRankValueBetween(test.lower, test.upper, i, test.n)
+				require.Greater(t, value, previous, "value %d", i)
+				if test.upper != "" {
+					require.Less(t, value, test.upper, "value %d", i)
+				}
+				previous = value
+			}
+		})
+	}
+}
+
+// Replays a rebalance of a column interrupted halfway and checks the resumed ranks sort
+// correctly against the ones already migrated
+func TestEvenRankValueBetween_ResumedRebalance(t *testing.T) {
+	const total, done = 400, 200
+
+	// Migrating to a higher bucket starts from the tail, so the resumed ranks go below
+	migrated := make([]string, 0, done)
+	for position := total; position > total-done; position-- {
+		migrated = append(migrated, FormatRank(1, EvenRankValue(position, total)))
+	}
+	lowest := migrated[len(migrated)-1]
+	_, edge, err := ParseRank(lowest)
+	require.NoError(t, err)
+	for position := total - done; position >= 1; position-- {
+		rank := FormatRank(1, EvenRankValueBetween("", edge, position, total-done))
+		require.Less(t, rank, lowest, "resumed position %d", position)
+		lowest = rank
+	}
+
+	// Migrating to a lower bucket starts from the head, so they go above
+	migrated = migrated[:0]
+	for position := 1; position <= done; position++ {
+		migrated = append(migrated, FormatRank(0, EvenRankValue(position, total)))
+	}
+	highest := migrated[len(migrated)-1]
+	_, edge, err = ParseRank(highest)
+	require.NoError(t, err)
+	for position := 1; position <= total-done; position++ {
+		rank := FormatRank(0, EvenRankValueBetween(edge, "", position, total-done))
+		require.Greater(t, rank, highest, "resumed position %d", position)
+		highest = rank
+	}
+}
@@ -1,25 +1,67 @@
 package models
 
+import "strings"
+
 // Task-related requests
 type CreateTaskRequest struct {
-	Title       string `json:"title" binding:"required,min=1,max=255"`
-	Description string `json:"description" binding:"max=1000"`
-	Status      string `json:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+	Title        string         `json:"title" binding:"required,min=1,max=255"`
+	Description  string         `json:"description" binding:"max=1000"`
+	Status       string         `json:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+	CustomFields map[string]any `json:"custom_fields"`
 }
 
+// A null custom field value removes the field from the task
 type UpdateTaskRequest struct {
-	Title       string `json:"title" binding:"omitempty,min=1,max=255"`
-	Description string `json:"description" binding:"omitempty,max=1000"`
-	Status      string `json:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+	Title        string         `json:"title" binding:"omitempty,min=1,max=255"`
+	Description  string         `json:"description" binding:"omitempty,max=1000"`
+	Status       string         `json:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+	CustomFields map[string]any `json:"custom_fields"`
+}
+
+// Custom field definition requests
+type CreateCustomFieldRequest struct {
+	Key      string   `json:"key" binding:"required,min=1,max=50"`
+	Name     string   `json:"name" binding:"required,min=1,max=255"`
+	Type     string   `json:"type" binding:"required,oneof=text number date enum boolean"`
+	Options  []string `json:"options" binding:"omitempty,dive,min=1,max=255"`
+	Required bool     `json:"required"`
 }
 
 // Query parameters
+// SortBy accepts the built-in columns or a custom field as cf.<key>;
+// CustomFields holds the cf.<key>=value filters collected from the query string
 type TaskQueryParams struct {
-	Page      int    `form:"page"`
-	Limit     int    `form:"limit"`
-	Status    string `form:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
-	SortBy    string `form:"sort_by" binding:"omitempty,oneof=id title status created_at updated_at"`
-	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	Page         int               `form:"page"`
+	Limit        int               `form:"limit"`
+	Status       string            `form:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+	SortBy       string            `form:"sort_by"`
+	SortOrder    string            `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	CustomFields map[string]string `form:"-"`
+}
+
+var taskSortColumns = map[string]bool{
+	"id":         true,
+	"title":      true,
+	"status":     true,
+	"created_at": true,
+	"updated_at": true,
+	"rank":       true,
+}
+
+// Reports whether sortBy names a built-in column or a well-formed custom field
+func IsValidTaskSortField(sortBy string) bool {
+	if taskSortColumns[sortBy] {
+		return true
+	}
+	if key, ok := CustomFieldSortKey(sortBy); ok {
+		return IsValidCustomFieldKey(key)
+	}
+	return false
+}
+
+// Extracts the custom field key from a cf.<key> sort value
+func CustomFieldSortKey(sortBy string) (string, bool) {
+	return strings.CutPrefix(sortBy, CustomFieldQueryPrefix)
 }
 
 // Set defaults for query params
@@ -38,7 +80,138 @@ func (q *TaskQueryParams) SetDefaults() {
 	}
 }
 
+// Places a task after one neighbor and/or before another in the target status column.
+// With no neighbors the task goes to the end of the column.
+type MoveTaskRequest struct {
+	Before int    `json:"before" binding:"omitempty,min=1"`
+	After  int    `json:"after" binding:"omitempty,min=1"`
+	Status string `json:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+}
+
+// Board query parameters. A cursor continues a single column, so it requires status.
+type BoardQueryParams struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+	Cursor string `form:"cursor" binding:"omitempty,max=512"`
+}
+
+func (q *BoardQueryParams) SetDefaults() {
+	if q.Limit <= 0 {
+		q.Limit = 20
+	}
+}
+
+// A null or missing wip_limit removes the limit
+type UpdateBoardColumnRequest struct {
+	WIPLimit *int `json:"wip_limit" binding:"omitempty,min=1"`
+}
+
+// User requests
+type CreateUserRequest struct {
+	Username    string `json:"username" binding:"required,min=1,max=50"`
+	Email       string `json:"email" binding:"required,email,max=255"`
+	DisplayName string `json:"display_name" binding:"max=255"`
+}
+
+// Replaces the user's digest preferences; an empty statuses list means every status
+type UpdateDigestPreferencesRequest struct {
+	Frequency   string   `json:"frequency" binding:"required,oneof=off daily weekly"`
+	Statuses    []string `json:"statuses" binding:"dive,oneof=pending in_progress completed closed"`
+	WatchedOnly bool     `json:"watched_only"`
+}
+
+// Notification query parameters
+type NotificationQueryParams struct {
+	Page   int  `form:"page"`
+	Limit  int  `form:"limit"`
+	Unread bool `form:"unread"`
+}
+
+func (q *NotificationQueryParams) SetDefaults() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 20
+	}
+}
+
+// Task stream query parameters. last_event_id is an alternative to the Last-Event-ID
+// header for clients that cannot set headers.
+type TaskStreamQueryParams struct {
+	Status      string `form:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+	Type        string `form:"type" binding:"omitempty,oneof=task.created task.updated task.status_changed task.moved task.deleted"`
+	LastEventID int64  `form:"last_event_id" binding:"omitempty,min=0"`
+}
+
+// Mention query parameters
+type MentionQueryParams struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+func (q *MentionQueryParams) SetDefaults() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 20
+	}
+}
+
+// Webhook requests. Without a secret one is generated.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	Secret     string   `json:"secret" binding:"omitempty,max=255"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,required"`
+}
+
+// Webhook delivery log query parameters
+type WebhookDeliveryQueryParams struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+func (q *WebhookDeliveryQueryParams) SetDefaults() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 20
+	}
+}
+
+// Automation rule requests. Rules are enabled unless enabled is false.
+type CreateAutomationRuleRequest struct {
+	Name       string          `json:"name" binding:"required,min=1,max=255"`
+	Trigger    string          `json:"trigger" binding:"required,oneof=task.created task.updated task.status_changed task.moved task.deleted"`
+	Conditions []RuleCondition `json:"conditions"`
+	Actions    []RuleAction    `json:"actions" binding:"required,min=1"`
+	Enabled    *bool           `json:"enabled"`
+}
+
+// A change to evaluate the rules against without making it: an update of task_id
+// with the given fields, a deletion of task_id, or without task_id the creation of
+// a task with them
+type AutomationDryRunRequest struct {
+	TaskID       int            `json:"task_id" binding:"omitempty,min=1"`
+	Delete       bool           `json:"delete"`
+	Title        string         `json:"title" binding:"omitempty,max=255"`
+	Description  string         `json:"description" binding:"omitempty,max=1000"`
+	Status       string         `json:"status" binding:"omitempty,oneof=pending in_progress completed closed"`
+	CustomFields map[string]any `json:"custom_fields"`
+}
+
 // URL parameters
 type TaskIDParam struct {
 	ID int `uri:"id" binding:"required,min=1"`
+}
+
+type AttachmentParam struct {
+	TaskID       int `uri:"id" binding:"required,min=1"`
+	AttachmentID int `uri:"attachmentId" binding:"required,min=1"`
+}
+
+type BoardColumnParam struct {
+	Status string `uri:"status" binding:"required,oneof=pending in_progress completed closed"`
 }
\ No newline at end of file
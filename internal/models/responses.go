@@ -25,6 +25,22 @@ type PaginatedTasksResponse struct {
 	Pagination PaginationMeta `json:"pagination"`
 }
 
+type PaginatedMentionsResponse struct {
+	Mentions   []Mention      `json:"mentions"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+type PaginatedWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Pagination PaginationMeta    `json:"pagination"`
+}
+
+type PaginatedNotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+	Pagination    PaginationMeta `json:"pagination"`
+}
+
 // Task-specific responses
 type TaskResponse struct {
 	Message string `json:"message"`
@@ -34,4 +50,20 @@ type TaskResponse struct {
 type TasksResponse struct {
 	Message string                  `json:"message"`
 	Data    *PaginatedTasksResponse `json:"data"`
-}
\ No newline at end of file
+}
+
+type BoardResponse struct {
+	Message string `json:"message"`
+	Data    *Board `json:"data"`
+}
+// Rules that would fire for a change, with their actions as they would run
+type AutomationDryRunResponse struct {
+	Event   TaskEvent             `json:"event"`
+	Matches []AutomationRuleMatch `json:"matches"`
+}
+
+type AutomationRuleMatch struct {
+	RuleID   int          `json:"rule_id"`
+	RuleName string       `json:"rule_name"`
+	Actions  []RuleAction `json:"actions"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "strings"
+
+// Headers of a Slack-style signed request. The signature is "v0=" followed by the hex
+// HMAC-SHA256 of "v0:<timestamp>:<body>" keyed with the app's signing secret.
+const (
+	SlackSignatureHeader = "X-Slack-Signature"
+	SlackTimestampHeader = "X-Slack-Request-Timestamp"
+	SlackRetryNumHeader  = "X-Slack-Retry-Num"
+)
+
+const (
+	SlashResponseEphemeral = "ephemeral"  // Only shown to the user who ran the command
+	SlashResponseInChannel = "in_channel" // Shown to everyone in the channel
+)
+
+// Form fields posted by the chat platform when a user runs a slash command
+type SlashCommandRequest struct {
+	Command     string `form:"command" binding:"required"`
+	Text        string `form:"text"`
+	TeamID      string `form:"team_id"`
+	ChannelID   string `form:"channel_id"`
+	UserID      string `form:"user_id"`
+	UserName    string `form:"user_name"`
+	ResponseURL string `form:"response_url"`
+}
+
+// A chat message answering a command, either directly or through the response URL
+type SlashResponse struct {
+	ResponseType    string         `json:"response_type"`
+	ReplaceOriginal bool           `json:"replace_original,omitempty"`
+	Text            string         `json:"text"` // Fallback for notifications and clients without blocks
+	Blocks          []MessageBlock `json:"blocks,omitempty"`
+}
+
+// A Block Kit layout block: "header", "section", "context" or "divider"
+type MessageBlock struct {
+	Type     string      `json:"type"`
+	Text     *BlockText  `json:"text,omitempty"`
+	Fields   []BlockText `json:"fields,omitempty"`
+	Elements []BlockText `json:"elements,omitempty"`
+}
+
+// Block text; "mrkdwn" text must have &, < and > escaped
+type BlockText struct {
+	Type string `json:"type"` // "plain_text" or "mrkdwn"
+	Text string `json:"text"`
+}
+
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Escapes user text for "mrkdwn" blocks, where < and > would start links and mentions
+func EscapeMrkdwn(text string) string {
+	return mrkdwnEscaper.Replace(text)
+}
@@ -13,6 +13,9 @@ const (
 	StatusClosed     TaskStatus = "closed"
 )
 
+// Statuses in the order tasks move through them, i.e. board column order
+var WorkflowStatuses = []TaskStatus{StatusPending, StatusInProgress, StatusCompleted, StatusClosed}
+
 func (s TaskStatus) IsValid() bool {
 	switch s {
 	case StatusPending, StatusInProgress, StatusCompleted, StatusClosed:
@@ -27,10 +30,12 @@ func (s TaskStatus) String() string {
 }
 
 type Task struct {
-	ID          int        `json:"id" db:"id"`
-	Title       string     `json:"title" db:"title"`
-	Description string     `json:"description" db:"description"`
-	Status      TaskStatus `json:"status" db:"status"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID           int            `json:"id" db:"id"`
+	Title        string         `json:"title" db:"title"`
+	Description  string         `json:"description" db:"description"`
+	Status       TaskStatus     `json:"status" db:"status"`
+	CustomFields map[string]any `json:"custom_fields" db:"custom_fields"`
+	Rank         string         `json:"rank" db:"rank"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
 }
\ No newline at end of file
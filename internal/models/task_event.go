@@ -0,0 +1,140 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+type TaskEventType string
+
+const (
+	EventTaskCreated       TaskEventType = "task.created"
+	EventTaskUpdated       TaskEventType = "task.updated"
+	EventTaskStatusChanged TaskEventType = "task.status_changed"
+	EventTaskMoved         TaskEventType = "task.moved"
+	EventTaskDeleted       TaskEventType = "task.deleted"
+)
+
+var TaskEventTypes = []TaskEventType{
+	EventTaskCreated,
+	EventTaskUpdated,
+	EventTaskStatusChanged,
+	EventTaskMoved,
+	EventTaskDeleted,
+}
+
+func (t TaskEventType) IsValid() bool {
+	for _, eventType := range TaskEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// A change made to a task. Task is the state after the change (before it, for
+// deletions); Previous is nil for creations. ID is set for events relayed from the
+// outbox and lets consumers drop the occasional duplicate.
+type TaskEvent struct {
+	ID         int64         `json:"id,omitempty"`
+	Type       TaskEventType `json:"type"`
+	Task       Task          `json:"task"`
+	Previous   *Task         `json:"previous,omitempty"`
+	Changes    []FieldChange `json:"changes,omitempty"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// Names the kind of change between two versions of a task: a status change, a
+// reorder within the column, or any other update
+func ClassifyTaskChange(before, after Task) TaskEventType {
+	switch {
+	case before.Status != after.Status:
+		return EventTaskStatusChanged
+	case before.Rank != after.Rank && before.Title == after.Title && before.Description == after.Description &&
+		reflect.DeepEqual(before.CustomFields, after.CustomFields):
+		return EventTaskMoved
+	default:
+		return EventTaskUpdated
+	}
+}
+
+// Lists the user-visible fields that differ between two versions of a task
+func DiffTasks(before, after Task) []FieldChange {
+	changes := []FieldChange{}
+	if before.Title != after.Title {
+		changes = append(changes, FieldChange{Field: "title", From: before.Title, To: after.Title})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, FieldChange{Field: "description", From: before.Description, To: after.Description})
+	}
+	if before.Status != after.Status {
+		changes = append(changes, FieldChange{Field: "status", From: before.Status, To: after.Status})
+	}
+	if before.Rank != after.Rank {
+		changes = append(changes, FieldChange{Field: "rank", From: before.Rank, To: after.Rank})
+	}
+
+	for key, value := range after.CustomFields {
+		if previous, ok := before.CustomFields[key]; !ok || !reflect.DeepEqual(previous, value) {
+			changes = append(changes, FieldChange{Field: CustomFieldQueryPrefix + key, From: previous, To: value})
+		}
+	}
+	for key, previous := range before.CustomFields {
+		if _, ok := after.CustomFields[key]; !ok {
+			changes = append(changes, FieldChange{Field: CustomFieldQueryPrefix + key, From: previous, To: nil})
+		}
+	}
+
+	return changes
+}
+
+// One-line human readable summary of an event
+func (e TaskEvent) Summary() string {
+	switch e.Type {
+	case EventTaskCreated:
+		return fmt.Sprintf("Task '%s' was created", e.Task.Title)
+	case EventTaskDeleted:
+		return fmt.Sprintf("Task '%s' was deleted", e.Task.Title)
+	case EventTaskStatusChanged:
+		from := StatusPending
+		if e.Previous != nil {
+			from = e.Previous.Status
+		}
+		return fmt.Sprintf("Task '%s' moved from %s to %s", e.Task.Title, from, e.Task.Status)
+	case EventTaskMoved:
+		return fmt.Sprintf("Task '%s' was reordered", e.Task.Title)
+	default:
+		fields := ""
+		for i, change := range e.Changes {
+			if i > 0 {
+				fields += ", "
+			}
+			fields += change.Field
+		}
+		return fmt.Sprintf("Task '%s' was updated (%s)", e.Task.Title, fields)
+	}
+}
+
+// Selects the events a stream subscriber receives; empty fields match everything.
+// A status matches tasks entering or leaving that status as well as tasks in it.
+type TaskEventFilter struct {
+	Status TaskStatus
+	Type   TaskEventType
+}
+
+func (f TaskEventFilter) Matches(event TaskEvent) bool {
+	if f.Type != "" && event.Type != f.Type {
+		return false
+	}
+	if f.Status != "" && event.Task.Status != f.Status && (event.Previous == nil || event.Previous.Status != f.Status) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type User struct {
+	ID          int       `json:"id" db:"id"`
+	Username    string    `json:"username" db:"username"`
+	Email       string    `json:"email" db:"email"`
+	DisplayName string    `json:"display_name" db:"display_name"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// What other users may see of a user; leaves out the email address
+type PublicUser struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+}
+
+func (u User) Public() PublicUser {
+	return PublicUser{ID: u.ID, Username: u.Username, DisplayName: u.DisplayName}
+}
+
+// Returned once when a user is created; only a hash of the token is stored
+type UserWithToken struct {
+	User
+	APIToken string `json:"api_token"`
+}
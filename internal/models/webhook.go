@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+// Headers sent with every delivery. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook's secret.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// The secret is only shown when the webhook is created
+type Webhook struct {
+	ID         int             `json:"id" db:"id"`
+	URL        string          `json:"url" db:"url"`
+	Secret     string          `json:"secret,omitempty" db:"secret"`
+	EventTypes []TaskEventType `json:"event_types" db:"event_types"`
+	Active     bool            `json:"active" db:"active"`
+	CreatedBy  int             `json:"created_by" db:"created_by"` // ID of the user who registered it
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+func (w Webhook) Subscribes(eventType TaskEventType) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	ID             int                   `json:"id" db:"id"`
+	WebhookID      int                   `json:"webhook_id" db:"webhook_id"`
+	Event          TaskEventType         `json:"event" db:"event"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError      string                `json:"last_error,omitempty" db:"last_error"`
+	ResponseStatus *int                  `json:"response_status" db:"response_status"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at" db:"delivered_at"`
+}
+
+// A claimed delivery together with where and how to send it
+type WebhookDeliveryJob struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+}
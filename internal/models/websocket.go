@@ -0,0 +1,67 @@
+package models
+
+// Message types exchanged over the WebSocket API
+const (
+	// Client to server
+	WSMessageSubscribe   = "subscribe"
+	WSMessageUnsubscribe = "unsubscribe"
+	WSMessageMove        = "move"
+	WSMessageSetStatus   = "set_status"
+	WSMessagePing        = "ping"
+
+	// Server to client
+	WSMessageEvent  = "event"
+	WSMessageResult = "result"
+	WSMessageError  = "error"
+	WSMessagePong   = "pong"
+)
+
+// A message sent by a WebSocket client. ID is echoed back in the reply so clients can
+// match results to commands. Which other fields apply depends on Type:
+//   - subscribe / unsubscribe: TaskIDs and/or Statuses; subscribe without either follows
+//     every task, and LastEventID replays what was missed since that event
+//   - move: TaskID with Before, After and Status as for POST /tasks/{id}/move
+//   - set_status: TaskID and Status
+type WSClientMessage struct {
+	Type        string   `json:"type"`
+	ID          string   `json:"id,omitempty"`
+	TaskIDs     []int    `json:"task_ids,omitempty"`
+	Statuses    []string `json:"statuses,omitempty"`
+	LastEventID int64    `json:"last_event_id,omitempty"`
+	TaskID      int      `json:"task_id,omitempty"`
+	Before      int      `json:"before,omitempty"`
+	After       int      `json:"after,omitempty"`
+	Status      string   `json:"status,omitempty"`
+}
+
+type WSServerMessage struct {
+	Type    string     `json:"type"`
+	ID      string     `json:"id,omitempty"`
+	Event   *TaskEvent `json:"event,omitempty"`
+	Task    *Task      `json:"task,omitempty"`
+	Error   string     `json:"error,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+// What a WebSocket client follows: specific tasks and/or whole status columns, or
+// everything when All is set
+type WSSubscription struct {
+	All      bool
+	TaskIDs  map[int]bool
+	Statuses map[TaskStatus]bool
+}
+
+func NewWSSubscription() *WSSubscription {
+	return &WSSubscription{TaskIDs: make(map[int]bool), Statuses: make(map[TaskStatus]bool)}
+}
+
+func (s *WSSubscription) Matches(event TaskEvent) bool {
+	if s.All || s.TaskIDs[event.Task.ID] || s.Statuses[event.Task.Status] {
+		return true
+	}
+	return event.Previous != nil && s.Statuses[event.Previous.Status]
+}
+
+func (s *WSSubscription) IsEmpty() bool {
+	return !s.All && len(s.TaskIDs) == 0 && len(s.Statuses) == 0
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/cache"
+	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+// Read-through cache in front of another TaskRepository for single task lookups.
+// Writes made through it drop the affected entry; writes made elsewhere (other
+// instances, rank rebalancing) are picked up from task_changes notifications via
+// HandleTaskChange, or at the latest when the entry expires. Misses are filled from
+// the primary, as an entry read from a lagging replica would be served to clients
+// whose own writes it is missing. Cache failures are logged and fall back to the
+// underlying repository.
+//
+// Invalidating leaves a short-lived tombstone rather than removing the entry, and fills
+// only go into an empty slot, so a lookup that read the task before a write cannot cache
+// what it read after the write's invalidation. While the tombstone lasts, lookups go to
+// the primary.
+type CachedTaskRepository struct {
+	TaskRepository // List and rank queries go straight through
+	cache          cache.Cache
+	config         CachedTaskRepositoryConfig
+}
+
+type CachedTaskRepositoryConfig struct {
+	TTL         time.Duration
+	NegativeTTL time.Duration // How long a missing ID is remembered
+	// How long an invalidated entry is kept from being filled. Lookups that take longer
+	// than this can still cache what they read before the invalidation.
+	TombstoneTTL time.Duration
+}
+
+func DefaultCachedTaskRepositoryConfig() CachedTaskRepositoryConfig {
+	return CachedTaskRepositoryConfig{
+		TTL:          5 * time.Minute,
+		NegativeTTL:  30 * time.Second,
+		TombstoneTTL: 10 * time.Second,
+	}
+}
+
+// Cached value recording that no task has the ID
+var missingTaskEntry = []byte("null")
+
+// Cached value left by an invalidation, never valid JSON
+var taskTombstoneEntry = []byte("-")
+
+func NewCachedTaskRepository(repo TaskRepository, c cache.Cache, config CachedTaskRepositoryConfig) *CachedTaskRepository {
+	return &CachedTaskRepository{TaskRepository: repo, cache: c, config: config}
+}
+
+func (r *CachedTaskRepository) GetTaskByID(ctx context.Context, id int) (*models.Task, error) {
+	key := taskCacheKey(id)
+	if data, ok, err := r.cache.Get(key); err != nil {
+		log.Printf("Task cache read failed for %s: %v", key, err)
+	} else if ok && bytes.Equal(data, taskTombstoneEntry) {
+		return r.TaskRepository.GetTaskByID(database.WithPrimaryReads(ctx), id)
+	} else if ok {
+		var task *models.Task
+		if err := json.Unmarshal(data, &task); err == nil {
+			return task, nil
+		}
+		log.Printf("Discarding undecodable task cache entry %s", key)
+	}
+
+	task, err := r.TaskRepository.GetTaskByID(database.WithPrimaryReads(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ttl := missingTaskEntry, r.config.NegativeTTL
+	if task != nil {
+		if data, err = json.Marshal(task); err != nil {
+			return task, nil
+		}
+		ttl = r.config.TTL
+	}
+	// Not over a tombstone left by a write since the lookup started
+	if _, err := r.cache.SetIfAbsent(key, data, ttl); err != nil {
+		log.Printf("Task cache write failed for %s: %v", key, err)
+	}
+	return task, nil
+}
+
+// Also drops a cached miss for the new ID
+func (r *CachedTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	if err := r.TaskRepository.CreateTask(ctx, task); err != nil {
+		return err
+	}
+	r.Invalidate(task.ID)
+	return nil
+}
+
+func (r *CachedTaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	err := r.TaskRepository.UpdateTask(ctx, task)
+	r.Invalidate(task.ID) // Even on failure, as the write may have gone through
+	return err
+}
+
+func (r *CachedTaskRepository) DeleteTask(ctx context.Context, id int) error {
+	err := r.TaskRepository.DeleteTask(ctx, id)
+	r.Invalidate(id)
+	return err
+}
+
+func (r *CachedTaskRepository) Invalidate(id int) {
+	if err := r.cache.Set(taskCacheKey(id), taskTombstoneEntry, r.config.TombstoneTTL); err != nil {
+		log.Printf("Task cache invalidation failed for task %d: %v", id, err)
+	}
+}
+
+// Invalidates the task named in a task_changes notification
+func (r *CachedTaskRepository) HandleTaskChange(payload string) {
+	change, err := database.ParseTaskChange(payload)
+	if err != nil {
+		log.Printf("Ignoring task change notification: %v", err)
+		return
+	}
+	r.Invalidate(change.ID)
+}
+
+// Closes the cache if it holds connections
+func (r *CachedTaskRepository) Close() error {
+	if closer, ok := r.cache.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func taskCacheKey(id int) string {
+	return "task:" + strconv.Itoa(id)
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/cache"
+	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// In-memory TaskRepository counting lookups; other methods are not used
+type countingTaskRepository struct {
+	TaskRepository
+	tasks          map[int]models.Task
+	lookups        int
+	primaryLookups int
+	afterLookup    func() // Runs once a lookup has read the task
+}
+
+func (r *countingTaskRepository) GetTaskByID(ctx context.Context, id int) (*models.Task, error) {
+	r.lookups++
+	if database.ReadsFromPrimary(ctx) {
+		r.primaryLookups++
+	}
+	task, ok := r.tasks[id]
+	if r.afterLookup != nil {
+		r.afterLookup()
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &task, nil
+}
+
+func (r *countingTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	r.tasks[task.ID] = *task
+	return nil
+}
+
+func (r *countingTaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	r.tasks[task.ID] = *task
+	return nil
+}
+
+func (r *countingTaskRepository) DeleteTask(ctx context.Context, id int) error {
+	delete(r.tasks, id)
+	return nil
+}
+
+func newCachedTestRepository() (*CachedTaskRepository, *countingTaskRepository) {
+	inner := &countingTaskRepository{tasks: map[int]models.Task{
+		1: {ID: 1, Title: "Cached", Status: models.StatusPending},
+	}}
+	return NewCachedTaskRepository(inner, cache.NewLRUCache(100), DefaultCachedTaskRepositoryConfig()), inner
+}
+
+func TestCachedTaskRepository_ReadThrough(t *testing.T) {
+	repo, inner := newCachedTestRepository()
+
+	for range 3 {
+		task, err := repo.GetTaskByID(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "Cached", task.Title)
+	}
+	assert.Equal(t, 1, inner.lookups)
+}
+
+func TestCachedTaskRepository_FillsFromPrimary(t *testing.T) {
+	repo, inner := newCachedTestRepository()
+
+	// Neither a task nor a miss is cached from a replica that may not have the latest write
+	ctx := database.WithReadSession(context.Background(), &database.ReadSession{MinLSN: 100})
+	_, err := repo.GetTaskByID(ctx, 1)
+	assert.NoError(t, err)
+	_, err = repo.GetTaskByID(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.primaryLookups)
+}
+
+func TestCachedTaskRepository_NegativeCaching(t *testing.T) {
+	repo, inner := newCachedTestRepository()
+
+	for range 2 {
+		task, err := repo.GetTaskByID(context.Background(), 2)
+		assert.NoError(t, err)
+		assert.Nil(t, task)
+	}
+	assert.Equal(t, 1, inner.lookups)
+
+	// Creating the task drops the cached miss
+	assert.NoError(t, repo.CreateTask(context.Background(), &models.Task{ID: 2, Title: "New"}))
+	task, _ := repo.GetTaskByID(context.Background(), 2)
+	assert.Equal(t, "New", task.Title)
+}
+
+func TestCachedTaskRepository_Expiry(t *testing.T) {
+	inner := &countingTaskRepository{tasks: map[int]models.Task{1: {ID: 1}}}
+	repo := NewCachedTaskRepository(inner, cache.NewLRUCache(100), CachedTaskRepositoryConfig{TTL: 10 * time.Millisecond})
+
+	repo.GetTaskByID(context.Background(), 1)
+	time.Sleep(20 * time.Millisecond)
+	repo.GetTaskByID(context.Background(), 1)
+	assert.Equal(t, 2, inner.lookups)
+}
+
+func TestCachedTaskRepository_Invalidation(t *testing.T) {
+	repo, inner := newCachedTestRepository()
+	repo.GetTaskByID(context.Background(), 1)
+
+	assert.NoError(t, repo.UpdateTask(context.Background(), &models.Task{ID: 1, Title: "Renamed"}))
+	task, _ := repo.GetTaskByID(context.Background(), 1)
+	assert.Equal(t, "Renamed", task.Title)
+
+	// A change made by another instance arrives as a notification
+	inner.tasks[1] = models.Task{ID: 1, Title: "Elsewhere"}
+	repo.HandleTaskChange(`{"op":"UPDATE","id":1}`)
+	task, _ = repo.GetTaskByID(context.Background(), 1)
+	assert.Equal(t, "Elsewhere", task.Title)
+
+	assert.NoError(t, repo.DeleteTask(context.Background(), 1))
+	task, _ = repo.GetTaskByID(context.Background(), 1)
+	assert.Nil(t, task)
+	assert.Equal(t, 4, inner.lookups)
+}
+
+func TestCachedTaskRepository_StaleFillAfterInvalidation(t *testing.T) {
+	repo, inner := newCachedTestRepository()
+
+	// A write lands between a lookup reading the task and caching it
+	inner.afterLookup = func() {
+		inner.afterLookup = nil
+		assert.NoError(t, repo.UpdateTask(context.Background(), &models.Task{ID: 1, Title: "Renamed"}))
+	}
+	task, _ := repo.GetTaskByID(context.Background(), 1)
+	assert.Equal(t, "Cached", task.Title)
+
+	task, _ = repo.GetTaskByID(context.Background(), 1)
+	assert.Equal(t, "Renamed", task.Title)
+}
+
+func TestCachedTaskRepository_FillsAgainAfterTombstone(t *testing.T) {
+	inner := &countingTaskRepository{tasks: map[int]models.Task{1: {ID: 1}}}
+	config := DefaultCachedTaskRepositoryConfig()
+	config.TombstoneTTL = 10 * time.Millisecond
+	repo := NewCachedTaskRepository(inner, cache.NewLRUCache(100), config)
+
+	repo.Invalidate(1)
+	repo.GetTaskByID(context.Background(), 1)
+	time.Sleep(20 * time.Millisecond)
+	repo.GetTaskByID(context.Background(), 1)
+	repo.GetTaskByID(context.Background(), 1)
+	assert.Equal(t, 2, inner.lookups)
+}
@@ -0,0 +1,458 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+// Keeps tasks in memory, for demos and for tests that need a real repository. It
+// sorts, filters, pages and reports missing tasks exactly like PostgresTaskRepository;
+// strings sort byte-wise rather than by the database's locale. Safe for concurrent use.
+type MemoryTaskRepository struct {
+	mu     sync.RWMutex
+	tasks  map[int]models.Task
+	nextID int
+}
+
+// Everything needed to recreate a MemoryTaskRepository, as written to snapshot files
+type MemoryTaskSnapshot struct {
+	NextID int           `json:"next_id"`
+	Tasks  []models.Task `json:"tasks"`
+}
+
+// Constructor - creates an empty repository
+func NewMemoryTaskRepository() *MemoryTaskRepository {
+	return &MemoryTaskRepository{tasks: make(map[int]models.Task), nextID: 1}
+}
+
+// Inserts a new task, appending it to its status column unless a rank is set
+func (r *MemoryTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	customFields, err := copyCustomFields(task.CustomFields)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if task.Rank == "" {
+		if task.Rank, err = models.RankBetween(r.lastRank(task.Status, 0), ""); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	task.ID = r.nextID
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	r.nextID++
+
+	stored := *task
+	stored.CustomFields = customFields
+	r.tasks[stored.ID] = stored
+	return nil
+}
+
+// GetTaskByID retrieves a single task by ID, or nil if there is none
+func (r *MemoryTaskRepository) GetTaskByID(ctx context.Context, id int) (*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, nil // Task not found
+	}
+	return r.copyTask(task), nil
+}
+
+// Retrieves one page of the tasks matching the filters, with the number of matching tasks
+func (r *MemoryTaskRepository) GetAllTasks(ctx context.Context, limit, page int, status, sortBy, sortOrder string, customFields map[string]any) ([]models.Task, int, error) {
+	// The same validation as the SQL backends
+	if _, err := buildTaskOrder(sortBy, sortOrder); err != nil {
+		return nil, 0, err
+	}
+	filters := make(map[string]any, len(customFields))
+	for key, value := range customFields {
+		if !models.IsValidCustomFieldKey(key) {
+			return nil, 0, fmt.Errorf("invalid custom field key %q", key)
+		}
+		normalized, err := jsonValue(value)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode filter for custom field %q: %w", key, err)
+		}
+		filters[key] = normalized
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matching := []models.Task{}
+	for _, task := range r.tasks {
+		if status != "" && string(task.Status) != status {
+			continue
+		}
+		if matchesCustomFields(task, filters) {
+			matching = append(matching, task)
+		}
+	}
+
+	compare := compareTasks(sortBy)
+	slices.SortFunc(matching, func(a, b models.Task) int {
+		if key, ok := models.CustomFieldSortKey(sortBy); ok {
+			// Tasks without the field come last in either order, like NULLS LAST
+			_, hasA := a.CustomFields[key]
+			_, hasB := b.CustomFields[key]
+			if hasA != hasB {
+				if hasA {
+					return -1
+				}
+				return 1
+			}
+		}
+		if sortOrder == "desc" {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+
+	start := min(max(page-1, 0)*limit, len(matching))
+	end := min(start+limit, len(matching))
+
+	tasks := make([]models.Task, 0, end-start)
+	for _, task := range matching[start:end] {
+		tasks = append(tasks, *r.copyTask(task))
+	}
+	return tasks, len(matching), nil
+}
+
+// Updates an existing task, returning sql.ErrNoRows if there is none
+func (r *MemoryTaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	customFields, err := copyCustomFields(task.CustomFields)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.tasks[task.ID]
+	if !ok {
+		return sql.ErrNoRows // Task not found
+	}
+
+	task.UpdatedAt = time.Now()
+	stored := *task
+	stored.CreatedAt = existing.CreatedAt
+	stored.CustomFields = customFields
+	r.tasks[task.ID] = stored
+	return nil
+}
+
+// Removes a task by ID, returning sql.ErrNoRows if there is none
+func (r *MemoryTaskRepository) DeleteTask(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tasks[id]; !ok {
+		return sql.ErrNoRows // Task not found
+	}
+	delete(r.tasks, id)
+	return nil
+}
+
+// Retrieves one page of a status column in rank order, starting after the cursor position
+// (nil for the first page), together with the total number of tasks in the column
+func (r *MemoryTaskRepository) GetColumnTasks(ctx context.Context, status models.TaskStatus, after *models.BoardCursor, limit int) ([]models.Task, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	column := r.column(status)
+	tasks := []models.Task{}
+	for _, task := range column {
+		if after != nil && (task.Rank < after.Rank || (task.Rank == after.Rank && task.ID <= after.ID)) {
+			continue
+		}
+		if len(tasks) == limit {
+			break
+		}
+		tasks = append(tasks, *r.copyTask(task))
+	}
+	return tasks, len(column), nil
+}
+
+// Returns the highest rank in a status column, or "" for an empty column
+func (r *MemoryTaskRepository) GetLastRank(ctx context.Context, status models.TaskStatus, excludeID int) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.lastRank(status, excludeID), nil
+}
+
+// Returns the nearest rank above (higher) or below a given rank in a status column, or "" if there is none
+func (r *MemoryTaskRepository) GetAdjacentRank(ctx context.Context, status models.TaskStatus, rank string, excludeID int, higher bool) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	adjacent := ""
+	for _, task := range r.tasks {
+		if task.Status != status || task.ID == excludeID {
+			continue
+		}
+		if higher && task.Rank > rank && (adjacent == "" || task.Rank < adjacent) {
+			adjacent = task.Rank
+		}
+		if !higher && task.Rank < rank && task.Rank > adjacent {
+			adjacent = task.Rank
+		}
+	}
+	return adjacent, nil
+}
+
+// Respaces the ranks of a status column evenly, moving them into the next bucket. The
+// whole column is re-ranked under one lock, so batchSize is not needed. Returns the
+// number of tasks re-ranked.
+func (r *MemoryTaskRepository) RebalanceRanks(ctx context.Context, status models.TaskStatus, batchSize int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	column := r.column(status)
+	if len(column) == 0 {
+		return 0, nil
+	}
+
+	var buckets []int
+	for _, task := range column {
+		if bucket, _, err := models.ParseRank(task.Rank); err == nil && !slices.Contains(buckets, bucket) {
+			buckets = append(buckets, bucket)
+		}
+	}
+	from, to, _ := models.RankRebalanceBuckets(buckets)
+
+	migrated := []models.Task{}
+	for _, task := range column {
+		if bucket, _, err := models.ParseRank(task.Rank); err == nil && bucket == from {
+			migrated = append(migrated, task)
+		}
+	}
+	for i, task := range migrated {
+		task.Rank = models.FormatRank(to, models.EvenRankValue(i+1, len(migrated)))
+		r.tasks[task.ID] = task
+	}
+	return len(migrated), nil
+}
+
+// Returns a copy of every task and the next id to hand out
+func (r *MemoryTaskRepository) Snapshot() MemoryTaskSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := MemoryTaskSnapshot{NextID: r.nextID, Tasks: make([]models.Task, 0, len(r.tasks))}
+	for _, task := range r.tasks {
+		snapshot.Tasks = append(snapshot.Tasks, *r.copyTask(task))
+	}
+	slices.SortFunc(snapshot.Tasks, func(a, b models.Task) int { return cmp.Compare(a.ID, b.ID) })
+	return snapshot
+}
+
+// Replaces the repository's contents with a snapshot
+func (r *MemoryTaskRepository) Restore(snapshot MemoryTaskSnapshot) {
+	tasks := make(map[int]models.Task, len(snapshot.Tasks))
+	nextID := max(snapshot.NextID, 1)
+	for _, task := range snapshot.Tasks {
+		tasks[task.ID] = *r.copyTask(task)
+		nextID = max(nextID, task.ID+1)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks = tasks
+	r.nextID = nextID
+}
+
+// Writes a snapshot to a JSON file, replacing it atomically
+func (r *MemoryTaskRepository) SaveSnapshot(path string) error {
+	data, err := json.MarshalIndent(r.Snapshot(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Restores a snapshot written by SaveSnapshot. A missing file leaves the repository
+// empty and is not an error.
+func (r *MemoryTaskRepository) LoadSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot MemoryTaskSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	r.Restore(snapshot)
+	return nil
+}
+
+// Tasks of a status column in rank order; callers hold the lock
+func (r *MemoryTaskRepository) column(status models.TaskStatus) []models.Task {
+	column := []models.Task{}
+	for _, task := range r.tasks {
+		if task.Status == status {
+			column = append(column, task)
+		}
+	}
+	slices.SortFunc(column, func(a, b models.Task) int {
+		return cmp.Or(strings.Compare(a.Rank, b.Rank), cmp.Compare(a.ID, b.ID))
+	})
+	return column
+}
+
+// Callers hold the lock
+func (r *MemoryTaskRepository) lastRank(status models.TaskStatus, excludeID int) string {
+	last := ""
+	for _, task := range r.tasks {
+		if task.Status == status && task.ID != excludeID && task.Rank > last {
+			last = task.Rank
+		}
+	}
+	return last
+}
+
+// Stored custom fields are decoded JSON, so a copy never shares maps or slices
+func (r *MemoryTaskRepository) copyTask(task models.Task) *models.Task {
+	task.CustomFields, _ = copyCustomFields(task.CustomFields)
+	return &task
+}
+
+// Copies custom fields through JSON, which also gives values the types the SQL
+// backends return (float64 numbers and so on)
+func copyCustomFields(fields map[string]any) (map[string]any, error) {
+	encoded, err := encodeCustomFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCustomFields([]byte(encoded))
+}
+
+func jsonValue(value any) (any, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded any
+	err = json.Unmarshal(encoded, &decoded)
+	return decoded, err
+}
+
+func matchesCustomFields(task models.Task, filters map[string]any) bool {
+	for key, value := range filters {
+		stored, ok := task.CustomFields[key]
+		if !ok || !reflect.DeepEqual(stored, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Ascending comparison for a sort field; ties are broken by id, so pages never overlap
+func compareTasks(sortBy string) func(a, b models.Task) int {
+	var compare func(a, b models.Task) int
+	switch sortBy {
+	case "title":
+		compare = func(a, b models.Task) int { return strings.Compare(a.Title, b.Title) }
+	case "status":
+		compare = func(a, b models.Task) int { return strings.Compare(string(a.Status), string(b.Status)) }
+	case "created_at":
+		compare = func(a, b models.Task) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	case "updated_at":
+		compare = func(a, b models.Task) int { return a.UpdatedAt.Compare(b.UpdatedAt) }
+	case "rank":
+		compare = func(a, b models.Task) int { return strings.Compare(a.Rank, b.Rank) }
+	default:
+		compare = func(a, b models.Task) int { return 0 }
+		if key, ok := models.CustomFieldSortKey(sortBy); ok {
+			compare = func(a, b models.Task) int { return compareJSON(a.CustomFields[key], b.CustomFields[key]) }
+		}
+	}
+	return func(a, b models.Task) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.ID, b.ID))
+	}
+}
+
+// Orders decoded JSON values like Postgres orders jsonb: null < strings < numbers <
+// booleans < arrays < objects, with numbers compared numerically
+func compareJSON(a, b any) int {
+	if rankA, rankB := jsonTypeRank(a), jsonTypeRank(b); rankA != rankB {
+		return cmp.Compare(rankA, rankB)
+	}
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case bool:
+		if a == b.(bool) {
+			return 0
+		}
+		if a {
+			return 1
+		}
+		return -1
+	case nil:
+		return 0
+	}
+	encodedA, _ := json.Marshal(a)
+	encodedB, _ := json.Marshal(b)
+	return strings.Compare(string(encodedA), string(encodedB))
+}
+
+func jsonTypeRank(value any) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case string:
+		return 1
+	case float64:
+		return 2
+	case bool:
+		return 3
+	case []any:
+		return 4
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestMemoryTaskRepository_Snapshot(t *testing.T) {
+	// Setup
+	repo := NewMemoryTaskRepository()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	kept := &models.Task{Title: "Kept", Status: models.StatusPending, CustomFields: map[string]any{"points": 3}}
+	deleted := &models.Task{Title: "Deleted", Status: models.StatusPending}
+	for _, task := range []*models.Task{kept, deleted} {
+		if err := repo.CreateTask(context.Background(), task); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+	repo.DeleteTask(context.Background(), deleted.ID)
+
+	// Execute
+	if err := repo.SaveSnapshot(path); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+	restored := NewMemoryTaskRepository()
+	if err := restored.LoadSnapshot(path); err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+
+	// Assert
+	task, _ := restored.GetTaskByID(context.Background(), kept.ID)
+	if task == nil || task.Title != "Kept" || task.Rank != kept.Rank || task.CustomFields["points"] != float64(3) || !task.CreatedAt.Equal(kept.CreatedAt) {
+		t.Errorf("Expected the kept task to be restored, got %+v", task)
+	}
+
+	// Ids of deleted tasks are not handed out again
+	next := &models.Task{Title: "Next", Status: models.StatusPending}
+	restored.CreateTask(context.Background(), next)
+	if next.ID != deleted.ID+1 {
+		t.Errorf("Expected id %d, got %d", deleted.ID+1, next.ID)
+	}
+
+	// A missing snapshot means starting empty
+	if err := NewMemoryTaskRepository().LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("Expected no error for a missing snapshot, got %v", err)
+	}
+}
+
+func TestMemoryTaskRepository_ConcurrentWrites(t *testing.T) {
+	// Setup
+	repo := NewMemoryTaskRepository()
+
+	// Execute
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task := &models.Task{Title: "Task", Status: models.StatusPending}
+			repo.CreateTask(context.Background(), task)
+			task.Status = models.StatusCompleted
+			repo.UpdateTask(context.Background(), task)
+			repo.GetAllTasks(context.Background(), 10, 1, "completed", "rank", "asc", nil)
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	_, total, _ := repo.GetAllTasks(context.Background(), 10, 1, "completed", "id", "asc", nil)
+	if total != 20 {
+		t.Errorf("Expected 20 completed tasks, got %d", total)
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+type PostgresAttachmentRepository struct {
+	db queryer
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(attachment *models.TaskAttachment) error
+	GetAttachments(taskID int) ([]models.TaskAttachment, error)
+	GetAttachment(taskID, id int) (*models.TaskAttachment, error)
+}
+
+func NewPostgresAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &PostgresAttachmentRepository{db: db}
+}
+
+func (r *PostgresAttachmentRepository) CreateAttachment(attachment *models.TaskAttachment) error {
+	query := `
+		INSERT INTO task_attachments (task_id, filename, content_type, size, source, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	attachment.Size = len(attachment.Data)
+	attachment.CreatedAt = time.Now()
+
+	return r.db.QueryRow(query, attachment.TaskID, attachment.Filename, attachment.ContentType, attachment.Size,
+		attachment.Source, attachment.Data, attachment.CreatedAt).Scan(&attachment.ID)
+}
+
+// Lists a task's attachments, oldest first, without their data
+func (r *PostgresAttachmentRepository) GetAttachments(taskID int) ([]models.TaskAttachment, error) {
+	query := `
+		SELECT id, task_id, filename, content_type, size, source, created_at
+		FROM task_attachments
+		WHERE task_id = $1
+		ORDER BY id`
+
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []models.TaskAttachment{}
+	for rows.Next() {
+		var attachment models.TaskAttachment
+		err := rows.Scan(&attachment.ID, &attachment.TaskID, &attachment.Filename, &attachment.ContentType,
+			&attachment.Size, &attachment.Source, &attachment.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// Returns one of a task's attachments with its data, or nil if it has no such attachment
+func (r *PostgresAttachmentRepository) GetAttachment(taskID, id int) (*models.TaskAttachment, error) {
+	query := `
+		SELECT id, task_id, filename, content_type, size, source, data, created_at
+		FROM task_attachments
+		WHERE id = $1 AND task_id = $2`
+
+	var attachment models.TaskAttachment
+	err := r.db.QueryRow(query, id, taskID).Scan(&attachment.ID, &attachment.TaskID, &attachment.Filename,
+		&attachment.ContentType, &attachment.Size, &attachment.Source, &attachment.Data, &attachment.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil // Attachment not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+type PostgresAutomationRepository struct {
+	db *sql.DB
+}
+
+type AutomationRuleRepository interface {
+	CreateRule(rule *models.AutomationRule) error
+	GetRuleByID(id int) (*models.AutomationRule, error)
+	GetAllRules() ([]models.AutomationRule, error)
+	GetEnabledRules(trigger models.TaskEventType) ([]models.AutomationRule, error)
+	DeleteRule(id int) error
+}
+
+// Columns read by scanRule, in order
+const automationRuleColumns = "id, name, trigger_event, conditions, actions, enabled, created_at, updated_at"
+
+func NewPostgresAutomationRepository(db *sql.DB) AutomationRuleRepository {
+	return &PostgresAutomationRepository{db: db}
+}
+
+func (r *PostgresAutomationRepository) CreateRule(rule *models.AutomationRule) error {
+	query := `
+		INSERT INTO automation_rules (name, trigger_event, conditions, actions, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	if rule.Conditions == nil {
+		rule.Conditions = []models.RuleCondition{}
+	}
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return fmt.Errorf("failed to encode conditions: %w", err)
+	}
+	actions, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return fmt.Errorf("failed to encode actions: %w", err)
+	}
+
+	now := time.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	return r.db.QueryRow(query, rule.Name, rule.Trigger, string(conditions), string(actions), rule.Enabled,
+		rule.CreatedAt, rule.UpdatedAt).Scan(&rule.ID)
+}
+
+// Returns nil if the rule does not exist
+func (r *PostgresAutomationRepository) GetRuleByID(id int) (*models.AutomationRule, error) {
+	query := `SELECT ` + automationRuleColumns + ` FROM automation_rules WHERE id = $1`
+
+	rule, err := scanRule(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Rule not found
+	}
+	return rule, err
+}
+
+func (r *PostgresAutomationRepository) GetAllRules() ([]models.AutomationRule, error) {
+	return r.queryRules(`SELECT ` + automationRuleColumns + ` FROM automation_rules ORDER BY id`)
+}
+
+// Rules to evaluate for an event of the trigger type, in creation order
+func (r *PostgresAutomationRepository) GetEnabledRules(trigger models.TaskEventType) ([]models.AutomationRule, error) {
+	return r.queryRules(`
+		SELECT `+automationRuleColumns+`
+		FROM automation_rules
+		WHERE enabled AND trigger_event = $1
+		ORDER BY id`, trigger)
+}
+
+func (r *PostgresAutomationRepository) DeleteRule(id int) error {
+	result, err := r.db.Exec(`DELETE FROM automation_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows // Rule not found
+	}
+
+	return nil
+}
+
+func (r *PostgresAutomationRepository) queryRules(query string, args ...any) ([]models.AutomationRule, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query automation rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []models.AutomationRule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func scanRule(row rowScanner) (*models.AutomationRule, error) {
+	var rule models.AutomationRule
+	var conditions, actions []byte
+	err := row.Scan(&rule.ID, &rule.Name, &rule.Trigger, &conditions, &actions, &rule.Enabled,
+		&rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+		return nil, fmt.Errorf("failed to decode conditions of rule %d: %w", rule.ID, err)
+	}
+	if err := json.Unmarshal(actions, &rule.Actions); err != nil {
+		return nil, fmt.Errorf("failed to decode actions of rule %d: %w", rule.ID, err)
+	}
+	return &rule, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+type PostgresBoardRepository struct {
+	db *sql.DB
+}
+
+type BoardRepository interface {
+	GetColumnSettings() ([]models.BoardColumnSettings, error)
+	UpdateColumnSettings(settings *models.BoardColumnSettings) error
+}
+
+func NewPostgresBoardRepository(db *sql.DB) BoardRepository {
+	return &PostgresBoardRepository{db: db}
+}
+
+// Retrieves the settings of every configured column
+func (r *PostgresBoardRepository) GetColumnSettings() ([]models.BoardColumnSettings, error) {
+	rows, err := r.db.Query(`SELECT status, wip_limit FROM board_columns`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query board columns: %w", err)
+	}
+	defer rows.Close()
+
+	settings := []models.BoardColumnSettings{}
+	for rows.Next() {
+		var column models.BoardColumnSettings
+		var wipLimit sql.NullInt64
+		if err := rows.Scan(&column.Status, &wipLimit); err != nil {
+			return nil, fmt.Errorf("failed to scan board column: %w", err)
+		}
+		if wipLimit.Valid {
+			limit := int(wipLimit.Int64)
+			column.WIPLimit = &limit
+		}
+		settings = append(settings, column)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Creates or replaces the settings of a column
+func (r *PostgresBoardRepository) UpdateColumnSettings(settings *models.BoardColumnSettings) error {
+	query := `
+		INSERT INTO board_columns (status, wip_limit, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (status) DO UPDATE SET wip_limit = EXCLUDED.wip_limit, updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.Exec(query, settings.Status, settings.WIPLimit)
+	return err
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/lib/pq"
+)
+
+type PostgresCustomFieldRepository struct {
+	db *sql.DB
+}
+
+type CustomFieldRepository interface {
+	CreateField(field *models.CustomFieldDefinition) error
+	GetFieldByID(id int) (*models.CustomFieldDefinition, error)
+	GetFieldByKey(key string) (*models.CustomFieldDefinition, error)
+	GetAllFields(ctx context.Context) ([]models.CustomFieldDefinition, error)
+	DeleteField(id int) error
+}
+
+func NewPostgresCustomFieldRepository(db *sql.DB) CustomFieldRepository {
+	return &PostgresCustomFieldRepository{db: db}
+}
+
+// Inserts a definition and creates the expression index used for filtering and sorting
+// on it, in one transaction so a field never exists without its index. A key that is
+// already taken, including by a concurrent create, is a BusinessError.
+func (r *PostgresCustomFieldRepository) CreateField(field *models.CustomFieldDefinition) error {
+	if !models.IsValidNewCustomFieldKey(field.Key) {
+		return fmt.Errorf("invalid custom field key %q", field.Key)
+	}
+
+	query := `
+		INSERT INTO custom_field_definitions (key, name, type, options, required, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	now := time.Now()
+	field.CreatedAt = now
+	field.UpdatedAt = now
+
+	options := field.Options
+	if options == nil {
+		options = []string{}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(query, field.Key, field.Name, field.Type, pq.Array(options), field.Required,
+		field.CreatedAt, field.UpdatedAt).Scan(&field.ID)
+	if isUniqueViolation(err) {
+		return models.BusinessError{Message: fmt.Sprintf("custom field with key '%s' already exists", field.Key)}
+	}
+	if err != nil {
+		return err
+	}
+
+	// The key is validated above, so it is safe to embed in DDL
+	indexQuery := fmt.Sprintf(
+		`CREATE INDEX IF NOT EXISTS %s ON tasks ((custom_fields -> '%s'))`,
+		customFieldIndexName(field.Key), field.Key)
+	if _, err := tx.Exec(indexQuery); err != nil {
+		return fmt.Errorf("failed to create index for custom field %q: %w", field.Key, err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresCustomFieldRepository) GetFieldByID(id int) (*models.CustomFieldDefinition, error) {
+	query := `
+		SELECT id, key, name, type, options, required, created_at, updated_at
+		FROM custom_field_definitions
+		WHERE id = $1`
+
+	return r.scanField(r.db.QueryRow(query, id))
+}
+
+func (r *PostgresCustomFieldRepository) GetFieldByKey(key string) (*models.CustomFieldDefinition, error) {
+	query := `
+		SELECT id, key, name, type, options, required, created_at, updated_at
+		FROM custom_field_definitions
+		WHERE key = $1`
+
+	return r.scanField(r.db.QueryRow(query, key))
+}
+
+func (r *PostgresCustomFieldRepository) GetAllFields(ctx context.Context) ([]models.CustomFieldDefinition, error) {
+	query := `
+		SELECT id, key, name, type, options, required, created_at, updated_at
+		FROM custom_field_definitions
+		ORDER BY key ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query custom fields: %w", contextError(ctx, err))
+	}
+	defer rows.Close()
+
+	fields := []models.CustomFieldDefinition{}
+	for rows.Next() {
+		var field models.CustomFieldDefinition
+		err := rows.Scan(
+			&field.ID,
+			&field.Key,
+			&field.Name,
+			&field.Type,
+			pq.Array(&field.Options),
+			&field.Required,
+			&field.CreatedAt,
+			&field.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		fields = append(fields, field)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return fields, nil
+}
+
+// Removes a definition, its values on every task and its expression index
+func (r *PostgresCustomFieldRepository) DeleteField(id int) error {
+	field, err := r.GetFieldByID(id)
+	if err != nil {
+		return err
+	}
+	if field == nil {
+		return sql.ErrNoRows
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM custom_field_definitions WHERE id = $1`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE tasks SET custom_fields = custom_fields - $1::text WHERE custom_fields ? $1::text`, field.Key); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`DROP INDEX IF EXISTS %s`, customFieldIndexName(field.Key))); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresCustomFieldRepository) scanField(row *sql.Row) (*models.CustomFieldDefinition, error) {
+	field := &models.CustomFieldDefinition{}
+	err := row.Scan(
+		&field.ID,
+		&field.Key,
+		&field.Name,
+		&field.Type,
+		pq.Array(&field.Options),
+		&field.Required,
+		&field.CreatedAt,
+		&field.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Field not found
+		}
+		return nil, err
+	}
+
+	return field, nil
+}
+
+func customFieldIndexName(key string) string {
+	return "idx_tasks_cf_" + key
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestPostgresCustomFieldRepository_CreateAndGet(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	repo := NewPostgresCustomFieldRepository(db)
+
+	field := &models.CustomFieldDefinition{
+		Key:     "severity",
+		Name:    "Severity",
+		Type:    models.FieldTypeEnum,
+		Options: []string{"low", "high"},
+	}
+
+	// Execute
+	err := repo.CreateField(field)
+	if err != nil {
+		t.Fatalf("CreateField failed: %v", err)
+	}
+	defer repo.DeleteField(field.ID)
+
+	// Assert
+	retrieved, err := repo.GetFieldByKey("severity")
+	if err != nil {
+		t.Fatalf("GetFieldByKey failed: %v", err)
+	}
+
+	if retrieved == nil || retrieved.ID != field.ID {
+		t.Fatalf("Expected field %d, got %v", field.ID, retrieved)
+	}
+
+	if len(retrieved.Options) != 2 {
+		t.Errorf("Expected 2 options, got %v", retrieved.Options)
+	}
+}
+
+func TestPostgresCustomFieldRepository_CreateField_DuplicateKey(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	repo := NewPostgresCustomFieldRepository(db)
+
+	field := &models.CustomFieldDefinition{Key: "severity", Name: "Severity", Type: models.FieldTypeText}
+	if err := repo.CreateField(field); err != nil {
+		t.Fatalf("CreateField failed: %v", err)
+	}
+	defer repo.DeleteField(field.ID)
+
+	// Execute
+	err := repo.CreateField(&models.CustomFieldDefinition{Key: "severity", Name: "Again", Type: models.FieldTypeText})
+
+	// Assert
+	var businessErr models.BusinessError
+	if !errors.As(err, &businessErr) {
+		t.Fatalf("Expected a BusinessError, got %v", err)
+	}
+
+	fields, err := repo.GetAllFields(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllFields failed: %v", err)
+	}
+	if len(fields) != 1 {
+		t.Errorf("Expected 1 field, got %d", len(fields))
+	}
+}
+
+func TestPostgresTaskRepository_GetAllTasks_CustomFields(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	fieldRepo := NewPostgresCustomFieldRepository(db)
+	taskRepo := NewPostgresTaskRepository(db)
+
+	field := &models.CustomFieldDefinition{Key: "estimate", Name: "Estimate", Type: models.FieldTypeNumber}
+	if err := fieldRepo.CreateField(field); err != nil {
+		t.Fatalf("CreateField failed: %v", err)
+	}
+	defer fieldRepo.DeleteField(field.ID)
+
+	tasks := []*models.Task{
+		{Title: "Ten", Status: models.StatusPending, CustomFields: map[string]any{"estimate": float64(10)}},
+		{Title: "Two", Status: models.StatusPending, CustomFields: map[string]any{"estimate": float64(2)}},
+		{Title: "None", Status: models.StatusPending},
+	}
+	for _, task := range tasks {
+		if err := taskRepo.CreateTask(context.Background(), task); err != nil {
+			t.Fatalf("Failed to create test task: %v", err)
+		}
+	}
+
+	// Numeric ordering, tasks without the field last
+	sorted, total, err := taskRepo.GetAllTasks(context.Background(), 10, 1, "", "cf.estimate", "asc", nil)
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+
+	if total != 3 || len(sorted) != 3 {
+		t.Fatalf("Expected 3 tasks, got %d (total %d)", len(sorted), total)
+	}
+
+	if sorted[0].Title != "Two" || sorted[1].Title != "Ten" || sorted[2].Title != "None" {
+		t.Errorf("Unexpected order: %s, %s, %s", sorted[0].Title, sorted[1].Title, sorted[2].Title)
+	}
+
+	// Typed equality filter
+	filtered, total, err := taskRepo.GetAllTasks(context.Background(), 10, 1, "", "created_at", "desc", map[string]any{"estimate": float64(10)})
+	if err != nil {
+		t.Fatalf("GetAllTasks with filter failed: %v", err)
+	}
+
+	if total != 1 || len(filtered) != 1 || filtered[0].Title != "Ten" {
+		t.Errorf("Expected only 'Ten', got %v", filtered)
+	}
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/lib/pq"
+)
+
+type PostgresDigestRepository struct {
+	db *sql.DB
+}
+
+type DigestRepository interface {
+	GetPreferences(userID int) (*models.DigestPreferences, error)
+	SavePreferences(preferences *models.DigestPreferences) error
+	ClaimDueDigests(now time.Time, limit int, leaseUntil time.Time) ([]models.DigestJob, error)
+	MarkDigestSent(userID int, sentAt, nextDigestAt time.Time) error
+	GetChangedTasks(preferences models.DigestPreferences, since, until time.Time, limit int) ([]models.Task, error)
+	GetDueTasks(preferences models.DigestPreferences, dueField string, dueBy time.Time, limit int) ([]models.Task, error)
+}
+
+func NewPostgresDigestRepository(db *sql.DB) DigestRepository {
+	return &PostgresDigestRepository{db: db}
+}
+
+// Columns read by scanDigestPreferences, in order
+const digestPreferenceColumns = "p.user_id, p.frequency, p.statuses, p.watched_only, p.last_sent_at, p.next_digest_at, p.updated_at"
+
+// Returns the user's saved preferences, or nil if they have never saved any
+func (r *PostgresDigestRepository) GetPreferences(userID int) (*models.DigestPreferences, error) {
+	query := `SELECT ` + digestPreferenceColumns + ` FROM digest_preferences p WHERE p.user_id = $1`
+
+	preferences, err := scanDigestPreferences(r.db.QueryRow(query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil // Preferences not saved
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest preferences: %w", err)
+	}
+	return preferences, nil
+}
+
+// Inserts or replaces the user's preferences; last_sent_at is kept as it is
+func (r *PostgresDigestRepository) SavePreferences(preferences *models.DigestPreferences) error {
+	query := `
+		INSERT INTO digest_preferences (user_id, frequency, statuses, watched_only, next_digest_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET frequency = EXCLUDED.frequency,
+			statuses = EXCLUDED.statuses,
+			watched_only = EXCLUDED.watched_only,
+			next_digest_at = EXCLUDED.next_digest_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING last_sent_at`
+
+	preferences.UpdatedAt = time.Now()
+
+	var lastSentAt sql.NullTime
+	err := r.db.QueryRow(query, preferences.UserID, preferences.Frequency, pq.Array(statusStrings(preferences.Statuses)),
+		preferences.WatchedOnly, preferences.NextDigestAt, preferences.UpdatedAt).Scan(&lastSentAt)
+	if err != nil {
+		return fmt.Errorf("failed to save digest preferences: %w", err)
+	}
+	preferences.LastSentAt = nullTimePtr(lastSentAt)
+	return nil
+}
+
+// Claims digests that are due by pushing their next send time out to the lease, so
+// other instances skip them while they are being sent. A digest that is never marked
+// sent is retried when the lease runs out.
+func (r *PostgresDigestRepository) ClaimDueDigests(now time.Time, limit int, leaseUntil time.Time) ([]models.DigestJob, error) {
+	query := `
+		UPDATE digest_preferences p
+		SET next_digest_at = $3
+		FROM users u
+		WHERE u.id = p.user_id
+			AND p.user_id IN (
+				SELECT user_id FROM digest_preferences
+				WHERE frequency <> 'off' AND next_digest_at <= $1
+				ORDER BY next_digest_at, user_id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING ` + digestPreferenceColumns + `, u.id, u.username, u.email, u.display_name, u.created_at`
+
+	rows, err := r.db.Query(query, now, limit, leaseUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim digests: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.DigestJob{}
+	for rows.Next() {
+		var job models.DigestJob
+		recipient := &job.Recipient
+		preferences, err := scanDigestPreferences(rows, &recipient.ID, &recipient.Username, &recipient.Email,
+			&recipient.DisplayName, &recipient.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan digest: %w", err)
+		}
+		job.Preferences = *preferences
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (r *PostgresDigestRepository) MarkDigestSent(userID int, sentAt, nextDigestAt time.Time) error {
+	query := `UPDATE digest_preferences SET last_sent_at = $2, next_digest_at = $3 WHERE user_id = $1`
+
+	if _, err := r.db.Exec(query, userID, sentAt, nextDigestAt); err != nil {
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+	return nil
+}
+
+// Tasks updated within [since, until) that match the preferences, most recent first
+func (r *PostgresDigestRepository) GetChangedTasks(preferences models.DigestPreferences, since, until time.Time, limit int) ([]models.Task, error) {
+	conditions, args := digestTaskFilter(preferences, 4)
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE updated_at >= $1 AND updated_at < $2` + conditions + `
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $3`
+
+	return r.queryTasks(query, append([]any{since, until, limit}, args...)...)
+}
+
+// Tasks that are not completed or closed and whose date custom field is on or before
+// dueBy, soonest first. Dates are stored as YYYY-MM-DD, so text order is date order.
+func (r *PostgresDigestRepository) GetDueTasks(preferences models.DigestPreferences, dueField string, dueBy time.Time, limit int) ([]models.Task, error) {
+	if !models.IsValidCustomFieldKey(dueField) {
+		return nil, fmt.Errorf("invalid custom field key %q", dueField)
+	}
+
+	conditions, args := digestTaskFilter(preferences, 3)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM tasks
+		WHERE custom_fields ->> '%[2]s' <= $1
+			AND status NOT IN ('completed', 'closed')%[3]s
+		ORDER BY custom_fields ->> '%[2]s', id
+		LIMIT $2`, taskColumns, dueField, conditions)
+
+	return r.queryTasks(query, append([]any{dueBy.Format("2006-01-02"), limit}, args...)...)
+}
+
+func (r *PostgresDigestRepository) queryTasks(query string, args ...any) ([]models.Task, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digest tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// Extra AND conditions for the user's status and watched-only preferences
+func digestTaskFilter(preferences models.DigestPreferences, argIndex int) (string, []any) {
+	conditions := []string{}
+	args := []any{}
+
+	if len(preferences.Statuses) > 0 {
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", argIndex))
+		args = append(args, pq.Array(statusStrings(preferences.Statuses)))
+		argIndex++
+	}
+	if preferences.WatchedOnly {
+		conditions = append(conditions, fmt.Sprintf("id IN (SELECT task_id FROM task_watchers WHERE user_id = $%d)", argIndex))
+		args = append(args, preferences.UserID)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " AND " + strings.Join(conditions, " AND "), args
+}
+
+// Scans the digestPreferenceColumns of a row, followed by any extra columns
+func scanDigestPreferences(row rowScanner, extra ...any) (*models.DigestPreferences, error) {
+	preferences := &models.DigestPreferences{}
+	var statuses []string
+	var lastSentAt, nextDigestAt sql.NullTime
+	dest := append([]any{
+		&preferences.UserID,
+		&preferences.Frequency,
+		pq.Array(&statuses),
+		&preferences.WatchedOnly,
+		&lastSentAt,
+		&nextDigestAt,
+		&preferences.UpdatedAt,
+	}, extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	preferences.Statuses = make([]models.TaskStatus, len(statuses))
+	for i, status := range statuses {
+		preferences.Statuses[i] = models.TaskStatus(status)
+	}
+	preferences.LastSentAt = nullTimePtr(lastSentAt)
+	preferences.NextDigestAt = nullTimePtr(nextDigestAt)
+	return preferences, nil
+}
+
+func statusStrings(statuses []models.TaskStatus) []string {
+	strs := make([]string, len(statuses))
+	for i, status := range statuses {
+		strs[i] = string(status)
+	}
+	return strs
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
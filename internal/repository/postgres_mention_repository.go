@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+type PostgresMentionRepository struct {
+	db queryer
+}
+
+type MentionRepository interface {
+	CreateMentions(mentions []models.Mention) error
+	GetMentions(userID int, limit, page int) ([]models.Mention, int, error)
+}
+
+func NewPostgresMentionRepository(db *sql.DB) MentionRepository {
+	return &PostgresMentionRepository{db: db}
+}
+
+// Stores the mentions of a single change together
+func (r *PostgresMentionRepository) CreateMentions(mentions []models.Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO mentions (user_id, task_id, source, excerpt, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	now := time.Now()
+	return inTransaction(context.Background(), r.db, func(q queryer) error {
+		for i := range mentions {
+			mentions[i].CreatedAt = now
+			err := q.QueryRow(query, mentions[i].UserID, mentions[i].TaskID, mentions[i].Source,
+				mentions[i].Excerpt, mentions[i].CreatedAt).Scan(&mentions[i].ID)
+			if err != nil {
+				return fmt.Errorf("failed to create mention: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// Retrieves a page of the user's mentions, newest first, with the title of the task
+func (r *PostgresMentionRepository) GetMentions(userID int, limit, page int) ([]models.Mention, int, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT m.id, m.user_id, m.task_id, t.title, m.source, m.excerpt, m.created_at,
+			COUNT(*) OVER() as total_count
+		FROM mentions m
+		JOIN tasks t ON t.id = m.task_id
+		WHERE m.user_id = $1
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentions := []models.Mention{}
+	var totalCount int
+	for rows.Next() {
+		var mention models.Mention
+		err := rows.Scan(
+			&mention.ID,
+			&mention.UserID,
+			&mention.TaskID,
+			&mention.TaskTitle,
+			&mention.Source,
+			&mention.Excerpt,
+			&mention.CreatedAt,
+			&totalCount,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		mentions = append(mentions, mention)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// Handle case where no rows returned (high page number)
+	if len(mentions) == 0 {
+		if err := r.db.QueryRow(`SELECT COUNT(*) FROM mentions WHERE user_id = $1`, userID).Scan(&totalCount); err != nil {
+			return nil, 0, fmt.Errorf("failed to get count: %w", err)
+		}
+	}
+
+	return mentions, totalCount, nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+type PostgresNotificationRepository struct {
+	db *sql.DB
+}
+
+type NotificationRepository interface {
+	CreateForWatchers(eventID int64, notification *models.Notification) (int, error)
+	GetNotifications(userID int, unreadOnly bool, limit, page int) ([]models.Notification, int, error)
+	CountUnread(userID int) (int, error)
+	MarkRead(userID, id int) (bool, error)
+	MarkAllRead(userID int) (int, error)
+}
+
+func NewPostgresNotificationRepository(db *sql.DB) NotificationRepository {
+	return &PostgresNotificationRepository{db: db}
+}
+
+// Fans a notification out to every watcher of its task in a single statement.
+// eventID is the outbox message it is for, if any: watchers already notified of that
+// message are skipped, so relaying it again is harmless. Returns the number of
+// notifications created.
+func (r *PostgresNotificationRepository) CreateForWatchers(eventID int64, notification *models.Notification) (int, error) {
+	query := `
+		INSERT INTO notifications (user_id, task_id, event, message, changes, created_at, outbox_id)
+		SELECT user_id, $1, $2, $3, $4, $5, $6
+		FROM task_watchers
+		WHERE task_id = $1
+		ON CONFLICT (outbox_id, user_id) DO NOTHING`
+
+	changes, err := json.Marshal(notification.Changes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode changes: %w", err)
+	}
+	if notification.Changes == nil {
+		changes = []byte("[]")
+	}
+	notification.CreatedAt = time.Now()
+
+	result, err := r.db.Exec(query, notification.TaskID, notification.Event, notification.Message,
+		string(changes), notification.CreatedAt, outboxID(eventID))
+	if err != nil {
+		return 0, err
+	}
+
+	created, err := result.RowsAffected()
+	return int(created), err
+}
+
+// Retrieves a page of a user's notifications, newest first
+func (r *PostgresNotificationRepository) GetNotifications(userID int, unreadOnly bool, limit, page int) ([]models.Notification, int, error) {
+	offset := (page - 1) * limit
+
+	whereClause := "WHERE user_id = $1"
+	if unreadOnly {
+		whereClause += " AND read_at IS NULL"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, task_id, event, message, changes, read_at, created_at,
+			COUNT(*) OVER() as total_count
+		FROM notifications
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, whereClause)
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	var totalCount int
+	for rows.Next() {
+		var notification models.Notification
+		var changes []byte
+		var readAt sql.NullTime
+		err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.TaskID,
+			&notification.Event,
+			&notification.Message,
+			&changes,
+			&readAt,
+			&notification.CreatedAt,
+			&totalCount,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if err := json.Unmarshal(changes, &notification.Changes); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode changes: %w", err)
+		}
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// Handle case where no rows returned (high page number)
+	if len(notifications) == 0 {
+		countQuery := "SELECT COUNT(*) FROM notifications " + whereClause
+		if err := r.db.QueryRow(countQuery, userID).Scan(&totalCount); err != nil {
+			return nil, 0, fmt.Errorf("failed to get count: %w", err)
+		}
+	}
+
+	return notifications, totalCount, nil
+}
+
+func (r *PostgresNotificationRepository) CountUnread(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// Marks one of the user's notifications as read; already read ones keep their original time.
+// Reports false if the user has no such notification.
+func (r *PostgresNotificationRepository) MarkRead(userID, id int) (bool, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Marks every unread notification of the user as read, returning how many changed
+func (r *PostgresNotificationRepository) MarkAllRead(userID int) (int, error) {
+	result, err := r.db.Exec(`UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	updated, err := result.RowsAffected()
+	return int(updated), err
+}
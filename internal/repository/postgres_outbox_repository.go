@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/lib/pq"
+)
+
+// Channel the outbox trigger notifies on with the ID of every message, once it commits
+const OutboxChannel = "task_events"
+
+type PostgresOutboxRepository struct {
+	db *sql.DB
+}
+
+type OutboxRepository interface {
+	PublishBatch(limit int, retryAfter func(attempts int) time.Duration, publish func(message models.OutboxMessage) error) (int, error)
+	DeletePublished(before time.Time) (int, error)
+	GetMessagesAfter(afterID int64, limit int) ([]models.OutboxMessage, error)
+	GetMessagesByIDs(ids []int64) ([]models.OutboxMessage, error)
+	GetLatestMessageID() (int64, error)
+	GetLookbackStart(id int64, window time.Duration) (int64, error)
+}
+
+func NewPostgresOutboxRepository(db *sql.DB) OutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+// Claims up to limit unpublished messages in order and hands each to publish. Successful
+// messages are marked published; failed ones are retried after retryAfter(attempts).
+// The rows stay locked until the batch is done, and SKIP LOCKED lets relays on other
+// replicas take the next messages instead of waiting. A relay dying mid-batch rolls the
+// batch back, so messages are published at least once. Returns the number published.
+func (r *PostgresOutboxRepository) PublishBatch(limit int, retryAfter func(attempts int) time.Duration, publish func(message models.OutboxMessage) error) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, aggregate_id, event_type, payload, attempts, created_at
+		FROM outbox
+		WHERE published_at IS NULL AND available_at <= NOW()
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.Query(query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		var message models.OutboxMessage
+		var payload []byte
+		err := rows.Scan(&message.ID, &message.AggregateID, &message.EventType, &payload, &message.Attempts, &message.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		message.Payload = payload
+		messages = append(messages, message)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	published := 0
+	for _, message := range messages {
+		if publishErr := publish(message); publishErr != nil {
+			attempts := message.Attempts + 1
+			_, err = tx.Exec(`UPDATE outbox SET attempts = $1, last_error = $2, available_at = $3 WHERE id = $4`,
+				attempts, publishErr.Error(), time.Now().Add(retryAfter(attempts)), message.ID)
+		} else {
+			published++
+			_, err = tx.Exec(`UPDATE outbox SET attempts = attempts + 1, last_error = '', published_at = NOW() WHERE id = $1`, message.ID)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to update outbox message %d: %w", message.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return published, nil
+}
+
+// Removes messages published before the given time, returning how many were removed
+func (r *PostgresOutboxRepository) DeletePublished(before time.Time) (int, error) {
+	result, err := r.db.Exec(`DELETE FROM outbox WHERE published_at IS NOT NULL AND published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+// Returns up to limit messages with an ID above afterID, published or not, in ID order
+func (r *PostgresOutboxRepository) GetMessagesAfter(afterID int64, limit int) ([]models.OutboxMessage, error) {
+	query := `
+		SELECT id, aggregate_id, event_type, payload, attempts, created_at, published_at
+		FROM outbox
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2`
+
+	return r.queryMessages(query, afterID, limit)
+}
+
+// Returns the messages with the given IDs that still exist, in ID order
+func (r *PostgresOutboxRepository) GetMessagesByIDs(ids []int64) ([]models.OutboxMessage, error) {
+	query := `
+		SELECT id, aggregate_id, event_type, payload, attempts, created_at, published_at
+		FROM outbox
+		WHERE id = ANY($1)
+		ORDER BY id`
+
+	return r.queryMessages(query, pq.Array(ids))
+}
+
+// Returns the highest message ID, or 0 for an empty outbox
+func (r *PostgresOutboxRepository) GetLatestMessageID() (int64, error) {
+	var id int64
+	err := r.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM outbox`).Scan(&id)
+	return id, err
+}
+
+// Returns the lowest ID of the messages created no more than window before message id,
+// or id itself if that message is gone. IDs are assigned on insert rather than commit, so
+// messages just below id may have committed after it.
+func (r *PostgresOutboxRepository) GetLookbackStart(id int64, window time.Duration) (int64, error) {
+	query := `
+		SELECT COALESCE(MIN(id), $1)
+		FROM outbox
+		WHERE created_at >= (SELECT created_at FROM outbox WHERE id = $1) - $2 * INTERVAL '1 millisecond'`
+
+	var start int64
+	if err := r.db.QueryRow(query, id, window.Milliseconds()).Scan(&start); err != nil {
+		return 0, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	return min(start, id), nil
+}
+
+func (r *PostgresOutboxRepository) queryMessages(query string, args ...any) ([]models.OutboxMessage, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		var message models.OutboxMessage
+		var payload []byte
+		var publishedAt sql.NullTime
+		err := rows.Scan(&message.ID, &message.AggregateID, &message.EventType, &payload,
+			&message.Attempts, &message.CreatedAt, &publishedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		message.Payload = payload
+		if publishedAt.Valid {
+			message.PublishedAt = &publishedAt.Time
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// Stores an event in the outbox as part of the caller's transaction
+func insertOutboxMessage(ctx context.Context, tx queryer, event models.TaskEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO outbox (aggregate_id, event_type, payload, created_at) VALUES ($1, $2, $3, $4)`,
+		event.Task.ID, event.Type, string(payload), event.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("failed to write outbox message: %w", err)
+	}
+	return nil
+}
+
+// Stores an outbox message ID on the rows created for it; events that did not come
+// from the outbox have ID 0 and are stored with NULL, which never conflicts
+func outboxID(eventID int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: eventID, Valid: eventID != 0}
+}
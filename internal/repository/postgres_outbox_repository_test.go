@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestPostgresOutboxSinks_RelayingAgainCreatesNothing(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	taskRepo := NewPostgresTaskRepository(db)
+	userRepo := NewPostgresUserRepository(db)
+	notificationRepo := NewPostgresNotificationRepository(db)
+	webhookRepo := NewPostgresWebhookRepository(db)
+
+	task := &models.Task{Title: "Watched", Status: models.StatusPending}
+	if err := taskRepo.CreateTask(context.Background(), task); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	user := &models.User{Username: "alice", Email: "alice@example.com"}
+	if err := userRepo.CreateUser(user, "token-hash"); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if err := NewPostgresWatcherRepository(db).AddWatcher(task.ID, user.ID); err != nil {
+		t.Fatalf("AddWatcher failed: %v", err)
+	}
+	webhook := &models.Webhook{URL: "https://example.com/hook", Secret: "a-very-secret-value", Active: true, CreatedBy: user.ID,
+		EventTypes: []models.TaskEventType{models.EventTaskUpdated}}
+	if err := webhookRepo.CreateWebhook(webhook); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+
+	// Execute: the same outbox message twice, as a relay retrying it would
+	var notified, queued []int
+	for range 2 {
+		created, err := notificationRepo.CreateForWatchers(42, &models.Notification{TaskID: task.ID, Event: models.EventTaskUpdated, Message: "updated"})
+		if err != nil {
+			t.Fatalf("CreateForWatchers failed: %v", err)
+		}
+		notified = append(notified, created)
+		count, err := webhookRepo.EnqueueDeliveries(42, models.EventTaskUpdated, []byte(`{"id":42}`))
+		if err != nil {
+			t.Fatalf("EnqueueDeliveries failed: %v", err)
+		}
+		queued = append(queued, count)
+	}
+
+	// Assert
+	if notified[0] != 1 || notified[1] != 0 {
+		t.Errorf("Expected 1 notification and then none, got %v", notified)
+	}
+	if queued[0] != 1 || queued[1] != 0 {
+		t.Errorf("Expected 1 delivery and then none, got %v", queued)
+	}
+
+	// Events from outside the outbox are never deduplicated
+	if created, _ := notificationRepo.CreateForWatchers(0, &models.Notification{TaskID: task.ID, Event: models.EventTaskUpdated}); created != 1 {
+		t.Errorf("Expected a notification without an outbox ID, got %d", created)
+	}
+	if created, _ := notificationRepo.CreateForWatchers(0, &models.Notification{TaskID: task.ID, Event: models.EventTaskUpdated}); created != 1 {
+		t.Errorf("Expected another notification without an outbox ID, got %d", created)
+	}
+}
+
+func TestPostgresOutboxRepository_GetLookbackStart(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	repo := NewPostgresOutboxRepository(db)
+	now := time.Now()
+	var ids []int64
+	for _, age := range []time.Duration{time.Hour, 30 * time.Second, 10 * time.Second, 0} {
+		var id int64
+		err := db.QueryRow(`INSERT INTO outbox (aggregate_id, event_type, payload, created_at) VALUES (1, 'task.created', '{}', $1) RETURNING id`,
+			now.Add(-age)).Scan(&id)
+		if err != nil {
+			t.Fatalf("Failed to insert outbox message: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Execute & Assert: messages created within the window before the last one are included
+	start, err := repo.GetLookbackStart(ids[3], time.Minute)
+	if err != nil {
+		t.Fatalf("GetLookbackStart failed: %v", err)
+	}
+	if start != ids[1] {
+		t.Errorf("Expected the lookback to start at message %d, got %d", ids[1], start)
+	}
+
+	// A message that is gone has nothing to look back from
+	if start, _ := repo.GetLookbackStart(ids[3]+100, time.Minute); start != ids[3]+100 {
+		t.Errorf("Expected the lookback of a missing message to start at it, got %d", start)
+	}
+}
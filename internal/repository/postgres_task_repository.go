@@ -1,115 +1,195 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/AashishRichhariya/task-management-api/internal/database"
 	"github.com/AashishRichhariya/task-management-api/internal/models"
 	_ "github.com/lib/pq"
 )
 
 type PostgresTaskRepository struct {
-	db *sql.DB
+	db           *sql.DB
+	conn         queryer // db, or the transaction the repository is bound to
+	replicas     *database.ReplicaSet
+	outbox       bool
+	queryTimeout time.Duration
+}
+
+// Optional behaviour for PostgresTaskRepository
+type PostgresTaskRepositoryOption func(*PostgresTaskRepository)
+
+// Records a task event in the outbox table in the same transaction as every create,
+// update and delete, for the outbox relay to publish
+func WithOutbox() PostgresTaskRepositoryOption {
+	return func(r *PostgresTaskRepository) {
+		r.outbox = true
+	}
+}
+
+// Bounds each call by a deadline of its own, as an optional cap on top of the deadline
+// the caller's context carries for the whole request. Rank rebalancing is exempt.
+func WithQueryTimeout(timeout time.Duration) PostgresTaskRepositoryOption {
+	return func(r *PostgresTaskRepository) {
+		r.queryTimeout = timeout
+	}
+}
+
+// Serves GetTaskByID and GetAllTasks from replicas that have replayed the request's
+// own writes (see database.ReadSession), falling back to the primary. Writes are
+// recorded in the request's read session once committed. Reads in a transaction stay in it.
+func WithReplicas(replicas *database.ReplicaSet) PostgresTaskRepositoryOption {
+	return func(r *PostgresTaskRepository) {
+		r.replicas = replicas
+	}
+}
+
+// Runs every query in tx; used by the transaction manager
+func boundToTx(tx *sql.Tx) PostgresTaskRepositoryOption {
+	return func(r *PostgresTaskRepository) {
+		r.conn = tx
+	}
 }
 
 
 type TaskRepository interface {
 	// Create operations
-	CreateTask(task *models.Task) error
+	CreateTask(ctx context.Context, task *models.Task) error
 	
 	// Read operations  
-	GetTaskByID(id int) (*models.Task, error)
-	GetAllTasks(limit, page int, status, sortBy, sortOrder string) ([]models.Task, int, error)
+	GetTaskByID(ctx context.Context, id int) (*models.Task, error)
+	GetAllTasks(ctx context.Context, limit, page int, status, sortBy, sortOrder string, customFields map[string]any) ([]models.Task, int, error)
 	
 	// Update operations
-	UpdateTask(task *models.Task) error
+	UpdateTask(ctx context.Context, task *models.Task) error
 	
 	// Delete operations
-	DeleteTask(id int) error
+	DeleteTask(ctx context.Context, id int) error
+
+	// Rank-ordered status columns
+	GetColumnTasks(ctx context.Context, status models.TaskStatus, after *models.BoardCursor, limit int) ([]models.Task, int, error)
+	GetLastRank(ctx context.Context, status models.TaskStatus, excludeID int) (string, error)
+	GetAdjacentRank(ctx context.Context, status models.TaskStatus, rank string, excludeID int, higher bool) (string, error)
+	RebalanceRanks(ctx context.Context, status models.TaskStatus, batchSize int) (int, error)
 }
 
+// Columns read by scanTask, in order
+const taskColumns = "id, title, description, status, custom_fields, rank, created_at, updated_at"
+
+// Advisory lock namespace serializing rank rebalances of a column across replicas
+const rankRebalanceLockSpace = 27
+
 // Constructor - creates new repository instance
-func NewPostgresTaskRepository(db *sql.DB) TaskRepository {
-	return &PostgresTaskRepository{db: db}
+func NewPostgresTaskRepository(db *sql.DB, opts ...PostgresTaskRepositoryOption) TaskRepository {
+	r := &PostgresTaskRepository{db: db, conn: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
-// Inserts a new task into database
-func (r *PostgresTaskRepository) CreateTask(task *models.Task) error {
+// Inserts a new task into database, appending it to its status column unless a rank is set
+func (r *PostgresTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := `
-		INSERT INTO tasks (title, description, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO tasks (title, description, status, custom_fields, rank, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id`
 	
 	now := time.Now()
 	task.CreatedAt = now
 	task.UpdatedAt = now
 	
-	err := r.db.QueryRow(query, task.Title, task.Description, task.Status, task.CreatedAt, task.UpdatedAt).Scan(&task.ID)
+	customFields, err := encodeCustomFields(task.CustomFields)
 	if err != nil {
 		return err
 	}
 	
-	return nil
+	if task.Rank == "" {
+		lastRank, err := r.GetLastRank(ctx, task.Status, 0)
+		if err != nil {
+			return err
+		}
+		if task.Rank, err = models.RankBetween(lastRank, ""); err != nil {
+			return err
+		}
+	}
+
+	return r.mutate(ctx, func(q queryer) (*models.TaskEvent, error) {
+		err := q.QueryRowContext(ctx, query, task.Title, task.Description, task.Status, customFields, task.Rank, task.CreatedAt, task.UpdatedAt).Scan(&task.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.TaskEvent{Type: models.EventTaskCreated, Task: *task, OccurredAt: now}, nil
+	})
 }
 
 // GetTaskByID retrieves a single task by ID
-func (r *PostgresTaskRepository) GetTaskByID(id int) (*models.Task, error) {
+func (r *PostgresTaskRepository) GetTaskByID(ctx context.Context, id int) (*models.Task, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := `
-		SELECT id, title, description, status, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks 
 		WHERE id = $1`
 	
-	task := &models.Task{}
-	err := r.db.QueryRow(query, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
-	
+	task, err := scanTask(r.reader(ctx).QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Task not found
 		}
-		return nil, err
+		return nil, contextError(ctx, err)
 	}
 	
 	return task, nil
 }
 
 // Retrieves all tasks
-func (r *PostgresTaskRepository) GetAllTasks(limit, page int, status, sortBy, sortOrder string) ([]models.Task, int, error) {
+func (r *PostgresTaskRepository) GetAllTasks(ctx context.Context, limit, page int, status, sortBy, sortOrder string, customFields map[string]any) ([]models.Task, int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	// Convert page to offset for database
 	offset := (page - 1) * limit
 
 	// Build the WHERE clause for filtering
-	whereClause := ""
-	args := []any{limit, offset}  // $1 = limit, $2 = offset
-	argIndex := 3
+	whereClause, filterArgs, err := buildTaskFilter(status, customFields, 3)
+	if err != nil {
+		return nil, 0, err
+	}
+	args := append([]any{limit, offset}, filterArgs...) // $1 = limit, $2 = offset
 
-	if status != "" {
-		whereClause = "WHERE status = $" + fmt.Sprintf("%d", argIndex)
-		args = append(args, status)
-		argIndex++
+	orderClause, err := buildTaskOrder(sortBy, sortOrder)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	// Build the complete query with COUNT
 	query := fmt.Sprintf(`
 		SELECT 
-			id, title, description, status, created_at, updated_at,
+			%s,
 			COUNT(*) OVER() as total_count
 		FROM tasks 
 		%s
-		ORDER BY %s %s
+		ORDER BY %s
 		LIMIT $1 OFFSET $2
-	`, whereClause, sortBy, sortOrder)
+	`, taskColumns, whereClause, orderClause)
 
-	rows, err := r.db.Query(query, args...)
+	reader := r.reader(ctx)
+	rows, err := reader.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query tasks: %w", err)
+		return nil, 0, fmt.Errorf("failed to query tasks: %w", contextError(ctx, err))
 	}
 	defer rows.Close()
 
@@ -117,89 +197,498 @@ func (r *PostgresTaskRepository) GetAllTasks(limit, page int, status, sortBy, so
 	var totalCount int
 
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&totalCount,
-		)
+		task, err := scanTask(rows, &totalCount)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan task: %w", err)
 		}
-		tasks = append(tasks, task)
+		tasks = append(tasks, *task)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+		return nil, 0, fmt.Errorf("row iteration error: %w", contextError(ctx, err))
 	}
 
 	// Handle case where no rows returned (high page number)
 	if len(tasks) == 0 {
-			// Do a separate count query to get total
-			countQuery := "SELECT COUNT(*) FROM tasks"
-			countArgs := []any{}
-			
-			if status != "" {
-					countQuery += " WHERE status = $1"
-					countArgs = append(countArgs, status)
-			}
-			
-			err = r.db.QueryRow(countQuery, countArgs...).Scan(&totalCount)
-			if err != nil {
-					return nil, 0, fmt.Errorf("failed to get count: %w", err)
-			}
+		// Do a separate count query to get total
+		countWhere, countArgs, err := buildTaskFilter(status, customFields, 1)
+		if err != nil {
+			return nil, 0, err
+		}
+		countQuery := "SELECT COUNT(*) FROM tasks " + countWhere
+
+		err = reader.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get count: %w", contextError(ctx, err))
+		}
 	}
 
 	return tasks, totalCount, nil
 }
 
 // Updates an existing task
-func (r *PostgresTaskRepository) UpdateTask(task *models.Task) error {
+func (r *PostgresTaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	query := `
 		UPDATE tasks 
-		SET title = $1, description = $2, status = $3, updated_at = $4
-		WHERE id = $5`
+		SET title = $1, description = $2, status = $3, custom_fields = $4, rank = $5, updated_at = $6
+		WHERE id = $7`
 	
 	task.UpdatedAt = time.Now()
 	
-	result, err := r.db.Exec(query, task.Title, task.Description, task.Status, task.UpdatedAt, task.ID)
+	customFields, err := encodeCustomFields(task.CustomFields)
 	if err != nil {
 		return err
 	}
 	
-	rowsAffected, err := result.RowsAffected()
+	return r.mutate(ctx, func(q queryer) (*models.TaskEvent, error) {
+		// The stored version is locked so the event describes exactly this change
+		var previous *models.Task
+		if r.outbox {
+			previous, err = scanTask(q.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1 FOR UPDATE`, task.ID))
+			if err != nil {
+				return nil, err // sql.ErrNoRows if the task does not exist
+			}
+		}
+
+		result, err := q.ExecContext(ctx, query, task.Title, task.Description, task.Status, customFields, task.Rank, task.UpdatedAt, task.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+
+		if rowsAffected == 0 {
+			return nil, sql.ErrNoRows // Task not found
+		}
+
+		if previous == nil {
+			return nil, nil
+		}
+		return &models.TaskEvent{
+			Type:       models.ClassifyTaskChange(*previous, *task),
+			Task:       *task,
+			Previous:   previous,
+			Changes:    models.DiffTasks(*previous, *task),
+			OccurredAt: task.UpdatedAt,
+		}, nil
+	})
+}
+
+// Removes a task by ID
+func (r *PostgresTaskRepository) DeleteTask(ctx context.Context, id int) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `DELETE FROM tasks WHERE id = $1 RETURNING ` + taskColumns
+
+	return r.mutate(ctx, func(q queryer) (*models.TaskEvent, error) {
+		deleted, err := scanTask(q.QueryRowContext(ctx, query, id))
+		if err != nil {
+			return nil, err // sql.ErrNoRows if the task does not exist
+		}
+		return &models.TaskEvent{Type: models.EventTaskDeleted, Task: *deleted, OccurredAt: time.Now()}, nil
+	})
+}
+
+// Runs a task mutation. With the outbox enabled it runs in a transaction together with
+// the insert of the event it returns; otherwise the event is ignored.
+func (r *PostgresTaskRepository) mutate(ctx context.Context, fn func(q queryer) (*models.TaskEvent, error)) error {
+	if !r.outbox {
+		_, err := fn(r.conn)
+		if err == nil {
+			r.recordWrite(ctx)
+		}
+		return contextError(ctx, err)
+	}
+
+	err := inTransaction(ctx, r.conn, func(q queryer) error {
+		event, err := fn(q)
+		if err != nil {
+			return err
+		}
+
+		// Updates that changed nothing visible are not events
+		if event != nil && (event.Previous == nil || len(event.Changes) > 0) {
+			return insertOutboxMessage(ctx, q, *event)
+		}
+		return nil
+	})
+	if err == nil {
+		r.recordWrite(ctx)
+	}
+	return contextError(ctx, err)
+}
+
+// Where reads that may be served by a replica go
+func (r *PostgresTaskRepository) reader(ctx context.Context) queryer {
+	if _, inTx := r.conn.(*sql.Tx); inTx || r.replicas == nil {
+		return r.conn
+	}
+	return r.replicas.Reader(ctx)
+}
+
+// Records a committed write for read-your-writes; the transaction manager does it
+// for writes made in a transaction, once that commits
+func (r *PostgresTaskRepository) recordWrite(ctx context.Context) {
+	if _, inTx := r.conn.(*sql.Tx); inTx || r.replicas == nil {
+		return
+	}
+	if err := database.RecordWrite(ctx, r.db); err != nil {
+		log.Printf("Reads after this write may be stale: %v", err)
+	}
+}
+
+// Retrieves one page of a status column in rank order, starting after the cursor position
+// (nil for the first page), together with the total number of tasks in the column
+func (r *PostgresTaskRepository) GetColumnTasks(ctx context.Context, status models.TaskStatus, after *models.BoardCursor, limit int) ([]models.Task, int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+	if err := r.conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks WHERE status = $1`, status).Scan(&count); err != nil {
+		return nil, 0, fmt.Errorf("failed to count column: %w", contextError(ctx, err))
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE status = $1 ORDER BY rank, id LIMIT $2`
+	args := []any{status, limit}
+	if after != nil {
+		query = `SELECT ` + taskColumns + ` FROM tasks WHERE status = $1 AND (rank, id) > ($3, $4) ORDER BY rank, id LIMIT $2`
+		args = append(args, after.Rank, after.ID)
+	}
+
+	rows, err := r.conn.QueryContext(ctx, query, args...)
 	if err != nil {
-		return err
+		return nil, 0, fmt.Errorf("failed to query column: %w", contextError(ctx, err))
 	}
+	defer rows.Close()
 	
-	if rowsAffected == 0 {
-		return sql.ErrNoRows // Task not found
+	tasks := []models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("row iteration error: %w", contextError(ctx, err))
 	}
 	
-	return nil
+	return tasks, count, nil
 }
 
-// Removes a task by ID
-func (r *PostgresTaskRepository) DeleteTask(id int) error {
-	query := `DELETE FROM tasks WHERE id = $1`
+// Returns the highest rank in a status column, or "" for an empty column
+func (r *PostgresTaskRepository) GetLastRank(ctx context.Context, status models.TaskStatus, excludeID int) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE status = $1 AND id <> $2`
+
+	var rank string
+	if err := r.conn.QueryRowContext(ctx, query, status, excludeID).Scan(&rank); err != nil {
+		return "", fmt.Errorf("failed to get last rank: %w", contextError(ctx, err))
+	}
+	return rank, nil
+}
+
+// Returns the nearest rank above (higher) or below a given rank in a status column, or "" if there is none
+func (r *PostgresTaskRepository) GetAdjacentRank(ctx context.Context, status models.TaskStatus, rank string, excludeID int, higher bool) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE status = $1 AND rank < $2 AND id <> $3`
+	if higher {
+		query = `SELECT COALESCE(MIN(rank), '') FROM tasks WHERE status = $1 AND rank > $2 AND id <> $3`
+	}
 	
-	result, err := r.db.Exec(query, id)
+	var adjacent string
+	if err := r.conn.QueryRowContext(ctx, query, status, rank, excludeID).Scan(&adjacent); err != nil {
+		return "", fmt.Errorf("failed to get adjacent rank: %w", contextError(ctx, err))
+	}
+	return adjacent, nil
+}
+
+// Respaces the ranks of a status column evenly, moving them into the next bucket.
+// Rows are migrated in short batches starting from the end of the column that keeps the
+// order intact, so concurrent moves and inserts are never blocked for long. A session
+// advisory lock makes sure only one replica rebalances a given column at a time; if it is
+// already held the call returns immediately. Returns the number of tasks re-ranked.
+// It always runs on a connection of its own, even when bound to a transaction.
+func (r *PostgresTaskRepository) RebalanceRanks(ctx context.Context, status models.TaskStatus, batchSize int) (int, error) {
+	conn, err := r.db.Conn(ctx)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1, hashtext($2))`, rankRebalanceLockSpace, string(status)).Scan(&locked)
+	if err != nil {
+		return 0, fmt.Errorf("failed to acquire rebalance lock: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1, hashtext($2))`, rankRebalanceLockSpace, string(status))
+
+	// Work out which bucket to migrate from
+	rows, err := conn.QueryContext(ctx, `SELECT DISTINCT LEFT(rank, 1)::int FROM tasks WHERE status = $1`, status)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rank buckets: %w", err)
+	}
+	var buckets []int
+	for rows.Next() {
+		var bucket int
+		if err := rows.Scan(&bucket); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		buckets = append(buckets, bucket)
+	}
+	rows.Close()
+	if len(buckets) == 0 {
+		return 0, nil
 	}
 	
-	rowsAffected, err := result.RowsAffected()
+	from, to, tailFirst := models.RankRebalanceBuckets(buckets)
+	lower, upper := models.RankBucketRange(from)
+
+	var total int
+	err = conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks WHERE status = $1 AND rank >= $2 AND rank < $3`,
+		status, lower, upper).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count ranks: %w", err)
+	}
+
+	// Rows an interrupted run already migrated sit at one end of the target bucket;
+	// the rest of the column is spaced out beyond them
+	targetLower, targetUpper := models.RankBucketRange(to)
+	edgeQuery := `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE status = $1 AND rank >= $2 AND rank < $3`
+	if tailFirst {
+		edgeQuery = `SELECT COALESCE(MIN(rank), '') FROM tasks WHERE status = $1 AND rank >= $2 AND rank < $3`
+	}
+	var migratedEdge string
+	if err := conn.QueryRowContext(ctx, edgeQuery, status, targetLower, targetUpper).Scan(&migratedEdge); err != nil {
+		return 0, fmt.Errorf("failed to read migrated ranks: %w", err)
+	}
+	var below, above string // Bounds of the new values
+	if migratedEdge != "" {
+		_, edgeValue, err := models.ParseRank(migratedEdge)
+		if err != nil {
+			return 0, err
+		}
+		if tailFirst {
+			above = edgeValue
+		} else {
+			below = edgeValue
+		}
+	}
+
+	direction := "ASC"
+	position, step := 1, 1
+	if tailFirst {
+		direction = "DESC"
+		position, step = total, -1
+	}
+	batchQuery := fmt.Sprintf(`
+		SELECT id FROM tasks
+		WHERE status = $1 AND rank >= $2 AND rank < $3
+		ORDER BY rank %s, id %s
+		LIMIT $4
+		FOR UPDATE`, direction, direction)
+
+	migrated := 0
+	edge := "" // last rank assigned, for rows that arrived after counting
+	for {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return migrated, err
+		}
+
+		ids, err := queryIDs(ctx, tx, batchQuery, status, lower, upper, batchSize)
+		if err != nil || len(ids) == 0 {
+			tx.Rollback()
+			return migrated, err
+		}
+
+		for _, id := range ids {
+			var rank string
+			if position >= 1 && position <= total {
+				rank = models.FormatRank(to, models.EvenRankValueBetween(below, above, position, total))
+			} else if tailFirst {
+				rank, err = models.RankBetween("", edge)
+			} else {
+				rank, err = models.RankBetween(edge, "")
+			}
+			if err != nil {
+				tx.Rollback()
+				return migrated, err
+			}
+
+			if _, err := tx.ExecContext(ctx, `UPDATE tasks SET rank = $1 WHERE id = $2`, rank, id); err != nil {
+				tx.Rollback()
+				return migrated, err
+			}
+			edge = rank
+			position += step
+		}
+
+		if err := tx.Commit(); err != nil {
+			return migrated, err
+		}
+		migrated += len(ids)
+	}
+}
+
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Builds the WHERE clause for list and count queries, numbering placeholders from argIndex.
+// Custom field keys are embedded literally so the per-field expression indexes can be used.
+func buildTaskFilter(status string, customFields map[string]any, argIndex int) (string, []any, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, status)
+		argIndex++
+	}
+
+	keys := make([]string, 0, len(customFields))
+	for key := range customFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !models.IsValidCustomFieldKey(key) {
+			return "", nil, fmt.Errorf("invalid custom field key %q", key)
+		}
+		value, err := json.Marshal(customFields[key])
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode filter for custom field %q: %w", key, err)
+		}
+		conditions = append(conditions, fmt.Sprintf("custom_fields -> '%s' = $%d::jsonb", key, argIndex))
+		args = append(args, string(value))
+		argIndex++
+	}
+
+	if len(conditions) == 0 {
+		return "", args, nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// Builds the ORDER BY expression; jsonb ordering keeps numbers numeric and ISO dates chronological
+func buildTaskOrder(sortBy, sortOrder string) (string, error) {
+	if sortOrder != "asc" && sortOrder != "desc" {
+		return "", fmt.Errorf("invalid sort order %q", sortOrder)
+	}
+	if key, ok := models.CustomFieldSortKey(sortBy); ok {
+		if !models.IsValidCustomFieldKey(key) {
+			return "", fmt.Errorf("invalid custom field key %q", key)
+		}
+		return fmt.Sprintf("custom_fields -> '%s' %s NULLS LAST, id %s", key, sortOrder, sortOrder), nil
+	}
+	if !models.IsValidTaskSortField(sortBy) {
+		return "", fmt.Errorf("invalid sort field %q", sortBy)
+	}
+	if sortBy == "rank" {
+		// Ranks are only unique per column, id keeps the order stable
+		return fmt.Sprintf("rank %s, id %s", sortOrder, sortOrder), nil
+	}
+	return sortBy + " " + sortOrder, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Applies the query timeout, if any, to ctx
+func (r *PostgresTaskRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
+
+// Reports a query that failed because its context ended as that context's error, so
+// callers can tell timeouts and disconnects apart from database failures. The driver
+// itself only reports that the statement was cancelled.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, ctx.Err()) {
 		return err
 	}
+	return fmt.Errorf("%w (%v)", ctx.Err(), err)
+}
+
+// Scans the taskColumns of a row, followed by any extra columns
+func scanTask(row rowScanner, extra ...any) (*models.Task, error) {
+	task := &models.Task{}
+	var customFields []byte
+	dest := append([]any{
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&customFields,
+		&task.Rank,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+	}, extra...)
 	
-	if rowsAffected == 0 {
-		return sql.ErrNoRows // Task not found
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
 	}
 	
-	return nil
+	var err error
+	if task.CustomFields, err = decodeCustomFields(customFields); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func encodeCustomFields(fields map[string]any) (string, error) {
+	if fields == nil {
+		return "{}", nil
+	}
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode custom fields: %w", err)
+	}
+	return string(encoded), nil
+}
+
+func decodeCustomFields(raw []byte) (map[string]any, error) {
+	fields := map[string]any{}
+	if len(raw) == 0 {
+		return fields, nil
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode custom fields: %w", err)
+	}
+	return fields, nil
 }
\ No newline at end of file
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -23,7 +25,7 @@ func TestPostgresTaskRepository_CreateTask(t *testing.T) {
 	}
 	
 	// Execute
-	err := repo.CreateTask(task)
+	err := repo.CreateTask(context.Background(), task)
 	
 	// Assert
 	if err != nil {
@@ -60,13 +62,13 @@ func TestPostgresTaskRepository_GetTaskByID(t *testing.T) {
 		Status:      models.StatusInProgress,
 	}
 	
-	err := repo.CreateTask(originalTask)
+	err := repo.CreateTask(context.Background(), originalTask)
 	if err != nil {
 		t.Fatalf("Failed to create task for test: %v", err)
 	}
 	
 	// Execute
-	retrievedTask, err := repo.GetTaskByID(originalTask.ID)
+	retrievedTask, err := repo.GetTaskByID(context.Background(), originalTask.ID)
 	
 	// Assert
 	if err != nil {
@@ -100,7 +102,7 @@ func TestPostgresTaskRepository_GetTaskByID_NotFound(t *testing.T) {
 	repo := NewPostgresTaskRepository(db)
 	
 	// Execute - try to get non-existent task
-	task, err := repo.GetTaskByID(99999)
+	task, err := repo.GetTaskByID(context.Background(), 99999)
 	
 	// Assert
 	if err != nil {
@@ -128,14 +130,14 @@ func TestPostgresTaskRepository_GetAllTasks(t *testing.T) {
 	}
 	
 	for _, task := range tasks {
-		err := repo.CreateTask(task)
+		err := repo.CreateTask(context.Background(), task)
 		if err != nil {
 			t.Fatalf("Failed to create test task: %v", err)
 		}
 	}
 	
 	// Execute
-	allTasks, totalCount, err := repo.GetAllTasks(10, 1, "", "created_at", "desc")
+	allTasks, totalCount, err := repo.GetAllTasks(context.Background(), 10, 1, "", "created_at", "desc", nil)
 	
 	// Assert
 	if totalCount != 3 {
@@ -173,7 +175,7 @@ func TestPostgresTaskRepository_UpdateTask(t *testing.T) {
 		Status:      models.StatusPending,
 	}
 	
-	err := repo.CreateTask(task)
+	err := repo.CreateTask(context.Background(), task)
 	if err != nil {
 		t.Fatalf("Failed to create task: %v", err)
 	}
@@ -185,7 +187,7 @@ func TestPostgresTaskRepository_UpdateTask(t *testing.T) {
 	task.Title = "Updated Title"
 	task.Status = models.StatusCompleted
 	
-	err = repo.UpdateTask(task)
+	err = repo.UpdateTask(context.Background(), task)
 	
 	// Assert
 	if err != nil {
@@ -198,7 +200,7 @@ func TestPostgresTaskRepository_UpdateTask(t *testing.T) {
 	}
 	
 	// Verify changes persisted
-	updated, err := repo.GetTaskByID(task.ID)
+	updated, err := repo.GetTaskByID(context.Background(), task.ID)
 	if err != nil {
 		t.Fatalf("Failed to retrieve updated task: %v", err)
 	}
@@ -226,13 +228,13 @@ func TestPostgresTaskRepository_DeleteTask(t *testing.T) {
 		Status: models.StatusPending,
 	}
 	
-	err := repo.CreateTask(task)
+	err := repo.CreateTask(context.Background(), task)
 	if err != nil {
 		t.Fatalf("Failed to create task: %v", err)
 	}
 	
 	// Execute delete
-	err = repo.DeleteTask(task.ID)
+	err = repo.DeleteTask(context.Background(), task.ID)
 	
 	// Assert
 	if err != nil {
@@ -240,7 +242,7 @@ func TestPostgresTaskRepository_DeleteTask(t *testing.T) {
 	}
 	
 	// Verify task is gone
-	deleted, err := repo.GetTaskByID(task.ID)
+	deleted, err := repo.GetTaskByID(context.Background(), task.ID)
 	if err != nil {
 		t.Fatalf("Error checking if task was deleted: %v", err)
 	}
@@ -248,4 +250,129 @@ func TestPostgresTaskRepository_DeleteTask(t *testing.T) {
 	if deleted != nil {
 		t.Error("Expected task to be deleted")
 	}
-}
\ No newline at end of file
+}
+
+func TestPostgresTaskRepository_RebalanceRanks(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	repo := NewPostgresTaskRepository(db)
+
+	// Create tasks with long ranks, in a known order
+	rank, _ := models.RankBetween("", "")
+	next := ""
+	var ids []int
+	for i := 0; i < 5; i++ {
+		task := &models.Task{Title: "Task", Status: models.StatusPending, Rank: rank}
+		if err := repo.CreateTask(context.Background(), task); err != nil {
+			t.Fatalf("Failed to create test task: %v", err)
+		}
+		ids = append([]int{task.ID}, ids...)
+		next = rank
+		rank, _ = models.RankBetween("", next)
+	}
+
+	// Execute with a small batch size to exercise batching
+	migrated, err := repo.RebalanceRanks(context.Background(), models.StatusPending, 2)
+	if err != nil {
+		t.Fatalf("RebalanceRanks failed: %v", err)
+	}
+
+	// Assert
+	if migrated != 5 {
+		t.Errorf("Expected 5 tasks re-ranked, got %d", migrated)
+	}
+
+	tasks, _, err := repo.GetAllTasks(context.Background(), 10, 1, "pending", "rank", "asc", nil)
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+
+	for i, task := range tasks {
+		if task.ID != ids[i] {
+			t.Fatalf("Expected order %v to be preserved, position %d has task %d", ids, i, task.ID)
+		}
+		if bucket, _, _ := models.ParseRank(task.Rank); bucket != 1 {
+			t.Errorf("Expected task %d to move to bucket 1, got rank %s", task.ID, task.Rank)
+		}
+	}
+}
+
+func TestPostgresTaskRepository_Outbox(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	repo := NewPostgresTaskRepository(db, WithOutbox())
+	outboxRepo := NewPostgresOutboxRepository(db)
+
+	// Execute
+	task := &models.Task{Title: "Outboxed", Status: models.StatusPending}
+	if err := repo.CreateTask(context.Background(), task); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task.Status = models.StatusCompleted
+	if err := repo.UpdateTask(context.Background(), task); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if err := repo.UpdateTask(context.Background(), task); err != nil { // No visible change, no event
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if err := repo.DeleteTask(context.Background(), task.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	// Assert
+	var events []models.TaskEvent
+	published, err := outboxRepo.PublishBatch(10, func(int) time.Duration { return 0 }, func(message models.OutboxMessage) error {
+		event, err := message.Event()
+		events = append(events, event)
+		return err
+	})
+	if err != nil || published != 3 {
+		t.Fatalf("Expected 3 published messages, got %d (%v)", published, err)
+	}
+
+	expected := []models.TaskEventType{models.EventTaskCreated, models.EventTaskStatusChanged, models.EventTaskDeleted}
+	for i, eventType := range expected {
+		if events[i].Type != eventType || events[i].Task.ID != task.ID {
+			t.Errorf("Expected event %d to be %s, got %+v", i, eventType, events[i])
+		}
+	}
+	if events[1].Previous == nil || events[1].Previous.Status != models.StatusPending {
+		t.Errorf("Expected the status change to carry the previous version, got %+v", events[1].Previous)
+	}
+
+	// Published messages are not handed out again
+	published, _ = outboxRepo.PublishBatch(10, func(int) time.Duration { return 0 }, func(models.OutboxMessage) error { return nil })
+	if published != 0 {
+		t.Errorf("Expected no unpublished messages, got %d", published)
+	}
+}
+
+func TestPostgresTaskRepository_QueryTimeout(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	repo := NewPostgresTaskRepository(db, WithQueryTimeout(time.Nanosecond))
+
+	// Execute
+	_, err := repo.GetTaskByID(context.Background(), 1)
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a deadline error, got %v", err)
+	}
+
+	// A cancelled caller is reported as such
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := NewPostgresTaskRepository(db).GetAllTasks(ctx, 10, 1, "", "", "", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancellation error, got %v", err)
+	}
+}
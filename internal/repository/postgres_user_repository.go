@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/lib/pq"
+)
+
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+type UserRepository interface {
+	CreateUser(user *models.User, tokenHash string) error
+	GetUserByID(id int) (*models.User, error)
+	GetUserByUsername(username string) (*models.User, error)
+	GetUserByTokenHash(tokenHash string) (*models.User, error)
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.User, error)
+}
+
+func NewPostgresUserRepository(db *sql.DB) UserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+// Inserts a new user with the hash of their API token
+func (r *PostgresUserRepository) CreateUser(user *models.User, tokenHash string) error {
+	query := `
+		INSERT INTO users (username, email, display_name, api_token_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	user.CreatedAt = time.Now()
+
+	err := r.db.QueryRow(query, user.Username, user.Email, user.DisplayName, tokenHash, user.CreatedAt).Scan(&user.ID)
+	if isUniqueViolation(err) {
+		if violatedConstraint(err) == "users_email_key" {
+			return models.BusinessError{Message: fmt.Sprintf("email '%s' is already registered", user.Email)}
+		}
+		// Two signups racing for the same username, in any case
+		return models.BusinessError{Message: fmt.Sprintf("username '%s' is already taken", user.Username)}
+	}
+	return err
+}
+
+func (r *PostgresUserRepository) GetUserByID(id int) (*models.User, error) {
+	return r.getUser(`WHERE id = $1`, id)
+}
+
+// Usernames are matched case-insensitively, as they are typed in @mentions
+func (r *PostgresUserRepository) GetUserByUsername(username string) (*models.User, error) {
+	return r.getUser(`WHERE LOWER(username) = LOWER($1)`, username)
+}
+
+func (r *PostgresUserRepository) GetUserByTokenHash(tokenHash string) (*models.User, error) {
+	return r.getUser(`WHERE api_token_hash = $1`, tokenHash)
+}
+
+// Resolves several usernames at once, case-insensitively; unknown names are skipped
+func (r *PostgresUserRepository) GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.User, error) {
+	users := []models.User{}
+	if len(usernames) == 0 {
+		return users, nil
+	}
+
+	lowered := make([]string, len(usernames))
+	for i, username := range usernames {
+		lowered[i] = strings.ToLower(username)
+	}
+
+	query := `
+		SELECT id, username, email, display_name, created_at
+		FROM users
+		WHERE LOWER(username) = ANY($1)
+		ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lowered))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", contextError(ctx, err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.DisplayName, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *PostgresUserRepository) getUser(where string, arg any) (*models.User, error) {
+	query := `SELECT id, username, email, display_name, created_at FROM users ` + where
+
+	user := &models.User{}
+	err := r.db.QueryRow(query, arg).Scan(&user.ID, &user.Username, &user.Email, &user.DisplayName, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // User not found
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestPostgresUserRepository_CreateUser_Duplicates(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	repo := NewPostgresUserRepository(db)
+	if err := repo.CreateUser(&models.User{Username: "bob", Email: "bob@example.com"}, "hash-1"); err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		user      models.User
+		tokenHash string
+	}{
+		{"same email", models.User{Username: "robert", Email: "bob@example.com"}, "hash-2"},
+		{"username in another case", models.User{Username: "Bob", Email: "other@example.com"}, "hash-3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Execute
+			err := repo.CreateUser(&test.user, test.tokenHash)
+
+			// Assert
+			var businessErr models.BusinessError
+			if !errors.As(err, &businessErr) {
+				t.Errorf("Expected a BusinessError, got %v", err)
+			}
+		})
+	}
+}
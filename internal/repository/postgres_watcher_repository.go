@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+type PostgresWatcherRepository struct {
+	db queryer
+}
+
+type WatcherRepository interface {
+	AddWatcher(taskID, userID int) error
+	RemoveWatcher(taskID, userID int) error
+	RemoveAllWatchers(taskID int) error
+	GetWatchers(taskID int) ([]models.User, error)
+}
+
+func NewPostgresWatcherRepository(db *sql.DB) WatcherRepository {
+	return &PostgresWatcherRepository{db: db}
+}
+
+// Adds a watcher; watching a task twice is a no-op
+func (r *PostgresWatcherRepository) AddWatcher(taskID, userID int) error {
+	query := `
+		INSERT INTO task_watchers (task_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (task_id, user_id) DO NOTHING`
+
+	_, err := r.db.Exec(query, taskID, userID)
+	return err
+}
+
+func (r *PostgresWatcherRepository) RemoveWatcher(taskID, userID int) error {
+	_, err := r.db.Exec(`DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2`, taskID, userID)
+	return err
+}
+
+func (r *PostgresWatcherRepository) RemoveAllWatchers(taskID int) error {
+	_, err := r.db.Exec(`DELETE FROM task_watchers WHERE task_id = $1`, taskID)
+	return err
+}
+
+func (r *PostgresWatcherRepository) GetWatchers(taskID int) ([]models.User, error) {
+	query := `
+		SELECT u.id, u.username, u.email, u.display_name, u.created_at
+		FROM task_watchers w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.task_id = $1
+		ORDER BY w.created_at, u.id`
+
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchers: %w", err)
+	}
+	defer rows.Close()
+
+	watchers := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.DisplayName, &user.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watcher: %w", err)
+		}
+		watchers = append(watchers, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return watchers, nil
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/lib/pq"
+)
+
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+type WebhookRepository interface {
+	CreateWebhook(webhook *models.Webhook) error
+	GetWebhookByID(id int) (*models.Webhook, error)
+	GetWebhooksByOwner(ownerID int) ([]models.Webhook, error)
+	DeleteWebhook(id, ownerID int) error
+
+	EnqueueDeliveries(eventID int64, eventType models.TaskEventType, payload []byte) (int, error)
+	EnqueueDelivery(webhookID int, event string, payload []byte) (bool, error)
+	ClaimDueDeliveries(limit int, leaseUntil time.Time) ([]models.WebhookDeliveryJob, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	GetDeliveries(webhookID int, limit, page int) ([]models.WebhookDelivery, int, error)
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) WebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+func (r *PostgresWebhookRepository) CreateWebhook(webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (url, secret, event_types, active, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	now := time.Now()
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	return r.db.QueryRow(query, webhook.URL, webhook.Secret, pq.Array(eventTypeStrings(webhook.EventTypes)),
+		webhook.Active, webhook.CreatedBy, webhook.CreatedAt, webhook.UpdatedAt).Scan(&webhook.ID)
+}
+
+// Returns the webhook without its secret, or nil if it does not exist
+func (r *PostgresWebhookRepository) GetWebhookByID(id int) (*models.Webhook, error) {
+	query := `
+		SELECT id, url, event_types, active, COALESCE(created_by, 0), created_at, updated_at
+		FROM webhooks
+		WHERE id = $1`
+
+	webhook, err := scanWebhook(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil // Webhook not found
+	}
+	return webhook, err
+}
+
+// Returns the webhooks registered by a user, without their secrets
+func (r *PostgresWebhookRepository) GetWebhooksByOwner(ownerID int) ([]models.Webhook, error) {
+	query := `
+		SELECT id, url, event_types, active, created_by, created_at, updated_at
+		FROM webhooks
+		WHERE created_by = $1
+		ORDER BY id`
+
+	rows, err := r.db.Query(query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// Deletes a user's webhook together with its delivery log
+func (r *PostgresWebhookRepository) DeleteWebhook(id, ownerID int) error {
+	result, err := r.db.Exec(`DELETE FROM webhooks WHERE id = $1 AND created_by = $2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows // Webhook not found or owned by someone else
+	}
+
+	return nil
+}
+
+// Queues a delivery of payload for every active webhook subscribed to the event, in a
+// single statement. eventID is the outbox message it is for, if any: webhooks that
+// already have a delivery of that message are skipped, so relaying it again is
+// harmless. Returns the number of deliveries queued.
+func (r *PostgresWebhookRepository) EnqueueDeliveries(eventID int64, eventType models.TaskEventType, payload []byte) (int, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at, created_at, outbox_id)
+		SELECT id, $1, $2, 'pending', NOW(), NOW(), $3
+		FROM webhooks
+		WHERE active AND $1 = ANY(event_types)
+		ON CONFLICT (outbox_id, webhook_id) DO NOTHING`
+
+	result, err := r.db.Exec(query, string(eventType), string(payload), outboxID(eventID))
+	if err != nil {
+		return 0, err
+	}
+
+	queued, err := result.RowsAffected()
+	return int(queued), err
+}
+
+// Queues a delivery of payload to one webhook regardless of its event types. Reports
+// false if there is no such active webhook.
+func (r *PostgresWebhookRepository) EnqueueDelivery(webhookID int, event string, payload []byte) (bool, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at, created_at)
+		SELECT id, $2, $3, 'pending', NOW(), NOW()
+		FROM webhooks
+		WHERE id = $1 AND active`
+
+	result, err := r.db.Exec(query, webhookID, event, string(payload))
+	if err != nil {
+		return false, err
+	}
+
+	queued, err := result.RowsAffected()
+	return queued > 0, err
+}
+
+// Claims up to limit due deliveries. Claimed rows are leased by pushing their next attempt
+// to leaseUntil, so deliveries held by a dispatcher that dies are picked up again later.
+// SKIP LOCKED lets several instances dispatch concurrently without claiming the same rows.
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(limit int, leaseUntil time.Time) ([]models.WebhookDeliveryJob, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM webhooks w
+		WHERE w.id = d.webhook_id
+			AND d.id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at, id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.created_at, w.url, w.secret`
+
+	rows, err := r.db.Query(query, limit, leaseUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []models.WebhookDeliveryJob{}
+	for rows.Next() {
+		var job models.WebhookDeliveryJob
+		delivery := &job.Delivery
+		var payload []byte
+		err := rows.Scan(&delivery.ID, &delivery.WebhookID, &delivery.Event, &payload, &delivery.Status,
+			&delivery.Attempts, &delivery.CreatedAt, &job.URL, &job.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		delivery.Payload = payload
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// Stores the outcome of a delivery attempt
+func (r *PostgresWebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4,
+			response_status = $5, delivered_at = $6
+		WHERE id = $7`
+
+	_, err := r.db.Exec(query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastError,
+		delivery.ResponseStatus, delivery.DeliveredAt, delivery.ID)
+	return err
+}
+
+// Retrieves a page of a webhook's delivery log, newest first
+func (r *PostgresWebhookRepository) GetDeliveries(webhookID int, limit, page int) ([]models.WebhookDelivery, int, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT id, webhook_id, event, payload, status, attempts, next_attempt_at, last_error,
+			response_status, created_at, delivered_at, COUNT(*) OVER() as total_count
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, webhookID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	var totalCount int
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var payload []byte
+		var nextAttemptAt, deliveredAt sql.NullTime
+		var responseStatus sql.NullInt64
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.Event,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&nextAttemptAt,
+			&delivery.LastError,
+			&responseStatus,
+			&delivery.CreatedAt,
+			&deliveredAt,
+			&totalCount,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		delivery.Payload = payload
+		if nextAttemptAt.Valid && delivery.Status == models.DeliveryPending {
+			delivery.NextAttemptAt = &nextAttemptAt.Time
+		}
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		if responseStatus.Valid {
+			status := int(responseStatus.Int64)
+			delivery.ResponseStatus = &status
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// Handle case where no rows returned (high page number)
+	if len(deliveries) == 0 {
+		if err := r.db.QueryRow(`SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1`, webhookID).Scan(&totalCount); err != nil {
+			return nil, 0, fmt.Errorf("failed to get count: %w", err)
+		}
+	}
+
+	return deliveries, totalCount, nil
+}
+
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+	var eventTypes []string
+	err := row.Scan(&webhook.ID, &webhook.URL, pq.Array(&eventTypes), &webhook.Active,
+		&webhook.CreatedBy, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan webhook: %w", err)
+	}
+
+	for _, eventType := range eventTypes {
+		webhook.EventTypes = append(webhook.EventTypes, models.TaskEventType(eventType))
+	}
+	return webhook, nil
+}
+
+func eventTypeStrings(eventTypes []models.TaskEventType) []string {
+	values := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		values[i] = string(eventType)
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestPostgresWebhookRepository_ScopedToOwner(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	userRepo := NewPostgresUserRepository(db)
+	repo := NewPostgresWebhookRepository(db)
+
+	alice := &models.User{Username: "alice", Email: "alice@example.com"}
+	bob := &models.User{Username: "bob", Email: "bob@example.com"}
+	for i, user := range []*models.User{alice, bob} {
+		if err := userRepo.CreateUser(user, string(rune('a'+i))+"-token-hash"); err != nil {
+			t.Fatalf("CreateUser failed: %v", err)
+		}
+	}
+	webhook := &models.Webhook{URL: "https://example.com/hook", Secret: "a-very-secret-value", Active: true, CreatedBy: alice.ID,
+		EventTypes: []models.TaskEventType{models.EventTaskCreated}}
+	if err := repo.CreateWebhook(webhook); err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+
+	// Execute & Assert
+	found, err := repo.GetWebhookByID(webhook.ID)
+	if err != nil || found == nil || found.CreatedBy != alice.ID {
+		t.Fatalf("Expected the webhook owned by %d, got %+v (%v)", alice.ID, found, err)
+	}
+
+	if webhooks, err := repo.GetWebhooksByOwner(bob.ID); err != nil || len(webhooks) != 0 {
+		t.Errorf("Expected no webhooks for another user, got %v (%v)", webhooks, err)
+	}
+	if webhooks, err := repo.GetWebhooksByOwner(alice.ID); err != nil || len(webhooks) != 1 {
+		t.Errorf("Expected the owner's webhook, got %v (%v)", webhooks, err)
+	}
+
+	if err := repo.DeleteWebhook(webhook.ID, bob.ID); err != sql.ErrNoRows {
+		t.Errorf("Expected another user's delete to find nothing, got %v", err)
+	}
+	if err := repo.DeleteWebhook(webhook.ID, alice.ID); err != nil {
+		t.Errorf("DeleteWebhook failed: %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+// Stores tasks in a SQLite database, for running the API without Postgres. It behaves
+// like PostgresTaskRepository, except that titles sort byte-wise rather than by the
+// database's locale and there is no outbox.
+type SQLiteTaskRepository struct {
+	db *sql.DB
+}
+
+// Constructor - creates new repository instance over a database from database.NewSQLiteConnection
+func NewSQLiteTaskRepository(db *sql.DB) TaskRepository {
+	return &SQLiteTaskRepository{db: db}
+}
+
+// Inserts a new task into database, appending it to its status column unless a rank is set
+func (r *SQLiteTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	query := `
+		INSERT INTO tasks (title, description, status, custom_fields, rank, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id`
+
+	// Timestamps are stored as text, so they are kept in UTC to sort chronologically
+	now := time.Now().UTC()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	customFields, err := encodeCustomFields(task.CustomFields)
+	if err != nil {
+		return err
+	}
+
+	if task.Rank == "" {
+		lastRank, err := r.GetLastRank(ctx, task.Status, 0)
+		if err != nil {
+			return err
+		}
+		if task.Rank, err = models.RankBetween(lastRank, ""); err != nil {
+			return err
+		}
+	}
+
+	err = r.db.QueryRowContext(ctx, query, task.Title, task.Description, task.Status, customFields, task.Rank, task.CreatedAt, task.UpdatedAt).Scan(&task.ID)
+	return contextError(ctx, err)
+}
+
+// GetTaskByID retrieves a single task by ID
+func (r *SQLiteTaskRepository) GetTaskByID(ctx context.Context, id int) (*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = ?`
+
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Task not found
+		}
+		return nil, contextError(ctx, err)
+	}
+
+	return task, nil
+}
+
+// Retrieves all tasks
+func (r *SQLiteTaskRepository) GetAllTasks(ctx context.Context, limit, page int, status, sortBy, sortOrder string, customFields map[string]any) ([]models.Task, int, error) {
+	offset := (page - 1) * limit
+
+	whereClause, args, err := buildSQLiteTaskFilter(status, customFields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orderClause, err := buildSQLiteTaskOrder(sortBy, sortOrder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s,
+			COUNT(*) OVER() as total_count
+		FROM tasks
+		%s
+		ORDER BY %s
+		LIMIT ? OFFSET ?
+	`, taskColumns, whereClause, orderClause)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query tasks: %w", contextError(ctx, err))
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	var totalCount int
+
+	for rows.Next() {
+		task, err := scanTask(rows, &totalCount)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("row iteration error: %w", contextError(ctx, err))
+	}
+	rows.Close() // Frees the single connection for the count query
+
+	// Handle case where no rows returned (high page number)
+	if len(tasks) == 0 {
+		err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks "+whereClause, args...).Scan(&totalCount)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get count: %w", contextError(ctx, err))
+		}
+	}
+
+	return tasks, totalCount, nil
+}
+
+// Updates an existing task
+func (r *SQLiteTaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	query := `
+		UPDATE tasks
+		SET title = ?, description = ?, status = ?, custom_fields = ?, rank = ?, updated_at = ?
+		WHERE id = ?`
+
+	task.UpdatedAt = time.Now().UTC()
+
+	customFields, err := encodeCustomFields(task.CustomFields)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, task.Title, task.Description, task.Status, customFields, task.Rank, task.UpdatedAt, task.ID)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows // Task not found
+	}
+	return nil
+}
+
+// Removes a task by ID
+func (r *SQLiteTaskRepository) DeleteTask(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows // Task not found
+	}
+	return nil
+}
+
+// Retrieves one page of a status column in rank order, starting after the cursor position
+// (nil for the first page), together with the total number of tasks in the column
+func (r *SQLiteTaskRepository) GetColumnTasks(ctx context.Context, status models.TaskStatus, after *models.BoardCursor, limit int) ([]models.Task, int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks WHERE status = ?`, status).Scan(&count); err != nil {
+		return nil, 0, fmt.Errorf("failed to count column: %w", contextError(ctx, err))
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE status = ? ORDER BY rank, id LIMIT ?`
+	args := []any{status, limit}
+	if after != nil {
+		query = `SELECT ` + taskColumns + ` FROM tasks WHERE status = ? AND (rank, id) > (?, ?) ORDER BY rank, id LIMIT ?`
+		args = []any{status, after.Rank, after.ID, limit}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query column: %w", contextError(ctx, err))
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("row iteration error: %w", contextError(ctx, err))
+	}
+
+	return tasks, count, nil
+}
+
+// Returns the highest rank in a status column, or "" for an empty column
+func (r *SQLiteTaskRepository) GetLastRank(ctx context.Context, status models.TaskStatus, excludeID int) (string, error) {
+	query := `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE status = ? AND id <> ?`
+
+	var rank string
+	if err := r.db.QueryRowContext(ctx, query, status, excludeID).Scan(&rank); err != nil {
+		return "", fmt.Errorf("failed to get last rank: %w", contextError(ctx, err))
+	}
+	return rank, nil
+}
+
+// Returns the nearest rank above (higher) or below a given rank in a status column, or "" if there is none
+func (r *SQLiteTaskRepository) GetAdjacentRank(ctx context.Context, status models.TaskStatus, rank string, excludeID int, higher bool) (string, error) {
+	query := `SELECT COALESCE(MAX(rank), '') FROM tasks WHERE status = ? AND rank < ? AND id <> ?`
+	if higher {
+		query = `SELECT COALESCE(MIN(rank), '') FROM tasks WHERE status = ? AND rank > ? AND id <> ?`
+	}
+
+	var adjacent string
+	if err := r.db.QueryRowContext(ctx, query, status, rank, excludeID).Scan(&adjacent); err != nil {
+		return "", fmt.Errorf("failed to get adjacent rank: %w", contextError(ctx, err))
+	}
+	return adjacent, nil
+}
+
+// Respaces the ranks of a status column evenly, moving them into the next bucket.
+// SQLite has a single writer, so the whole column is re-ranked in one transaction and
+// batchSize is not needed. Returns the number of tasks re-ranked.
+func (r *SQLiteTaskRepository) RebalanceRanks(ctx context.Context, status models.TaskStatus, batchSize int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Work out which bucket to migrate from
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT CAST(substr(rank, 1, 1) AS INTEGER) FROM tasks WHERE status = ?`, status)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rank buckets: %w", err)
+	}
+	var buckets []int
+	for rows.Next() {
+		var bucket int
+		if err := rows.Scan(&bucket); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		buckets = append(buckets, bucket)
+	}
+	rows.Close()
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	from, to, _ := models.RankRebalanceBuckets(buckets)
+	lower, upper := models.RankBucketRange(from)
+
+	ids, err := queryIDs(ctx, tx, `SELECT id FROM tasks WHERE status = ? AND rank >= ? AND rank < ? ORDER BY rank, id`,
+		status, lower, upper)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, id := range ids {
+		rank := models.FormatRank(to, models.EvenRankValue(i+1, len(ids)))
+		if _, err := tx.ExecContext(ctx, `UPDATE tasks SET rank = ? WHERE id = ?`, rank, id); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// Builds the WHERE clause for list and count queries. Custom fields are compared as
+// JSON text, which both sides get from encoding/json, so equal values match.
+func buildSQLiteTaskFilter(status string, customFields map[string]any) (string, []any, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, status)
+	}
+
+	keys := make([]string, 0, len(customFields))
+	for key := range customFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !models.IsValidCustomFieldKey(key) {
+			return "", nil, fmt.Errorf("invalid custom field key %q", key)
+		}
+		value, err := json.Marshal(customFields[key])
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode filter for custom field %q: %w", key, err)
+		}
+		conditions = append(conditions, fmt.Sprintf("custom_fields -> '$.%s' = json(?)", key))
+		args = append(args, string(value))
+	}
+
+	if len(conditions) == 0 {
+		return "", args, nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// Builds the ORDER BY expression; custom fields are extracted as SQL values, keeping
+// numbers numeric and ISO dates chronological
+func buildSQLiteTaskOrder(sortBy, sortOrder string) (string, error) {
+	key, ok := models.CustomFieldSortKey(sortBy)
+	if !ok {
+		return buildTaskOrder(sortBy, sortOrder)
+	}
+	if sortOrder != "asc" && sortOrder != "desc" {
+		return "", fmt.Errorf("invalid sort order %q", sortOrder)
+	}
+	if !models.IsValidCustomFieldKey(key) {
+		return "", fmt.Errorf("invalid custom field key %q", key)
+	}
+	return fmt.Sprintf("custom_fields ->> '$.%s' %s NULLS LAST, id %s", key, sortOrder, sortOrder), nil
+}
@@ -0,0 +1,292 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	sqlitemigrations "github.com/AashishRichhariya/task-management-api/migrations/sqlite"
+)
+
+// Behaviour every TaskRepository must share, run against each backend
+func runTaskRepositoryConformance(t *testing.T, newRepo func(t *testing.T) TaskRepository) {
+	ctx := context.Background()
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+
+		task, err := repo.GetTaskByID(ctx, 99999)
+		if err != nil || task != nil {
+			t.Errorf("Expected (nil, nil) for a missing task, got (%v, %v)", task, err)
+		}
+		if err := repo.UpdateTask(ctx, &models.Task{ID: 99999, Title: "Missing", Status: models.StatusPending, Rank: "0:i"}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows updating a missing task, got %v", err)
+		}
+		if err := repo.DeleteTask(ctx, 99999); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Expected sql.ErrNoRows deleting a missing task, got %v", err)
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		repo := newRepo(t)
+
+		// Execute
+		task := &models.Task{
+			Title:        "Round trip",
+			Description:  "Description",
+			Status:       models.StatusInProgress,
+			CustomFields: map[string]any{"points": float64(3), "team": "core"},
+		}
+		if err := repo.CreateTask(ctx, task); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		stored, err := repo.GetTaskByID(ctx, task.ID)
+
+		// Assert
+		if err != nil || stored == nil {
+			t.Fatalf("GetTaskByID failed: %v", err)
+		}
+		if stored.Title != task.Title || stored.Description != task.Description || stored.Status != task.Status || stored.Rank != task.Rank {
+			t.Errorf("Expected %+v, got %+v", task, stored)
+		}
+		if stored.CustomFields["points"] != float64(3) || stored.CustomFields["team"] != "core" {
+			t.Errorf("Expected custom fields to round trip, got %v", stored.CustomFields)
+		}
+		if stored.CreatedAt.Sub(task.CreatedAt).Abs() > time.Microsecond { // Postgres keeps microseconds
+			t.Errorf("Expected created_at %v, got %v", task.CreatedAt, stored.CreatedAt)
+		}
+
+		// Deleted ids are not handed out again
+		if err := repo.DeleteTask(ctx, task.ID); err != nil {
+			t.Fatalf("DeleteTask failed: %v", err)
+		}
+		next := &models.Task{Title: "Next", Status: models.StatusPending}
+		if err := repo.CreateTask(ctx, next); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+		if next.ID <= task.ID {
+			t.Errorf("Expected an id above %d, got %d", task.ID, next.ID)
+		}
+	})
+
+	t.Run("ListAndCount", func(t *testing.T) {
+		repo := newRepo(t)
+		ids := createConformanceTasks(t, repo)
+
+		// Pages carry the total of all matching tasks
+		tasks, total, err := repo.GetAllTasks(ctx, 2, 1, "", "id", "asc", nil)
+		if err != nil {
+			t.Fatalf("GetAllTasks failed: %v", err)
+		}
+		if total != 4 || len(tasks) != 2 || tasks[0].ID != ids["alpha"] || tasks[1].ID != ids["bravo"] {
+			t.Errorf("Expected the first two of 4 tasks, got %d of %d", len(tasks), total)
+		}
+
+		// So do pages past the end
+		tasks, total, err = repo.GetAllTasks(ctx, 2, 5, "", "id", "asc", nil)
+		if err != nil {
+			t.Fatalf("GetAllTasks failed: %v", err)
+		}
+		if total != 4 || len(tasks) != 0 {
+			t.Errorf("Expected an empty page of 4 tasks, got %d of %d", len(tasks), total)
+		}
+		tasks, total, _ = repo.GetAllTasks(ctx, 2, 5, "pending", "id", "asc", map[string]any{"team": "core"})
+		if total != 1 || len(tasks) != 0 {
+			t.Errorf("Expected an empty page of 1 filtered task, got %d of %d", len(tasks), total)
+		}
+
+		// Filters combine status and custom fields of any JSON type
+		filters := []struct {
+			status   string
+			fields   map[string]any
+			expected []string
+		}{
+			{"pending", nil, []string{"alpha", "charlie"}},
+			{"", map[string]any{"team": "core"}, []string{"alpha", "bravo"}},
+			{"", map[string]any{"points": 5}, []string{"charlie"}},
+			{"", map[string]any{"urgent": true}, []string{"bravo"}},
+			{"pending", map[string]any{"team": "core"}, []string{"alpha"}},
+			{"", map[string]any{"team": "missing"}, nil},
+		}
+		for _, filter := range filters {
+			tasks, total, err := repo.GetAllTasks(ctx, 10, 1, filter.status, "id", "asc", filter.fields)
+			if err != nil {
+				t.Fatalf("GetAllTasks failed: %v", err)
+			}
+			assertTaskOrder(t, tasks, ids, filter.expected)
+			if total != len(filter.expected) {
+				t.Errorf("Expected a total of %d for %q %v, got %d", len(filter.expected), filter.status, filter.fields, total)
+			}
+		}
+	})
+
+	t.Run("Sorting", func(t *testing.T) {
+		repo := newRepo(t)
+		ids := createConformanceTasks(t, repo)
+
+		sorts := []struct {
+			sortBy, sortOrder string
+			expected          []string
+		}{
+			{"id", "desc", []string{"delta", "charlie", "bravo", "alpha"}},
+			{"title", "asc", []string{"alpha", "bravo", "charlie", "delta"}},
+			{"created_at", "desc", []string{"delta", "charlie", "bravo", "alpha"}},
+			{"rank", "asc", []string{"delta", "bravo", "alpha", "charlie"}},
+			// Numbers sort numerically and tasks without the field come last either way
+			{"cf.points", "asc", []string{"alpha", "charlie", "delta", "bravo"}},
+			{"cf.points", "desc", []string{"delta", "charlie", "alpha", "bravo"}},
+		}
+		for _, s := range sorts {
+			tasks, _, err := repo.GetAllTasks(ctx, 10, 1, "", s.sortBy, s.sortOrder, nil)
+			if err != nil {
+				t.Fatalf("GetAllTasks by %s %s failed: %v", s.sortBy, s.sortOrder, err)
+			}
+			assertTaskOrder(t, tasks, ids, s.expected)
+		}
+
+		// Invalid sorts and filters are rejected rather than run
+		if _, _, err := repo.GetAllTasks(ctx, 10, 1, "", "title; DROP TABLE tasks", "asc", nil); err == nil {
+			t.Error("Expected an error for an invalid sort field")
+		}
+		if _, _, err := repo.GetAllTasks(ctx, 10, 1, "", "id", "sideways", nil); err == nil {
+			t.Error("Expected an error for an invalid sort order")
+		}
+		if _, _, err := repo.GetAllTasks(ctx, 10, 1, "", "id", "asc", map[string]any{"bad key": 1}); err == nil {
+			t.Error("Expected an error for an invalid custom field key")
+		}
+	})
+
+	t.Run("Columns", func(t *testing.T) {
+		repo := newRepo(t)
+
+		var created []*models.Task
+		for _, title := range []string{"First", "Second", "Third"} {
+			task := &models.Task{Title: title, Status: models.StatusPending}
+			if err := repo.CreateTask(ctx, task); err != nil {
+				t.Fatalf("CreateTask failed: %v", err)
+			}
+			created = append(created, task)
+		}
+		if !(created[0].Rank < created[1].Rank && created[1].Rank < created[2].Rank) {
+			t.Fatalf("Expected new tasks to be appended to the column, got ranks %s %s %s", created[0].Rank, created[1].Rank, created[2].Rank)
+		}
+
+		// Ranks around a task, skipping the task itself
+		last, _ := repo.GetLastRank(ctx, models.StatusPending, created[2].ID)
+		below, _ := repo.GetAdjacentRank(ctx, models.StatusPending, created[1].Rank, 0, false)
+		above, _ := repo.GetAdjacentRank(ctx, models.StatusPending, created[1].Rank, 0, true)
+		none, _ := repo.GetAdjacentRank(ctx, models.StatusPending, created[2].Rank, 0, true)
+		empty, _ := repo.GetLastRank(ctx, models.StatusClosed, 0)
+		if last != created[1].Rank || below != created[0].Rank || above != created[2].Rank || none != "" || empty != "" {
+			t.Errorf("Unexpected neighbor ranks: last %q, below %q, above %q, none %q, empty %q", last, below, above, none, empty)
+		}
+
+		// Pages continue after the cursor
+		page, count, err := repo.GetColumnTasks(ctx, models.StatusPending, nil, 2)
+		if err != nil {
+			t.Fatalf("GetColumnTasks failed: %v", err)
+		}
+		if count != 3 || len(page) != 2 || page[1].ID != created[1].ID {
+			t.Fatalf("Expected the first two of 3 tasks, got %d of %d", len(page), count)
+		}
+		page, _, err = repo.GetColumnTasks(ctx, models.StatusPending, &models.BoardCursor{Rank: page[1].Rank, ID: page[1].ID}, 2)
+		if err != nil {
+			t.Fatalf("GetColumnTasks failed: %v", err)
+		}
+		if len(page) != 1 || page[0].ID != created[2].ID {
+			t.Errorf("Expected only the third task after the cursor, got %d tasks", len(page))
+		}
+
+		// Rebalancing respaces the column into the next bucket, keeping its order
+		migrated, err := repo.RebalanceRanks(ctx, models.StatusPending, 2)
+		if err != nil {
+			t.Fatalf("RebalanceRanks failed: %v", err)
+		}
+		if migrated != 3 {
+			t.Errorf("Expected 3 tasks re-ranked, got %d", migrated)
+		}
+		page, _, _ = repo.GetColumnTasks(ctx, models.StatusPending, nil, 10)
+		for i, task := range page {
+			if task.ID != created[i].ID {
+				t.Fatalf("Expected the column order to be preserved, position %d has task %d", i, task.ID)
+			}
+			if bucket, _, _ := models.ParseRank(task.Rank); bucket != 1 {
+				t.Errorf("Expected task %d to move to bucket 1, got rank %s", task.ID, task.Rank)
+			}
+		}
+	})
+}
+
+// Creates four tasks, in order, named by their titles; the returned map holds their ids
+func createConformanceTasks(t *testing.T, repo TaskRepository) map[string]int {
+	tasks := []*models.Task{
+		{Title: "alpha", Status: models.StatusPending, Rank: "0:m", CustomFields: map[string]any{"team": "core", "points": 2}},
+		{Title: "bravo", Status: models.StatusInProgress, Rank: "0:c", CustomFields: map[string]any{"team": "core", "urgent": true}},
+		{Title: "charlie", Status: models.StatusPending, Rank: "0:t", CustomFields: map[string]any{"team": "web", "points": 5}},
+		{Title: "delta", Status: models.StatusCompleted, Rank: "0:a", CustomFields: map[string]any{"points": 10}},
+	}
+
+	ids := map[string]int{}
+	for _, task := range tasks {
+		if err := repo.CreateTask(context.Background(), task); err != nil {
+			t.Fatalf("Failed to create test task: %v", err)
+		}
+		ids[task.Title] = task.ID
+	}
+	return ids
+}
+
+func assertTaskOrder(t *testing.T, tasks []models.Task, ids map[string]int, expected []string) {
+	t.Helper()
+	if len(tasks) != len(expected) {
+		t.Errorf("Expected tasks %v, got %d tasks", expected, len(tasks))
+		return
+	}
+	for i, title := range expected {
+		if tasks[i].ID != ids[title] {
+			t.Errorf("Expected tasks %v, position %d has %q", expected, i, tasks[i].Title)
+			return
+		}
+	}
+}
+
+func TestPostgresTaskRepository_Conformance(t *testing.T) {
+	runTaskRepositoryConformance(t, func(t *testing.T) TaskRepository {
+		db := SetupTestDB(t)
+		t.Cleanup(func() {
+			CleanupTestDB(t, db)
+			db.Close()
+		})
+		return NewPostgresTaskRepository(db)
+	})
+}
+
+func TestSQLiteTaskRepository_Conformance(t *testing.T) {
+	runTaskRepositoryConformance(t, func(t *testing.T) TaskRepository {
+		db, err := database.NewSQLiteConnection(filepath.Join(t.TempDir(), "tasks.db"))
+		if err != nil {
+			t.Fatalf("Failed to open test database: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		migrator, err := database.NewSQLiteMigrator(db, sqlitemigrations.FS)
+		if err == nil {
+			_, err = migrator.Up()
+		}
+		if err != nil {
+			t.Fatalf("Failed to migrate test database: %v", err)
+		}
+		return NewSQLiteTaskRepository(db)
+	})
+}
+
+func TestMemoryTaskRepository_Conformance(t *testing.T) {
+	runTaskRepositoryConformance(t, func(t *testing.T) TaskRepository {
+		return NewMemoryTaskRepository()
+	})
+}
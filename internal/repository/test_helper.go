@@ -2,36 +2,46 @@ package repository
 
 import (
 	"database/sql"
-	"os"
 	"testing"
 
+	"github.com/AashishRichhariya/task-management-api/internal/config"
 	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/migrations"
 )
 
 func SetupTestDB(t *testing.T) *sql.DB {
-	// Temporarily override environment for tests
-	originalDBName := os.Getenv("DB_NAME")
-	os.Setenv("DB_NAME", "taskdb_test")
-	
+	// The environment's connection settings, with the test database and no waiting
+	testConfig := config.Default()
+	if err := testConfig.LoadEnv(); err != nil {
+		t.Fatalf("Invalid test database configuration: %v", err)
+	}
+	testConfig.Database.Name = "taskdb_test"
+	testConfig.Database.ConnectTimeout = 0
+
 	// Use production connection function with test DB name
-	db, err := database.NewPostgresConnection()
+	db, err := database.NewPostgresConnection(testConfig.Database)
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
 	
-	// Restore original environment
-	if originalDBName != "" {
-		os.Setenv("DB_NAME", originalDBName)
-	} else {
-		os.Unsetenv("DB_NAME")
+	// Bring the schema up to date; a no-op once the first test has done it
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err == nil {
+		_, err = migrator.Up()
+	}
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
 	}
 	
 	return db
 }
 
 func CleanupTestDB(t *testing.T, db *sql.DB) {
-	_, err := db.Exec("DELETE FROM tasks")
-	if err != nil {
-		t.Fatalf("Failed to cleanup test database: %v", err)
+	tables := []string{"digest_preferences", "task_attachments", "automation_rules", "outbox", "webhook_deliveries", "webhooks", "mentions", "notifications", "task_watchers", "users", "tasks", "custom_field_definitions"}
+	for _, table := range tables {
+		_, err := db.Exec("DELETE FROM " + table)
+		if err != nil {
+			t.Fatalf("Failed to cleanup test database: %v", err)
+		}
 	}
 }
\ No newline at end of file
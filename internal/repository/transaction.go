@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/database"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/lib/pq"
+)
+
+// SQLSTATE of a transaction aborted to keep it serializable; running it again may succeed
+const serializationFailure = "40001"
+
+// SQLSTATE of an insert or update that would duplicate a unique key
+const uniqueViolation = "23505"
+
+// Satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Repositories whose reads and writes all take part in one transaction
+type Repositories struct {
+	Tasks    TaskRepository
+	Mentions MentionRepository
+}
+
+// Runs functions as a unit of work in a database transaction.
+//
+// fn gets repositories bound to the transaction, which commits if fn returns nil and
+// rolls back otherwise. Called again with the ctx handed to fn, WithinTx joins the
+// transaction through a savepoint, so a failing inner unit only undoes its own writes.
+// A transaction that fails to serialize is retried from the start, so fn must be safe
+// to run more than once.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
+
+type TxManagerConfig struct {
+	Isolation    sql.IsolationLevel
+	MaxAttempts  int           // Including the first, for serialization failures
+	RetryBackoff time.Duration // Grows with each attempt, with jitter
+}
+
+func DefaultTxManagerConfig() TxManagerConfig {
+	return TxManagerConfig{
+		Isolation:    sql.LevelSerializable,
+		MaxAttempts:  5,
+		RetryBackoff: 10 * time.Millisecond,
+	}
+}
+
+type PostgresTxManager struct {
+	db          *sql.DB
+	config      TxManagerConfig
+	taskOptions []PostgresTaskRepositoryOption
+	taskCache   *CachedTaskRepository
+	replicas    *database.ReplicaSet
+}
+
+// Optional behaviour for PostgresTxManager
+type TxManagerOption func(*PostgresTxManager)
+
+// Builds the transaction's task repository with the same options as the standalone one
+func WithTaskRepositoryOptions(opts ...PostgresTaskRepositoryOption) TxManagerOption {
+	return func(m *PostgresTxManager) {
+		m.taskOptions = append(m.taskOptions, opts...)
+	}
+}
+
+// Records committed transactions in the request's read session, so the client's later
+// reads from replicas see them (see WithReplicas)
+func WithReadYourWrites(replicas *database.ReplicaSet) TxManagerOption {
+	return func(m *PostgresTxManager) {
+		m.replicas = replicas
+	}
+}
+
+// Drops the tasks a transaction wrote from cache once it commits. Reads within the
+// transaction always go to the database, so they see its own writes.
+func WithTaskCache(cache *CachedTaskRepository) TxManagerOption {
+	return func(m *PostgresTxManager) {
+		m.taskCache = cache
+	}
+}
+
+func NewTxManager(db *sql.DB, config TxManagerConfig, opts ...TxManagerOption) TxManager {
+	m := &PostgresTxManager{db: db, config: config}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// The transaction a ctx handed to a unit of work belongs to
+type txScope struct {
+	tx          *sql.Tx
+	repos       Repositories
+	savepoints  int
+	afterCommit []func()
+}
+
+type txScopeKey struct{}
+
+func (m *PostgresTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	if scope, ok := ctx.Value(txScopeKey{}).(*txScope); ok {
+		return scope.savepoint(ctx, fn)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.run(ctx, fn)
+		if !IsSerializationFailure(err) || attempt >= m.config.MaxAttempts {
+			return err
+		}
+
+		delay := m.config.RetryBackoff * time.Duration(attempt)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay/2 + rand.N(delay/2+1)):
+		}
+	}
+}
+
+func (m *PostgresTxManager) run(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: m.config.Isolation})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", contextError(ctx, err))
+	}
+	defer tx.Rollback()
+
+	scope := m.newScope(tx)
+	if err := fn(context.WithValue(ctx, txScopeKey{}, scope), scope.repos); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return contextError(ctx, err)
+	}
+	if m.replicas != nil {
+		if err := database.RecordWrite(ctx, m.db); err != nil {
+			log.Printf("Reads after this transaction may be stale: %v", err)
+		}
+	}
+
+	for _, f := range scope.afterCommit {
+		f()
+	}
+	return nil
+}
+
+func (m *PostgresTxManager) newScope(tx *sql.Tx) *txScope {
+	scope := &txScope{tx: tx}
+
+	taskOptions := append([]PostgresTaskRepositoryOption{boundToTx(tx)}, m.taskOptions...)
+	scope.repos = Repositories{
+		Tasks:    NewPostgresTaskRepository(m.db, taskOptions...),
+		Mentions: &PostgresMentionRepository{db: tx},
+	}
+	if m.taskCache != nil {
+		scope.repos.Tasks = &txCachedTaskRepository{TaskRepository: scope.repos.Tasks, cache: m.taskCache, scope: scope}
+	}
+	return scope
+}
+
+// Runs a nested unit of work, undoing only its writes if it fails
+func (s *txScope) savepoint(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	s.savepoints++
+	name := fmt.Sprintf("unit_%d", s.savepoints)
+
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", contextError(ctx, err))
+	}
+	if err := fn(ctx, s.repos); err != nil {
+		if _, rollbackErr := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rollbackErr != nil {
+			return fmt.Errorf("%w (and rolling back to the savepoint failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+	if _, err := s.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", contextError(ctx, err))
+	}
+	return nil
+}
+
+// Reports whether err comes from a transaction Postgres aborted to keep it serializable
+func IsSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == serializationFailure
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// Returns the name of the constraint or index a statement violated, if any
+func violatedConstraint(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+	return ""
+}
+
+// Runs fn in a transaction of its own, or as part of the one q already is
+func inTransaction(ctx context.Context, q queryer, fn func(q queryer) error) error {
+	db, ok := q.(*sql.DB)
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Task writes made in a transaction, dropping the tasks from cache once it commits
+type txCachedTaskRepository struct {
+	TaskRepository
+	cache *CachedTaskRepository
+	scope *txScope
+}
+
+func (r *txCachedTaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
+	if err := r.TaskRepository.CreateTask(ctx, task); err != nil {
+		return err
+	}
+	r.invalidateOnCommit(task.ID)
+	return nil
+}
+
+func (r *txCachedTaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
+	err := r.TaskRepository.UpdateTask(ctx, task)
+	r.invalidateOnCommit(task.ID)
+	return err
+}
+
+func (r *txCachedTaskRepository) DeleteTask(ctx context.Context, id int) error {
+	err := r.TaskRepository.DeleteTask(ctx, id)
+	r.invalidateOnCommit(id)
+	return err
+}
+
+func (r *txCachedTaskRepository) invalidateOnCommit(id int) {
+	r.scope.afterCommit = append(r.scope.afterCommit, func() {
+		r.cache.Invalidate(id)
+	})
+}
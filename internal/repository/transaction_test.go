@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/lib/pq"
+)
+
+func TestPostgresTxManager_CommitAndRollback(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	manager := NewTxManager(db, DefaultTxManagerConfig(), WithTaskRepositoryOptions(WithOutbox()))
+	tasks := NewPostgresTaskRepository(db)
+	ctx := context.Background()
+
+	// Execute - a unit that fails after writing
+	failure := errors.New("failed after writing")
+	var rolledBack models.Task
+	err := manager.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+		rolledBack = models.Task{Title: "Rolled back", Status: models.StatusPending}
+		if err := repos.Tasks.CreateTask(ctx, &rolledBack); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	// Assert
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected the unit's error, got %v", err)
+	}
+	if task, _ := tasks.GetTaskByID(ctx, rolledBack.ID); task != nil {
+		t.Errorf("Expected the task to be rolled back, got %+v", task)
+	}
+
+	// Execute - a unit that succeeds
+	var committed models.Task
+	err = manager.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+		committed = models.Task{Title: "Committed", Status: models.StatusPending}
+		if err := repos.Tasks.CreateTask(ctx, &committed); err != nil {
+			return err
+		}
+
+		// Reads within the transaction see its writes
+		task, err := repos.Tasks.GetTaskByID(ctx, committed.ID)
+		if err != nil || task == nil {
+			return fmt.Errorf("task not visible in its transaction: %v", err)
+		}
+		return nil
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("WithinTx failed: %v", err)
+	}
+	if task, _ := tasks.GetTaskByID(ctx, committed.ID); task == nil {
+		t.Error("Expected the task to be committed")
+	}
+
+	var outboxCount int
+	db.QueryRow(`SELECT COUNT(*) FROM outbox`).Scan(&outboxCount)
+	if outboxCount != 1 {
+		t.Errorf("Expected only the committed task's event in the outbox, got %d", outboxCount)
+	}
+}
+
+func TestPostgresTxManager_Savepoints(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	manager := NewTxManager(db, DefaultTxManagerConfig())
+	tasks := NewPostgresTaskRepository(db)
+	ctx := context.Background()
+
+	// Execute - the inner unit fails, the outer one carries on
+	var outer, inner models.Task
+	err := manager.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+		outer = models.Task{Title: "Outer", Status: models.StatusPending}
+		if err := repos.Tasks.CreateTask(ctx, &outer); err != nil {
+			return err
+		}
+
+		innerErr := manager.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+			inner = models.Task{Title: "Inner", Status: models.StatusPending}
+			if err := repos.Tasks.CreateTask(ctx, &inner); err != nil {
+				return err
+			}
+			invalid := models.Task{Title: "Invalid", Status: "archived"}
+			return repos.Tasks.CreateTask(ctx, &invalid) // Violates the status check, aborting the transaction
+		})
+		if innerErr == nil {
+			return errors.New("expected the inner unit to fail")
+		}
+
+		// The transaction is usable again after the savepoint rollback
+		outer.Description = "Updated after the inner failure"
+		return repos.Tasks.UpdateTask(ctx, &outer)
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("WithinTx failed: %v", err)
+	}
+	if task, _ := tasks.GetTaskByID(ctx, outer.ID); task == nil || task.Description != outer.Description {
+		t.Errorf("Expected the outer task to be committed with its update, got %+v", task)
+	}
+	if task, _ := tasks.GetTaskByID(ctx, inner.ID); task != nil {
+		t.Errorf("Expected the inner task to be rolled back, got %+v", task)
+	}
+}
+
+func TestPostgresTxManager_RetriesSerializationFailures(t *testing.T) {
+	// Setup
+	db := SetupTestDB(t)
+	defer db.Close()
+	defer CleanupTestDB(t, db)
+
+	config := DefaultTxManagerConfig()
+	config.MaxAttempts = 3
+	manager := NewTxManager(db, config)
+	tasks := NewPostgresTaskRepository(db)
+	ctx := context.Background()
+
+	// Execute - the first attempt conflicts after writing
+	attempts := 0
+	err := manager.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+		attempts++
+		task := models.Task{Title: fmt.Sprintf("Attempt %d", attempts), Status: models.StatusPending}
+		if err := repos.Tasks.CreateTask(ctx, &task); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return fmt.Errorf("update failed: %w", &pq.Error{Code: serializationFailure})
+		}
+		return nil
+	})
+
+	// Assert
+	if err != nil || attempts != 2 {
+		t.Fatalf("Expected success on the second attempt, got %v after %d attempts", err, attempts)
+	}
+	all, total, _ := tasks.GetAllTasks(ctx, 10, 1, "", "", "", nil)
+	if total != 1 || all[0].Title != "Attempt 2" {
+		t.Errorf("Expected only the second attempt's task, got %+v", all)
+	}
+
+	// Execute - every attempt conflicts
+	attempts = 0
+	err = manager.WithinTx(ctx, func(ctx context.Context, repos Repositories) error {
+		attempts++
+		return &pq.Error{Code: serializationFailure}
+	})
+
+	// Assert
+	if !IsSerializationFailure(err) || attempts != 3 {
+		t.Errorf("Expected a serialization failure after 3 attempts, got %v after %d", err, attempts)
+	}
+}
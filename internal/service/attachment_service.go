@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type AttachmentService struct {
+	taskRepo       repository.TaskRepository
+	attachmentRepo repository.AttachmentRepository
+}
+
+type AttachmentServiceInterface interface {
+	GetAttachments(ctx context.Context, taskID int) ([]models.TaskAttachment, error)
+	GetAttachment(ctx context.Context, taskID, id int) (*models.TaskAttachment, error)
+}
+
+func NewAttachmentService(taskRepo repository.TaskRepository, attachmentRepo repository.AttachmentRepository) AttachmentServiceInterface {
+	return &AttachmentService{
+		taskRepo:       taskRepo,
+		attachmentRepo: attachmentRepo,
+	}
+}
+
+func (s *AttachmentService) GetAttachments(ctx context.Context, taskID int) ([]models.TaskAttachment, error) {
+	if err := s.requireTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.GetAttachments(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (s *AttachmentService) GetAttachment(ctx context.Context, taskID, id int) (*models.TaskAttachment, error) {
+	if err := s.requireTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.attachmentRepo.GetAttachment(taskID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if attachment == nil {
+		return nil, models.NotFoundError{Resource: "attachment", ID: id}
+	}
+
+	return attachment, nil
+}
+
+func (s *AttachmentService) requireTask(ctx context.Context, taskID int) error {
+	task, err := s.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return models.TaskNotFoundError{ID: taskID}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+// Longest chain of rule-triggered changes followed from one direct change
+const automationMaxDepth = 5
+
+// Evaluates automation rules against task events and runs the actions of those that
+// match. Actions that change tasks go through TaskService, so they can trigger rules
+// in turn; a rule never fires twice for the same task within one chain, and chains
+// stop after automationMaxDepth steps.
+type AutomationEngine struct {
+	ruleRepo    repository.AutomationRuleRepository
+	webhookRepo repository.WebhookRepository
+	taskService *TaskService // Set by WithAutomation
+}
+
+// The rule firings that led to a change
+type automationChain struct {
+	depth int
+	fired map[string]bool // "<rule ID>:<task ID>"
+}
+
+// Payload of the webhook deliveries queued by fire_webhook actions
+type automationWebhookPayload struct {
+	RuleID   int              `json:"rule_id"`
+	RuleName string           `json:"rule_name"`
+	Event    models.TaskEvent `json:"event"`
+}
+
+func NewAutomationEngine(ruleRepo repository.AutomationRuleRepository, webhookRepo repository.WebhookRepository) *AutomationEngine {
+	return &AutomationEngine{
+		ruleRepo:    ruleRepo,
+		webhookRepo: webhookRepo,
+	}
+}
+
+// Enabled rules that fire for the event
+func (e *AutomationEngine) MatchingRules(event models.TaskEvent) ([]models.AutomationRule, error) {
+	rules, err := e.ruleRepo.GetEnabledRules(event.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load automation rules: %w", err)
+	}
+
+	matching := []models.AutomationRule{}
+	for _, rule := range rules {
+		if rule.Matches(event) {
+			matching = append(matching, rule)
+		}
+	}
+	return matching, nil
+}
+
+// Works out the event a change would produce and the rules it would fire, without
+// making the change or running any action. Only rules fired directly by the change
+// are listed, not those their actions would go on to trigger.
+func (e *AutomationEngine) DryRun(ctx context.Context, req models.AutomationDryRunRequest) (*models.AutomationDryRunResponse, error) {
+	tasks := e.taskService
+	var event models.TaskEvent
+
+	switch {
+	case req.TaskID == 0:
+		if req.Delete {
+			return nil, models.ValidationError{Field: "task_id", Message: "a deletion needs the task_id"}
+		}
+		if req.Title == "" {
+			return nil, models.ValidationError{Field: "title", Message: "a creation needs a title"}
+		}
+		status := req.Status
+		if status == "" {
+			status = string(models.StatusPending)
+		}
+		task, err := tasks.newTask(ctx, req.Title, req.Description, status, req.CustomFields)
+		if err != nil {
+			return nil, err
+		}
+		event = newTaskEvent(models.EventTaskCreated, *task, nil)
+
+	case req.Delete:
+		task, err := tasks.GetTaskByID(ctx, req.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		event = newTaskEvent(models.EventTaskDeleted, *task, nil)
+
+	default:
+		task, err := tasks.GetTaskByID(ctx, req.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		previous := *task
+		definitions, err := tasks.updateDefinitions(ctx, req.CustomFields)
+		if err != nil {
+			return nil, err
+		}
+		if err := tasks.applyUpdate(task, definitions, req.Title, req.Description, req.Status, req.CustomFields); err != nil {
+			return nil, err
+		}
+		event = newTaskEvent(models.ClassifyTaskChange(previous, *task), *task, &previous)
+	}
+
+	response := &models.AutomationDryRunResponse{Event: event, Matches: []models.AutomationRuleMatch{}}
+	if event.Previous != nil && len(event.Changes) == 0 {
+		return response, nil // No visible change, so no event and nothing fires
+	}
+
+	rules, err := e.MatchingRules(event)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		match := models.AutomationRuleMatch{RuleID: rule.ID, RuleName: rule.Name, Actions: []models.RuleAction{}}
+		for _, action := range rule.Actions {
+			match.Actions = append(match.Actions, action.Render(event.Task))
+		}
+		response.Matches = append(response.Matches, match)
+	}
+	return response, nil
+}
+
+func (e *AutomationEngine) run(ctx context.Context, event models.TaskEvent, chain *automationChain) {
+	rules, err := e.MatchingRules(event)
+	if err != nil {
+		log.Printf("Skipping automation for %s on task %d: %v", event.Type, event.Task.ID, err)
+		return
+	}
+
+	for _, rule := range rules {
+		next, ok := chain.extend(rule.ID, event.Task.ID)
+		if !ok {
+			log.Printf("Automation rule %d not fired on task %d: loop protection (depth %d)", rule.ID, event.Task.ID, chain.depth)
+			continue
+		}
+
+		log.Printf("Automation rule %d (%s) fired on %s of task %d", rule.ID, rule.Name, event.Type, event.Task.ID)
+		for _, action := range rule.Actions {
+			if err := e.execute(ctx, rule, action.Render(event.Task), event, next); err != nil {
+				log.Printf("Automation rule %d action %s failed on task %d: %v", rule.ID, action.Type, event.Task.ID, err)
+			}
+		}
+	}
+}
+
+func (e *AutomationEngine) execute(ctx context.Context, rule models.AutomationRule, action models.RuleAction, event models.TaskEvent, chain *automationChain) error {
+	switch action.Type {
+	case models.RuleActionSetStatus:
+		if event.Type == models.EventTaskDeleted {
+			return fmt.Errorf("the task was deleted")
+		}
+		_, err := e.taskService.updateTask(ctx, event.Task.ID, "", "", action.Status, nil, chain)
+		return err
+
+	case models.RuleActionCreateTask:
+		status := action.Status
+		if status == "" {
+			status = string(models.StatusPending)
+		}
+		_, err := e.taskService.createTask(ctx, action.Title, action.Description, status, nil, chain)
+		return err
+
+	case models.RuleActionFireWebhook:
+		payload, err := json.Marshal(automationWebhookPayload{RuleID: rule.ID, RuleName: rule.Name, Event: event})
+		if err != nil {
+			return err
+		}
+		queued, err := e.webhookRepo.EnqueueDelivery(action.WebhookID, models.AutomationWebhookEvent, payload)
+		if err != nil {
+			return err
+		}
+		if !queued {
+			return fmt.Errorf("webhook %d does not exist or is inactive", action.WebhookID)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown action type '%s'", action.Type)
+	}
+}
+
+// Returns the chain for changes made by a rule firing on a task, or false if the rule
+// already fired on it earlier in the chain or the chain is too long. A nil chain is a
+// direct change.
+func (c *automationChain) extend(ruleID, taskID int) (*automationChain, bool) {
+	key := fmt.Sprintf("%d:%d", ruleID, taskID)
+	if c == nil {
+		return &automationChain{depth: 1, fired: map[string]bool{key: true}}, true
+	}
+	if c.depth >= automationMaxDepth || c.fired[key] {
+		return nil, false
+	}
+
+	fired := maps.Clone(c.fired)
+	fired[key] = true
+	return &automationChain{depth: c.depth + 1, fired: fired}, true
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type AutomationService struct {
+	ruleRepo    repository.AutomationRuleRepository
+	webhookRepo repository.WebhookRepository
+	engine      *AutomationEngine
+}
+
+type AutomationServiceInterface interface {
+	CreateRule(name, trigger string, conditions []models.RuleCondition, actions []models.RuleAction, enabled bool) (*models.AutomationRule, error)
+	GetRuleByID(id int) (*models.AutomationRule, error)
+	GetAllRules() ([]models.AutomationRule, error)
+	DeleteRule(id int) error
+	DryRun(ctx context.Context, req models.AutomationDryRunRequest) (*models.AutomationDryRunResponse, error)
+}
+
+// The engine must be the one given to TaskService through WithAutomation
+func NewAutomationService(ruleRepo repository.AutomationRuleRepository, webhookRepo repository.WebhookRepository, engine *AutomationEngine) AutomationServiceInterface {
+	return &AutomationService{
+		ruleRepo:    ruleRepo,
+		webhookRepo: webhookRepo,
+		engine:      engine,
+	}
+}
+
+func (s *AutomationService) CreateRule(name, trigger string, conditions []models.RuleCondition, actions []models.RuleAction, enabled bool) (*models.AutomationRule, error) {
+	rule := &models.AutomationRule{
+		Name:       strings.TrimSpace(name),
+		Trigger:    models.TaskEventType(trigger),
+		Conditions: conditions,
+		Actions:    actions,
+		Enabled:    enabled,
+	}
+
+	if !rule.Trigger.IsValid() {
+		return nil, models.ValidationError{Field: "trigger", Message: fmt.Sprintf("unknown event type '%s'", trigger)}
+	}
+	for _, condition := range conditions {
+		if err := condition.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, action := range actions {
+		if err := action.Validate(); err != nil {
+			return nil, err
+		}
+		if action.Type == models.RuleActionSetStatus && rule.Trigger == models.EventTaskDeleted {
+			return nil, models.ValidationError{Field: "actions", Message: "set_status cannot act on a deleted task"}
+		}
+		if action.Type == models.RuleActionFireWebhook {
+			webhook, err := s.webhookRepo.GetWebhookByID(action.WebhookID)
+			if err != nil {
+				return nil, err
+			}
+			if webhook == nil {
+				return nil, models.ValidationError{Field: "actions", Message: fmt.Sprintf("webhook %d does not exist", action.WebhookID)}
+			}
+		}
+	}
+
+	if err := s.ruleRepo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *AutomationService) GetRuleByID(id int) (*models.AutomationRule, error) {
+	rule, err := s.ruleRepo.GetRuleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rule == nil {
+		return nil, models.NotFoundError{Resource: "automation rule", ID: id}
+	}
+
+	return rule, nil
+}
+
+func (s *AutomationService) GetAllRules() ([]models.AutomationRule, error) {
+	rules, err := s.ruleRepo.GetAllRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get automation rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *AutomationService) DeleteRule(id int) error {
+	if _, err := s.GetRuleByID(id); err != nil {
+		return err
+	}
+
+	return s.ruleRepo.DeleteRule(id)
+}
+
+func (s *AutomationService) DryRun(ctx context.Context, req models.AutomationDryRunRequest) (*models.AutomationDryRunResponse, error) {
+	return s.engine.DryRun(ctx, req)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type automationTestSetup struct {
+	taskRepo    repository.TaskRepository
+	webhookRepo *mockWebhookRepository
+	tasks       TaskServiceInterface
+	automation  AutomationServiceInterface
+}
+
+func newAutomationTestSetup() automationTestSetup {
+	fieldRepo := newMockCustomFieldRepository()
+	fieldRepo.CreateField(&models.CustomFieldDefinition{Key: "label", Name: "Label", Type: models.FieldTypeText})
+
+	ruleRepo := newMockAutomationRuleRepository()
+	webhookRepo := newMockWebhookRepository()
+	engine := NewAutomationEngine(ruleRepo, webhookRepo)
+	taskRepo := newMockTaskRepository()
+
+	return automationTestSetup{
+		taskRepo:    taskRepo,
+		webhookRepo: webhookRepo,
+		tasks:       NewTaskService(taskRepo, WithCustomFields(fieldRepo), WithAutomation(engine)),
+		automation:  NewAutomationService(ruleRepo, webhookRepo, engine),
+	}
+}
+
+// "When a task moves to completed and has label deploy, create a verification task"
+func createDeployRule(t *testing.T, automation AutomationServiceInterface) *models.AutomationRule {
+	rule, err := automation.CreateRule("Verify deploys", string(models.EventTaskStatusChanged),
+		[]models.RuleCondition{
+			{Field: "status", Operator: models.RuleOpEquals, Value: "completed"},
+			{Field: "cf.label", Operator: models.RuleOpEquals, Value: "deploy"},
+		},
+		[]models.RuleAction{{Type: models.RuleActionCreateTask, Title: "Verify {{title}}", Description: "Follow-up to task {{id}}"}},
+		true)
+	if err != nil {
+		t.Fatalf("CreateRule failed: %v", err)
+	}
+	return rule
+}
+
+func TestAutomation_CreatesFollowUpTask(t *testing.T) {
+	setup := newAutomationTestSetup()
+	createDeployRule(t, setup.automation)
+
+	deploy, _ := setup.tasks.CreateTask(context.Background(), "Ship v2", "", "in_progress", map[string]any{"label": "deploy"})
+	other, _ := setup.tasks.CreateTask(context.Background(), "Write docs", "", "in_progress", nil)
+
+	setup.tasks.UpdateTask(context.Background(), other.ID, "", "", "completed", nil)
+	if _, total, _ := setup.taskRepo.GetAllTasks(context.Background(), 100, 1, "", "id", "asc", nil); total != 2 {
+		t.Fatalf("Expected no follow-up for an unlabelled task, got %d tasks", total)
+	}
+
+	setup.tasks.UpdateTask(context.Background(), deploy.ID, "", "", "completed", nil)
+	tasks, total, _ := setup.taskRepo.GetAllTasks(context.Background(), 100, 1, "", "id", "asc", nil)
+	if total != 3 {
+		t.Fatalf("Expected a follow-up task, got %d tasks", total)
+	}
+	for _, task := range tasks {
+		if task.ID != deploy.ID && task.ID != other.ID {
+			if task.Title != "Verify Ship v2" || task.Status != models.StatusPending {
+				t.Errorf("Unexpected follow-up task: %+v", task)
+			}
+		}
+	}
+}
+
+func TestAutomation_LoopProtection(t *testing.T) {
+	setup := newAutomationTestSetup()
+
+	// Two rules that keep flipping a task between statuses
+	for _, flip := range [][2]string{{"in_progress", "completed"}, {"completed", "in_progress"}} {
+		_, err := setup.automation.CreateRule("Flip to "+flip[1], string(models.EventTaskStatusChanged),
+			[]models.RuleCondition{{Field: "status", Operator: models.RuleOpEquals, Value: flip[0]}},
+			[]models.RuleAction{{Type: models.RuleActionSetStatus, Status: flip[1]}},
+			true)
+		if err != nil {
+			t.Fatalf("CreateRule failed: %v", err)
+		}
+	}
+	// A rule that creates a task on every creation
+	setup.automation.CreateRule("Spawn", string(models.EventTaskCreated), nil,
+		[]models.RuleAction{{Type: models.RuleActionCreateTask, Title: "Spawned from {{id}}"}}, true)
+
+	task, _ := setup.tasks.CreateTask(context.Background(), "Task", "", "pending", nil)
+	setup.tasks.UpdateTask(context.Background(), task.ID, "", "", "in_progress", nil)
+
+	// Each flip rule fires once on the task before it would repeat
+	final, _ := setup.tasks.GetTaskByID(context.Background(), task.ID)
+	if final.Status != models.StatusInProgress {
+		t.Errorf("Expected the flips to stop at in_progress, got %s", final.Status)
+	}
+
+	// Spawned tasks are new tasks each time, so only the depth limit stops them
+	_, total, _ := setup.taskRepo.GetAllTasks(context.Background(), 100, 1, "", "id", "asc", nil)
+	if total != 1+automationMaxDepth {
+		t.Errorf("Expected the spawn chain to stop after %d tasks, got %d tasks", automationMaxDepth, total-1)
+	}
+}
+
+func TestAutomation_FireWebhook(t *testing.T) {
+	setup := newAutomationTestSetup()
+	webhook := &models.Webhook{URL: "http://example.com/hook", Secret: "0123456789abcdef", Active: true,
+		EventTypes: []models.TaskEventType{models.EventTaskCreated}}
+	setup.webhookRepo.CreateWebhook(webhook)
+
+	rule, err := setup.automation.CreateRule("Notify", string(models.EventTaskDeleted), nil,
+		[]models.RuleAction{{Type: models.RuleActionFireWebhook, WebhookID: webhook.ID}}, true)
+	if err != nil {
+		t.Fatalf("CreateRule failed: %v", err)
+	}
+
+	task, _ := setup.tasks.CreateTask(context.Background(), "Task", "", "pending", nil)
+	setup.tasks.DeleteTask(context.Background(), task.ID)
+
+	deliveries, _, _ := setup.webhookRepo.GetDeliveries(webhook.ID, 10, 1)
+	if len(deliveries) != 1 || deliveries[0].Event != models.AutomationWebhookEvent {
+		t.Fatalf("Expected one automation delivery, got %+v", deliveries)
+	}
+	var payload automationWebhookPayload
+	json.Unmarshal(deliveries[0].Payload, &payload)
+	if payload.RuleID != rule.ID || payload.Event.Task.ID != task.ID {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+}
+
+func TestAutomation_CreateRule_Invalid(t *testing.T) {
+	setup := newAutomationTestSetup()
+
+	cases := []struct {
+		name       string
+		trigger    string
+		conditions []models.RuleCondition
+		actions    []models.RuleAction
+	}{
+		{"unknown trigger", "task.renamed", nil, []models.RuleAction{{Type: models.RuleActionSetStatus, Status: "closed"}}},
+		{"unknown field", "task.updated", []models.RuleCondition{{Field: "owner", Operator: models.RuleOpExists}}, []models.RuleAction{{Type: models.RuleActionSetStatus, Status: "closed"}}},
+		{"missing value", "task.updated", []models.RuleCondition{{Field: "title", Operator: models.RuleOpEquals}}, []models.RuleAction{{Type: models.RuleActionSetStatus, Status: "closed"}}},
+		{"invalid status", "task.updated", nil, []models.RuleAction{{Type: models.RuleActionSetStatus, Status: "done"}}},
+		{"missing webhook", "task.updated", nil, []models.RuleAction{{Type: models.RuleActionFireWebhook, WebhookID: 99}}},
+		{"status of deleted task", "task.deleted", nil, []models.RuleAction{{Type: models.RuleActionSetStatus, Status: "closed"}}},
+	}
+
+	for _, tc := range cases {
+		_, err := setup.automation.CreateRule("Rule", tc.trigger, tc.conditions, tc.actions, true)
+		if _, ok := err.(models.ValidationError); !ok {
+			t.Errorf("%s: expected ValidationError, got %T (%v)", tc.name, err, err)
+		}
+	}
+}
+
+func TestAutomation_DryRun(t *testing.T) {
+	setup := newAutomationTestSetup()
+	rule := createDeployRule(t, setup.automation)
+	task, _ := setup.tasks.CreateTask(context.Background(), "Ship v2", "", "in_progress", map[string]any{"label": "deploy"})
+
+	result, err := setup.automation.DryRun(context.Background(), models.AutomationDryRunRequest{TaskID: task.ID, Status: "completed"})
+	if err != nil {
+		t.Fatalf("DryRun failed: %v", err)
+	}
+	if result.Event.Type != models.EventTaskStatusChanged {
+		t.Errorf("Expected a status change event, got %s", result.Event.Type)
+	}
+	if len(result.Matches) != 1 || result.Matches[0].RuleID != rule.ID {
+		t.Fatalf("Expected the deploy rule to match, got %+v", result.Matches)
+	}
+	if title := result.Matches[0].Actions[0].Title; title != "Verify Ship v2" {
+		t.Errorf("Expected rendered action title, got %q", title)
+	}
+
+	// Nothing was changed or created
+	unchanged, _ := setup.tasks.GetTaskByID(context.Background(), task.ID)
+	if unchanged.Status != models.StatusInProgress {
+		t.Errorf("Dry run changed the task status to %s", unchanged.Status)
+	}
+	if _, total, _ := setup.taskRepo.GetAllTasks(context.Background(), 100, 1, "", "id", "asc", nil); total != 1 {
+		t.Errorf("Dry run created tasks, %d tasks exist", total)
+	}
+
+	// A change that triggers nothing
+	result, _ = setup.automation.DryRun(context.Background(), models.AutomationDryRunRequest{TaskID: task.ID, Title: "Ship v3"})
+	if len(result.Matches) != 0 {
+		t.Errorf("Expected no matches for a rename, got %+v", result.Matches)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type BoardService struct {
+	taskRepo  repository.TaskRepository
+	boardRepo repository.BoardRepository
+}
+
+type BoardServiceInterface interface {
+	GetBoard(ctx context.Context, limit int, status, cursor string) (*models.Board, error)
+	UpdateColumn(status string, wipLimit *int) (*models.BoardColumnSettings, error)
+}
+
+func NewBoardService(taskRepo repository.TaskRepository, boardRepo repository.BoardRepository) BoardServiceInterface {
+	return &BoardService{
+		taskRepo:  taskRepo,
+		boardRepo: boardRepo,
+	}
+}
+
+// Builds the board in workflow order. With a status only that column is returned,
+// and a cursor continues it from where a previous page ended.
+func (s *BoardService) GetBoard(ctx context.Context, limit int, status, cursor string) (*models.Board, error) {
+	if cursor != "" && status == "" {
+		return nil, models.ValidationError{Field: "cursor", Message: "a cursor continues a single column and requires status"}
+	}
+
+	after, err := decodeBoardCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.boardRepo.GetColumnSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board columns: %w", err)
+	}
+	wipLimits := make(map[models.TaskStatus]*int, len(settings))
+	for _, column := range settings {
+		wipLimits[column.Status] = column.WIPLimit
+	}
+
+	board := &models.Board{Columns: []models.BoardColumn{}}
+	for _, columnStatus := range models.WorkflowStatuses {
+		if status != "" && models.TaskStatus(status) != columnStatus {
+			continue
+		}
+
+		// Fetch one extra task to know whether another page exists
+		tasks, count, err := s.taskRepo.GetColumnTasks(ctx, columnStatus, after, limit+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get column '%s': %w", columnStatus, err)
+		}
+
+		column := models.BoardColumn{
+			Status:   columnStatus,
+			Count:    count,
+			WIPLimit: wipLimits[columnStatus],
+			Tasks:    tasks,
+		}
+		column.WIPExceeded = column.WIPLimit != nil && count > *column.WIPLimit
+
+		if len(tasks) > limit {
+			column.Tasks = tasks[:limit]
+			column.HasMore = true
+			last := column.Tasks[limit-1]
+			column.NextCursor = encodeBoardCursor(models.BoardCursor{Rank: last.Rank, ID: last.ID})
+		}
+
+		board.Columns = append(board.Columns, column)
+	}
+
+	return board, nil
+}
+
+func (s *BoardService) UpdateColumn(status string, wipLimit *int) (*models.BoardColumnSettings, error) {
+	columnStatus := models.TaskStatus(status)
+	if !columnStatus.IsValid() {
+		return nil, models.ValidationError{Field: "status", Message: fmt.Sprintf("unknown column '%s'", status)}
+	}
+	if wipLimit != nil && *wipLimit < 1 {
+		return nil, models.ValidationError{Field: "wip_limit", Message: "must be at least 1"}
+	}
+
+	settings := &models.BoardColumnSettings{Status: columnStatus, WIPLimit: wipLimit}
+	if err := s.boardRepo.UpdateColumnSettings(settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func encodeBoardCursor(cursor models.BoardCursor) string {
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeBoardCursor(cursor string) (*models.BoardCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	invalid := models.ValidationError{Field: "cursor", Message: "malformed cursor"}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var decoded models.BoardCursor
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, invalid
+	}
+	if _, _, err := models.ParseRank(decoded.Rank); err != nil || decoded.ID < 1 {
+		return nil, invalid
+	}
+
+	return &decoded, nil
+}
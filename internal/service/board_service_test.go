@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestBoardService_GetBoard(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	taskService := NewTaskService(taskRepo)
+	boardService := NewBoardService(taskRepo, newMockBoardRepository())
+
+	taskService.CreateTask(context.Background(), "Done", "", "completed", nil)
+	taskService.CreateTask(context.Background(), "Todo 1", "", "pending", nil)
+	taskService.CreateTask(context.Background(), "Todo 2", "", "pending", nil)
+
+	board, err := boardService.GetBoard(context.Background(), 10, "", "")
+	if err != nil {
+		t.Fatalf("GetBoard failed: %v", err)
+	}
+
+	// Columns follow the workflow order, including empty ones
+	if len(board.Columns) != len(models.WorkflowStatuses) {
+		t.Fatalf("Expected %d columns, got %d", len(models.WorkflowStatuses), len(board.Columns))
+	}
+	for i, column := range board.Columns {
+		if column.Status != models.WorkflowStatuses[i] {
+			t.Errorf("Expected column %d to be %s, got %s", i, models.WorkflowStatuses[i], column.Status)
+		}
+	}
+
+	pending := board.Columns[0]
+	if pending.Count != 2 || len(pending.Tasks) != 2 || pending.Tasks[0].Title != "Todo 1" {
+		t.Errorf("Unexpected pending column: %+v", pending)
+	}
+
+	if board.Columns[1].Count != 0 || board.Columns[2].Count != 1 {
+		t.Errorf("Unexpected column counts: %d, %d", board.Columns[1].Count, board.Columns[2].Count)
+	}
+}
+
+func TestBoardService_GetBoard_ColumnPagination(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	taskService := NewTaskService(taskRepo)
+	boardService := NewBoardService(taskRepo, newMockBoardRepository())
+
+	for _, title := range []string{"A", "B", "C"} {
+		taskService.CreateTask(context.Background(), title, "", "pending", nil)
+	}
+
+	board, err := boardService.GetBoard(context.Background(), 2, "pending", "")
+	if err != nil {
+		t.Fatalf("GetBoard failed: %v", err)
+	}
+
+	column := board.Columns[0]
+	if len(board.Columns) != 1 || !column.HasMore || column.NextCursor == "" {
+		t.Fatalf("Expected a single column with another page, got %+v", board.Columns)
+	}
+
+	cursor := column.NextCursor
+	board, err = boardService.GetBoard(context.Background(), 2, "pending", cursor)
+	if err != nil {
+		t.Fatalf("GetBoard with cursor failed: %v", err)
+	}
+
+	column = board.Columns[0]
+	if len(column.Tasks) != 1 || column.Tasks[0].Title != "C" || column.HasMore {
+		t.Errorf("Expected only task C on the last page, got %+v", column)
+	}
+
+	// A cursor without status and a malformed cursor are rejected
+	if _, err := boardService.GetBoard(context.Background(), 2, "", cursor); err == nil {
+		t.Error("Expected error for cursor without status")
+	}
+	if _, err := boardService.GetBoard(context.Background(), 2, "pending", "not-a-cursor"); err == nil {
+		t.Error("Expected error for malformed cursor")
+	}
+}
+
+func TestBoardService_WIPLimit(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	taskService := NewTaskService(taskRepo)
+	boardService := NewBoardService(taskRepo, newMockBoardRepository())
+
+	limit := 1
+	if _, err := boardService.UpdateColumn("in_progress", &limit); err != nil {
+		t.Fatalf("UpdateColumn failed: %v", err)
+	}
+
+	taskService.CreateTask(context.Background(), "One", "", "in_progress", nil)
+	board, _ := boardService.GetBoard(context.Background(), 10, "in_progress", "")
+	if board.Columns[0].WIPExceeded {
+		t.Error("Expected column at its limit not to be flagged")
+	}
+
+	taskService.CreateTask(context.Background(), "Two", "", "in_progress", nil)
+	board, _ = boardService.GetBoard(context.Background(), 10, "in_progress", "")
+	if !board.Columns[0].WIPExceeded {
+		t.Error("Expected column over its limit to be flagged")
+	}
+}
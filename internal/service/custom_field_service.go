@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+const maxCustomFieldTextLength = 1000
+
+type CustomFieldService struct {
+	fieldRepo repository.CustomFieldRepository
+}
+
+type CustomFieldServiceInterface interface {
+	CreateField(key, name, fieldType string, options []string, required bool) (*models.CustomFieldDefinition, error)
+	GetFieldByID(id int) (*models.CustomFieldDefinition, error)
+	GetAllFields(ctx context.Context) ([]models.CustomFieldDefinition, error)
+	DeleteField(id int) error
+}
+
+func NewCustomFieldService(fieldRepo repository.CustomFieldRepository) CustomFieldServiceInterface {
+	return &CustomFieldService{
+		fieldRepo: fieldRepo,
+	}
+}
+
+func (s *CustomFieldService) CreateField(key, name, fieldType string, options []string, required bool) (*models.CustomFieldDefinition, error) {
+	field := &models.CustomFieldDefinition{
+		Key:      strings.TrimSpace(key),
+		Name:     strings.TrimSpace(name),
+		Type:     models.CustomFieldType(fieldType),
+		Required: required,
+	}
+
+	if !models.IsValidNewCustomFieldKey(field.Key) {
+		return nil, models.ValidationError{
+			Field: "key",
+			Message: fmt.Sprintf("must start with a lowercase letter, contain only lowercase letters, digits and underscores, and be at most %d characters",
+				models.MaxCustomFieldKeyLength),
+		}
+	}
+	if !field.Type.IsValid() {
+		return nil, models.ValidationError{Field: "type", Message: fmt.Sprintf("unknown field type %q", fieldType)}
+	}
+
+	// Options only make sense for enums, and enums need at least one
+	if field.Type == models.FieldTypeEnum {
+		seen := make(map[string]bool)
+		for _, option := range options {
+			option = strings.TrimSpace(option)
+			if option == "" || seen[option] {
+				continue
+			}
+			seen[option] = true
+			field.Options = append(field.Options, option)
+		}
+		if len(field.Options) == 0 {
+			return nil, models.ValidationError{Field: "options", Message: "enum fields need at least one option"}
+		}
+	} else if len(options) > 0 {
+		return nil, models.ValidationError{Field: "options", Message: "options are only allowed for enum fields"}
+	}
+
+	existing, err := s.fieldRepo.GetFieldByKey(field.Key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, models.BusinessError{Message: fmt.Sprintf("custom field with key '%s' already exists", field.Key)}
+	}
+
+	if err := s.fieldRepo.CreateField(field); err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+func (s *CustomFieldService) GetFieldByID(id int) (*models.CustomFieldDefinition, error) {
+	field, err := s.fieldRepo.GetFieldByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if field == nil {
+		return nil, models.NotFoundError{Resource: "custom field", ID: id}
+	}
+
+	return field, nil
+}
+
+func (s *CustomFieldService) GetAllFields(ctx context.Context) ([]models.CustomFieldDefinition, error) {
+	fields, err := s.fieldRepo.GetAllFields(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	return fields, nil
+}
+
+func (s *CustomFieldService) DeleteField(id int) error {
+	if _, err := s.GetFieldByID(id); err != nil {
+		return err
+	}
+
+	return s.fieldRepo.DeleteField(id)
+}
+
+// Loads definitions keyed by field key
+func loadCustomFieldDefinitions(ctx context.Context, fieldRepo repository.CustomFieldRepository) (map[string]models.CustomFieldDefinition, error) {
+	definitions := make(map[string]models.CustomFieldDefinition)
+	if fieldRepo == nil {
+		return definitions, nil
+	}
+
+	fields, err := fieldRepo.GetAllFields(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load custom fields: %w", err)
+	}
+	for _, field := range fields {
+		definitions[field.Key] = field
+	}
+	return definitions, nil
+}
+
+// Applies changes to current custom field values. A nil change removes the field.
+// Required fields must be present on create and can never be removed; tasks that
+// predate a required field are not forced to set it on unrelated updates.
+func applyCustomFieldValues(definitions map[string]models.CustomFieldDefinition, current, changes map[string]any, creating bool) (map[string]any, error) {
+	values := make(map[string]any, len(current)+len(changes))
+	for key, value := range current {
+		values[key] = value
+	}
+
+	for key, raw := range changes {
+		definition, ok := definitions[key]
+		if !ok {
+			return nil, models.ValidationError{Field: "custom_fields." + key, Message: "unknown custom field"}
+		}
+		if raw == nil {
+			if definition.Required {
+				return nil, models.ValidationError{Field: "custom_fields." + key, Message: "is required and cannot be removed"}
+			}
+			delete(values, key)
+			continue
+		}
+		value, err := normalizeCustomFieldValue(definition, raw)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+
+	if creating {
+		for key, definition := range definitions {
+			if _, ok := values[key]; definition.Required && !ok {
+				return nil, models.ValidationError{Field: "custom_fields." + key, Message: "is required"}
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// Validates a JSON-decoded value against its definition and returns its canonical form
+func normalizeCustomFieldValue(definition models.CustomFieldDefinition, raw any) (any, error) {
+	invalid := func(message string) error {
+		return models.ValidationError{Field: "custom_fields." + definition.Key, Message: message}
+	}
+
+	switch definition.Type {
+	case models.FieldTypeText:
+		value, ok := raw.(string)
+		if !ok {
+			return nil, invalid("must be a string")
+		}
+		if len(value) > maxCustomFieldTextLength {
+			return nil, invalid(fmt.Sprintf("must be at most %d characters", maxCustomFieldTextLength))
+		}
+		return value, nil
+
+	case models.FieldTypeNumber:
+		var value float64
+		switch v := raw.(type) {
+		case float64:
+			value = v
+		case int:
+			value = float64(v)
+		case int64:
+			value = float64(v)
+		default:
+			return nil, invalid("must be a number")
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, invalid("must be a finite number")
+		}
+		return value, nil
+
+	case models.FieldTypeDate:
+		value, ok := raw.(string)
+		if !ok {
+			return nil, invalid("must be a date in YYYY-MM-DD format")
+		}
+		date, err := time.Parse(models.CustomFieldDateLayout, value)
+		if err != nil {
+			return nil, invalid("must be a date in YYYY-MM-DD format")
+		}
+		return date.Format(models.CustomFieldDateLayout), nil
+
+	case models.FieldTypeEnum:
+		value, ok := raw.(string)
+		if !ok || !definition.HasOption(value) {
+			return nil, invalid(fmt.Sprintf("must be one of %s", strings.Join(definition.Options, ", ")))
+		}
+		return value, nil
+
+	case models.FieldTypeBoolean:
+		value, ok := raw.(bool)
+		if !ok {
+			return nil, invalid("must be a boolean")
+		}
+		return value, nil
+	}
+
+	return nil, invalid(fmt.Sprintf("unsupported field type %q", definition.Type))
+}
+
+// Converts a query string filter into a typed value comparable with stored values
+func parseCustomFieldFilter(definition models.CustomFieldDefinition, raw string) (any, error) {
+	switch definition.Type {
+	case models.FieldTypeNumber:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, models.ValidationError{Field: models.CustomFieldQueryPrefix + definition.Key, Message: "must be a number"}
+		}
+		return normalizeCustomFieldValue(definition, value)
+	case models.FieldTypeBoolean:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, models.ValidationError{Field: models.CustomFieldQueryPrefix + definition.Key, Message: "must be true or false"}
+		}
+		return value, nil
+	default:
+		return normalizeCustomFieldValue(definition, raw)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestCustomFieldService_CreateField(t *testing.T) {
+	fieldService := NewCustomFieldService(newMockCustomFieldRepository())
+
+	field, err := fieldService.CreateField("severity", "Severity", "enum", []string{"low", "high", "low"}, false)
+	if err != nil {
+		t.Fatalf("CreateField failed: %v", err)
+	}
+
+	if field.ID == 0 {
+		t.Error("Expected field ID to be set")
+	}
+
+	if len(field.Options) != 2 {
+		t.Errorf("Expected duplicate options to be removed, got %v", field.Options)
+	}
+}
+
+func TestCustomFieldService_CreateField_Invalid(t *testing.T) {
+	fieldService := NewCustomFieldService(newMockCustomFieldRepository())
+
+	cases := []struct {
+		name      string
+		key       string
+		fieldType string
+		options   []string
+	}{
+		{"invalid key", "Severity Level", "text", nil},
+		// Index names of longer keys would be truncated by Postgres and could collide
+		{"key too long", "k" + strings.Repeat("_", models.MaxCustomFieldKeyLength), "text", nil},
+		{"enum without options", "severity", "enum", nil},
+		{"options on non-enum", "customer", "text", []string{"acme"}},
+	}
+
+	for _, tc := range cases {
+		_, err := fieldService.CreateField(tc.key, "Name", tc.fieldType, tc.options, false)
+		if _, ok := err.(models.ValidationError); !ok {
+			t.Errorf("%s: expected ValidationError, got %T", tc.name, err)
+		}
+	}
+}
+
+func TestCustomFieldService_CreateField_DuplicateKey(t *testing.T) {
+	fieldService := NewCustomFieldService(newMockCustomFieldRepository())
+
+	fieldService.CreateField("customer", "Customer", "text", nil, false)
+	_, err := fieldService.CreateField("customer", "Customer", "text", nil, false)
+
+	if _, ok := err.(models.BusinessError); !ok {
+		t.Errorf("Expected BusinessError, got %T", err)
+	}
+}
+
+func TestTaskService_CreateTask_CustomFields(t *testing.T) {
+	fieldRepo := newMockCustomFieldRepository()
+	fieldService := NewCustomFieldService(fieldRepo)
+	service := NewTaskService(newMockTaskRepository(), WithCustomFields(fieldRepo))
+
+	fieldService.CreateField("severity", "Severity", "enum", []string{"low", "high"}, true)
+	fieldService.CreateField("estimate", "Estimate", "number", nil, false)
+	fieldService.CreateField("due", "Due", "date", nil, false)
+
+	task, err := service.CreateTask(context.Background(), "Task", "", "pending", map[string]any{
+		"severity": "high",
+		"estimate": float64(3),
+		"due":      "2025-01-31",
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	if task.CustomFields["severity"] != "high" {
+		t.Errorf("Expected severity 'high', got %v", task.CustomFields["severity"])
+	}
+
+	// Missing required field
+	_, err = service.CreateTask(context.Background(), "Task", "", "pending", map[string]any{"estimate": float64(1)})
+	if _, ok := err.(models.ValidationError); !ok {
+		t.Errorf("Expected ValidationError for missing required field, got %T", err)
+	}
+
+	// Invalid values
+	invalid := []map[string]any{
+		{"severity": "critical"},
+		{"severity": "low", "estimate": "three"},
+		{"severity": "low", "due": "31/01/2025"},
+		{"severity": "low", "unknown": "value"},
+	}
+	for _, fields := range invalid {
+		_, err := service.CreateTask(context.Background(), "Task", "", "pending", fields)
+		if _, ok := err.(models.ValidationError); !ok {
+			t.Errorf("Expected ValidationError for %v, got %T", fields, err)
+		}
+	}
+}
+
+func TestTaskService_UpdateTask_CustomFields(t *testing.T) {
+	fieldRepo := newMockCustomFieldRepository()
+	fieldService := NewCustomFieldService(fieldRepo)
+	service := NewTaskService(newMockTaskRepository(), WithCustomFields(fieldRepo))
+
+	fieldService.CreateField("customer", "Customer", "text", nil, false)
+	fieldService.CreateField("billable", "Billable", "boolean", nil, false)
+
+	task, _ := service.CreateTask(context.Background(), "Task", "", "pending", map[string]any{"customer": "Acme", "billable": true})
+
+	// Null removes a value, other values are merged
+	updated, err := service.UpdateTask(context.Background(), task.ID, "", "", "", map[string]any{"customer": nil, "billable": false})
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+
+	if _, ok := updated.CustomFields["customer"]; ok {
+		t.Error("Expected customer to be removed")
+	}
+
+	if updated.CustomFields["billable"] != false {
+		t.Errorf("Expected billable false, got %v", updated.CustomFields["billable"])
+	}
+}
+
+func TestTaskService_GetAllTasks_CustomFieldFilter(t *testing.T) {
+	fieldRepo := newMockCustomFieldRepository()
+	fieldService := NewCustomFieldService(fieldRepo)
+	service := NewTaskService(newMockTaskRepository(), WithCustomFields(fieldRepo))
+
+	fieldService.CreateField("estimate", "Estimate", "number", nil, false)
+
+	service.CreateTask(context.Background(), "Small", "", "pending", map[string]any{"estimate": float64(1)})
+	service.CreateTask(context.Background(), "Large", "", "pending", map[string]any{"estimate": float64(8)})
+
+	response, err := service.GetAllTasks(context.Background(), 1, 10, "", "cf.estimate", "asc", map[string]string{"estimate": "8"})
+	if err != nil {
+		t.Fatalf("GetAllTasks failed: %v", err)
+	}
+
+	if len(response.Tasks) != 1 || response.Tasks[0].Title != "Large" {
+		t.Errorf("Expected only 'Large' task, got %v", response.Tasks)
+	}
+
+	// Unknown sort field and malformed filter value
+	if _, err := service.GetAllTasks(context.Background(), 1, 10, "", "cf.missing", "asc", nil); err == nil {
+		t.Error("Expected error for unknown sort field")
+	}
+	if _, err := service.GetAllTasks(context.Background(), 1, 10, "", "", "", map[string]string{"estimate": "many"}); err == nil {
+		t.Error("Expected error for non-numeric filter")
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"log"
+	"net/mail"
+	"sync"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/email"
+	"github.com/AashishRichhariya/task-management-api/internal/health"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type DigestSchedulerConfig struct {
+	PollInterval time.Duration // How often due digests are looked for
+	BatchSize    int           // Digests claimed per poll
+	Lease        time.Duration // How long a claimed digest is left to one instance before it is retried
+	MaxTasks     int           // Per section of a digest
+	Schedule     models.DigestSchedule
+	From         mail.Address
+	DueField     string // Key of a date custom field holding due dates; no due section when empty
+}
+
+func DefaultDigestSchedulerConfig() DigestSchedulerConfig {
+	return DigestSchedulerConfig{
+		PollInterval: time.Minute,
+		BatchSize:    20,
+		Lease:        10 * time.Minute,
+		MaxTasks:     50,
+		Schedule:     models.DigestSchedule{Hour: 8, Weekday: time.Monday},
+		From:         mail.Address{Name: "Task Management", Address: "tasks@localhost"},
+	}
+}
+
+// DigestScheduler builds and sends the email digests that are due in the background.
+// Any number of instances can run against the same preferences; each digest is
+// claimed by one of them.
+type DigestScheduler struct {
+	digestRepo repository.DigestRepository
+	sender     email.Sender
+	config     DigestSchedulerConfig
+	now        func() time.Time
+	done       chan struct{}
+	wg         sync.WaitGroup
+	heartbeat  health.Heartbeat
+}
+
+func NewDigestScheduler(digestRepo repository.DigestRepository, sender email.Sender, config DigestSchedulerConfig) *DigestScheduler {
+	return &DigestScheduler{
+		digestRepo: digestRepo,
+		sender:     sender,
+		config:     config,
+		now:        time.Now,
+		done:       make(chan struct{}),
+	}
+}
+
+func (s *DigestScheduler) Start() {
+	s.heartbeat.Beat()
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stops polling after the batch currently being sent, if any, is finished
+func (s *DigestScheduler) Stop() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+// Beats before every batch; see health.Heartbeat
+func (s *DigestScheduler) Heartbeat() *health.Heartbeat {
+	return &s.heartbeat
+}
+
+// Claims and sends one batch of due digests, returning how many were claimed
+func (s *DigestScheduler) SendDue() (int, error) {
+	now := s.now()
+	jobs, err := s.digestRepo.ClaimDueDigests(now, s.config.BatchSize, now.Add(s.config.Lease))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		if err := s.send(job, now); err != nil {
+			// Left claimed, so it is tried again once the lease runs out
+			log.Printf("Failed to send %s digest to user %d: %v", job.Preferences.Frequency, job.Recipient.ID, err)
+		}
+	}
+
+	return len(jobs), nil
+}
+
+func (s *DigestScheduler) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			// Keep going while full batches come back so a backlog drains quickly
+			for {
+				s.heartbeat.Beat()
+				claimed, err := s.SendDue()
+				if err != nil {
+					log.Printf("Digest scheduling failed: %v", err)
+				}
+				if err != nil || claimed < s.config.BatchSize {
+					break
+				}
+				select {
+				case <-s.done:
+					return
+				default:
+				}
+			}
+		}
+	}
+}
+
+// Builds, sends and records one digest. Empty digests are not sent but still
+// move the schedule on.
+func (s *DigestScheduler) send(job models.DigestJob, now time.Time) error {
+	digest, err := s.buildDigest(job, now)
+	if err != nil {
+		return err
+	}
+
+	if !digest.IsEmpty() {
+		msg, err := email.RenderDigest(s.config.From, *digest)
+		if err != nil {
+			return err
+		}
+		if err := s.sender.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	next := s.config.Schedule.Next(job.Preferences.Frequency, now)
+	return s.digestRepo.MarkDigestSent(job.Recipient.ID, now, next)
+}
+
+// Collects what changed since the recipient's last digest, or over one period for
+// their first, and what falls due before their next one
+func (s *DigestScheduler) buildDigest(job models.DigestJob, now time.Time) (*models.Digest, error) {
+	preferences := job.Preferences
+	since := now.Add(-preferences.Frequency.Period())
+	if preferences.LastSentAt != nil {
+		since = *preferences.LastSentAt
+	}
+
+	changed, err := s.digestRepo.GetChangedTasks(preferences, since, now, s.config.MaxTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &models.Digest{
+		Recipient: job.Recipient,
+		Frequency: preferences.Frequency,
+		Since:     since,
+		Until:     now,
+		Changed:   changed,
+		Due:       []models.Task{},
+		DueField:  s.config.DueField,
+	}
+
+	if s.config.DueField != "" {
+		dueBy := s.config.Schedule.Next(preferences.Frequency, now)
+		if digest.Due, err = s.digestRepo.GetDueTasks(preferences, s.config.DueField, dueBy, s.config.MaxTasks); err != nil {
+			return nil, err
+		}
+	}
+
+	return digest, nil
+}
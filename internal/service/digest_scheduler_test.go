@@ -0,0 +1,149 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/email"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestDigestSchedule_Next(t *testing.T) {
+	schedule := models.DigestSchedule{Hour: 8, Weekday: time.Monday}
+	// A Wednesday
+	wednesday := time.Date(2026, 3, 11, 7, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		frequency models.DigestFrequency
+		after     time.Time
+		expected  time.Time
+	}{
+		{"daily, before the hour", models.DigestDaily, wednesday, time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)},
+		{"daily, at the hour", models.DigestDaily, wednesday.Add(30 * time.Minute), time.Date(2026, 3, 12, 8, 0, 0, 0, time.UTC)},
+		{"weekly", models.DigestWeekly, wednesday, time.Date(2026, 3, 16, 8, 0, 0, 0, time.UTC)},
+		{"weekly, on the day before the hour", models.DigestWeekly, time.Date(2026, 3, 16, 6, 0, 0, 0, time.UTC), time.Date(2026, 3, 16, 8, 0, 0, 0, time.UTC)},
+		{"weekly, on the day after the hour", models.DigestWeekly, time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC), time.Date(2026, 3, 23, 8, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if next := schedule.Next(tt.frequency, tt.after); !next.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestDigestService_UpdatePreferences(t *testing.T) {
+	repo := newMockDigestRepository()
+	service := NewDigestService(repo, models.DigestSchedule{Hour: 8, Weekday: time.Monday})
+
+	preferences, err := service.GetPreferences(1)
+	if err != nil || preferences.Frequency != models.DigestOff {
+		t.Fatalf("Expected digests off by default, got %+v (%v)", preferences, err)
+	}
+
+	preferences, err = service.UpdatePreferences(1, models.DigestDaily, []models.TaskStatus{models.StatusPending}, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if preferences.NextDigestAt == nil || preferences.NextDigestAt.Hour() != 8 {
+		t.Errorf("Expected the next digest at 08:00, got %v", preferences.NextDigestAt)
+	}
+	scheduled := *preferences.NextDigestAt
+
+	// Changing only the filters keeps the digest already scheduled
+	preferences, _ = service.UpdatePreferences(1, models.DigestDaily, nil, false)
+	if !preferences.NextDigestAt.Equal(scheduled) || len(preferences.Statuses) != 0 {
+		t.Errorf("Expected the schedule kept and statuses cleared, got %+v", preferences)
+	}
+
+	preferences, _ = service.UpdatePreferences(1, models.DigestOff, nil, false)
+	if preferences.NextDigestAt != nil {
+		t.Errorf("Expected no digest scheduled when off, got %v", preferences.NextDigestAt)
+	}
+
+	if _, err := service.UpdatePreferences(1, models.DigestDaily, []models.TaskStatus{"done"}, false); err == nil {
+		t.Error("Expected an unknown status to be rejected")
+	}
+}
+
+func TestDigestScheduler_SendDue(t *testing.T) {
+	now := time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	lastSent := now.Add(-24 * time.Hour)
+
+	repo := newMockDigestRepository()
+	repo.users[1] = models.User{ID: 1, Username: "jane", Email: "jane@example.com"}
+	repo.users[2] = models.User{ID: 2, Username: "bob", Email: "bob@example.com"}
+	repo.users[3] = models.User{ID: 3, Username: "ann", Email: "ann@example.com"}
+	repo.preferences[1] = &models.DigestPreferences{UserID: 1, Frequency: models.DigestDaily, LastSentAt: &lastSent, NextDigestAt: &due,
+		Statuses: []models.TaskStatus{models.StatusInProgress}}
+	// Nothing in bob's statuses changed: no email, but the schedule moves on
+	repo.preferences[2] = &models.DigestPreferences{UserID: 2, Frequency: models.DigestDaily, LastSentAt: &lastSent, NextDigestAt: &due,
+		Statuses: []models.TaskStatus{models.StatusClosed}}
+	// Not due yet
+	later := now.Add(time.Hour)
+	repo.preferences[3] = &models.DigestPreferences{UserID: 3, Frequency: models.DigestWeekly, NextDigestAt: &later}
+	repo.tasks = []models.Task{
+		{ID: 1, Title: "Changed", Status: models.StatusInProgress, UpdatedAt: now.Add(-time.Hour)},
+		{ID: 2, Title: "Changed before the last digest", Status: models.StatusInProgress, UpdatedAt: now.Add(-48 * time.Hour)},
+		{ID: 3, Title: "Due tomorrow", Status: models.StatusInProgress, UpdatedAt: now.Add(-72 * time.Hour),
+			CustomFields: map[string]any{"due": "2026-03-12"}},
+		{ID: 4, Title: "Due next week", Status: models.StatusInProgress, UpdatedAt: now.Add(-72 * time.Hour),
+			CustomFields: map[string]any{"due": "2026-03-18"}},
+		{ID: 5, Title: "Done and due", Status: models.StatusCompleted, UpdatedAt: now.Add(-72 * time.Hour),
+			CustomFields: map[string]any{"due": "2026-03-10"}},
+	}
+
+	sender, err := email.NewCaptureSender(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create capture sender: %v", err)
+	}
+	config := DefaultDigestSchedulerConfig()
+	config.DueField = "due"
+	scheduler := NewDigestScheduler(repo, sender, config)
+	scheduler.now = func() time.Time { return now }
+
+	claimed, err := scheduler.SendDue()
+	if err != nil || claimed != 2 {
+		t.Fatalf("Expected 2 digests claimed, got %d (%v)", claimed, err)
+	}
+
+	paths, _ := sender.Messages()
+	if len(paths) != 1 {
+		t.Fatalf("Expected one email, got %d", len(paths))
+	}
+	file, _ := os.Open(paths[0])
+	defer file.Close()
+	msg, err := email.ParseMessage(file)
+	if err != nil {
+		t.Fatalf("Failed to parse captured email: %v", err)
+	}
+	if msg.Subject != "Your daily task digest: 1 changed, 1 due" {
+		t.Errorf("Unexpected subject %q", msg.Subject)
+	}
+	for _, expected := range []string{"#1 [in_progress] Changed", "#3 [in_progress] Due tomorrow (due 2026-03-12)"} {
+		if !strings.Contains(msg.Text, expected) {
+			t.Errorf("Expected the digest to contain %q, got:\n%s", expected, msg.Text)
+		}
+	}
+	for _, unexpected := range []string{"Changed before the last digest", "Due next week", "Done and due"} {
+		if strings.Contains(msg.Text, unexpected) {
+			t.Errorf("Expected the digest not to contain %q", unexpected)
+		}
+	}
+
+	tomorrow := time.Date(2026, 3, 12, 8, 0, 0, 0, time.UTC)
+	for _, userID := range []int{1, 2} {
+		if !repo.preferences[userID].NextDigestAt.Equal(tomorrow) || !repo.sent[userID].Equal(now) {
+			t.Errorf("Expected user %d's digest recorded and rescheduled, got %+v", userID, repo.preferences[userID])
+		}
+	}
+	if _, sent := repo.sent[3]; sent {
+		t.Error("Expected no digest for a user who is not due")
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type DigestService struct {
+	digestRepo repository.DigestRepository
+	schedule   models.DigestSchedule
+}
+
+type DigestServiceInterface interface {
+	GetPreferences(userID int) (*models.DigestPreferences, error)
+	UpdatePreferences(userID int, frequency models.DigestFrequency, statuses []models.TaskStatus, watchedOnly bool) (*models.DigestPreferences, error)
+}
+
+func NewDigestService(digestRepo repository.DigestRepository, schedule models.DigestSchedule) DigestServiceInterface {
+	return &DigestService{
+		digestRepo: digestRepo,
+		schedule:   schedule,
+	}
+}
+
+// Returns the user's preferences, or the defaults (digests off) if they have none
+func (s *DigestService) GetPreferences(userID int) (*models.DigestPreferences, error) {
+	preferences, err := s.digestRepo.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	if preferences == nil {
+		defaults := models.DefaultDigestPreferences(userID)
+		return &defaults, nil
+	}
+	return preferences, nil
+}
+
+// Saves the preferences and schedules the next digest. Changing the frequency
+// reschedules it; other changes keep the digest already scheduled.
+func (s *DigestService) UpdatePreferences(userID int, frequency models.DigestFrequency, statuses []models.TaskStatus, watchedOnly bool) (*models.DigestPreferences, error) {
+	for _, status := range statuses {
+		if !status.IsValid() {
+			return nil, models.ValidationError{Field: "statuses", Message: fmt.Sprintf("invalid status '%s'", status)}
+		}
+	}
+
+	preferences, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if frequency == models.DigestOff {
+		preferences.NextDigestAt = nil
+	} else if frequency != preferences.Frequency || preferences.NextDigestAt == nil {
+		next := s.schedule.Next(frequency, time.Now())
+		preferences.NextDigestAt = &next
+	}
+	preferences.Frequency = frequency
+	preferences.Statuses = statuses
+	if preferences.Statuses == nil {
+		preferences.Statuses = []models.TaskStatus{}
+	}
+	preferences.WatchedOnly = watchedOnly
+
+	if err := s.digestRepo.SavePreferences(preferences); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
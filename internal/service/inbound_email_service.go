@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AashishRichhariya/task-management-api/internal/email"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+// Limits of CreateTaskRequest, which emailed tasks are held to as well
+const (
+	maxTaskTitleLength       = 255
+	maxTaskDescriptionLength = 1000
+)
+
+// Forwarding prefixes dropped from subjects, possibly repeated ("Fwd: FW: ...")
+var forwardPrefixPattern = regexp.MustCompile(`(?i)^\s*((fwd?|fw|re)\s*:\s*)+`)
+
+// Turns received emails into tasks. A subject carrying a task's reply tag updates
+// that task instead: the reply text is added to its description and a first line
+// such as "status: completed" changes its status. Attachments are stored on the task.
+type InboundEmailService struct {
+	taskService    TaskServiceInterface
+	attachmentRepo repository.AttachmentRepository
+	tokens         *email.ReplyTokens
+	allowedDomains []string
+}
+
+// allowedDomains restricts senders to those domains; empty allows anyone
+func NewInboundEmailService(taskService TaskServiceInterface, attachmentRepo repository.AttachmentRepository,
+	tokens *email.ReplyTokens, allowedDomains []string) *InboundEmailService {
+	return &InboundEmailService{
+		taskService:    taskService,
+		attachmentRepo: attachmentRepo,
+		tokens:         tokens,
+		allowedDomains: allowedDomains,
+	}
+}
+
+func (s *InboundEmailService) HandleMessage(envelope email.Envelope, msg *email.Message) error {
+	sender := msg.From
+	if sender == "" {
+		sender = envelope.From
+	}
+	if !s.allowsSender(sender) {
+		return fmt.Errorf("sender %s is not allowed to create tasks", sender)
+	}
+
+	// Mail is not tied to a request, so nothing cancels its changes
+	ctx := context.Background()
+	if taskID, ok := s.tokens.Find(msg.Subject); ok {
+		return s.applyReply(ctx, taskID, sender, msg)
+	}
+	return s.createTask(ctx, sender, msg)
+}
+
+// Builds the request for a task made from an email, keeping within its limits.
+// Reports whether the description had to be shortened.
+func TaskRequestFromEmail(msg *email.Message) (models.CreateTaskRequest, bool) {
+	title := strings.TrimSpace(forwardPrefixPattern.ReplaceAllString(msg.Subject, ""))
+	if title == "" {
+		title = "(no subject)"
+	}
+
+	description, truncated := truncateText(msg.Text, maxTaskDescriptionLength)
+	title, _ = truncateText(title, maxTaskTitleLength)
+	return models.CreateTaskRequest{
+		Title:       title,
+		Description: description,
+		Status:      string(models.StatusPending),
+	}, truncated
+}
+
+func (s *InboundEmailService) createTask(ctx context.Context, sender string, msg *email.Message) error {
+	req, truncated := TaskRequestFromEmail(msg)
+	task, err := s.taskService.CreateTask(ctx, req.Title, req.Description, req.Status, req.CustomFields)
+	if err != nil {
+		return err
+	}
+	log.Printf("Created task %d from email from %s", task.ID, sender)
+
+	attachments := msg.Attachments
+	if truncated {
+		// Keep the whole message when the description only holds the start of it
+		attachments = append(attachments, email.Attachment{Filename: "message.txt", ContentType: "text/plain", Data: []byte(msg.Text)})
+	}
+	return s.storeAttachments(task.ID, sender, attachments)
+}
+
+func (s *InboundEmailService) applyReply(ctx context.Context, taskID int, sender string, msg *email.Message) error {
+	task, err := s.taskService.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	body := stripQuotedReply(msg.Text)
+	status := ""
+	if first, rest, _ := strings.Cut(body, "\n"); strings.HasPrefix(strings.ToLower(first), "status:") {
+		status = strings.ToLower(strings.TrimSpace(first[len("status:"):]))
+		if !models.TaskStatus(status).IsValid() {
+			return models.ValidationError{Field: "status", Message: fmt.Sprintf("unknown status '%s'", status)}
+		}
+		body = strings.TrimSpace(rest)
+	}
+
+	attachments := msg.Attachments
+	description := ""
+	if body != "" {
+		note := fmt.Sprintf("Reply from %s on %s:\n%s", sender, time.Now().UTC().Format("2006-01-02 15:04"), body)
+		description = strings.TrimSpace(task.Description + "\n\n" + note)
+		if utf8.RuneCountInString(description) > maxTaskDescriptionLength {
+			// Too long to inline, so keep the reply as a file and point to it
+			filename := fmt.Sprintf("reply-%s.txt", time.Now().UTC().Format("20060102-150405"))
+			attachments = append(attachments, email.Attachment{Filename: filename, ContentType: "text/plain", Data: []byte(body)})
+			description = strings.TrimSpace(task.Description + "\n\n" + fmt.Sprintf("Reply from %s attached as %s", sender, filename))
+			if utf8.RuneCountInString(description) > maxTaskDescriptionLength {
+				description = ""
+			}
+		}
+	}
+
+	if description != "" || status != "" {
+		if _, err := s.taskService.UpdateTask(ctx, taskID, "", description, status, nil); err != nil {
+			return err
+		}
+	}
+	log.Printf("Applied email reply from %s to task %d", sender, taskID)
+	return s.storeAttachments(taskID, sender, attachments)
+}
+
+func (s *InboundEmailService) storeAttachments(taskID int, sender string, attachments []email.Attachment) error {
+	for _, file := range attachments {
+		filename, _ := truncateText(file.Filename, 255)
+		attachment := &models.TaskAttachment{
+			TaskID:      taskID,
+			Filename:    filename,
+			ContentType: file.ContentType,
+			Source:      sender,
+			Data:        file.Data,
+		}
+		if err := s.attachmentRepo.CreateAttachment(attachment); err != nil {
+			return fmt.Errorf("failed to store attachment %q of task %d: %w", file.Filename, taskID, err)
+		}
+	}
+	return nil
+}
+
+func (s *InboundEmailService) allowsSender(sender string) bool {
+	if len(s.allowedDomains) == 0 {
+		return true
+	}
+	_, domain, _ := strings.Cut(sender, "@")
+	for _, allowed := range s.allowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Drops the quoted message mail clients put under a reply: "> " lines and everything
+// from an "On ... wrote:" line or an "-----Original Message-----" separator onwards
+func stripQuotedReply(text string) string {
+	kept := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "-----Original Message-----") ||
+			(strings.HasPrefix(trimmed, "On ") && strings.HasSuffix(trimmed, "wrote:")) {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// Cuts text to at most limit characters, reporting whether it was cut
+func truncateText(text string, limit int) (string, bool) {
+	if utf8.RuneCountInString(text) <= limit {
+		return text, false
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:limit-1])) + "…", true
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/email"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+type inboundEmailTestSetup struct {
+	tasks       TaskServiceInterface
+	attachments *mockAttachmentRepository
+	tokens      *email.ReplyTokens
+	addr        string
+}
+
+// Runs a real SMTP listener in front of the service, as in production
+func newInboundEmailTestSetup(t *testing.T) inboundEmailTestSetup {
+	setup := inboundEmailTestSetup{
+		tasks:       NewTaskService(newMockTaskRepository()),
+		attachments: &mockAttachmentRepository{},
+		tokens:      email.NewReplyTokens("test-secret"),
+	}
+	service := NewInboundEmailService(setup.tasks, setup.attachments, setup.tokens, []string{"example.com"})
+
+	config := email.DefaultServerConfig()
+	config.Addr = "127.0.0.1:0"
+	server := email.NewServer(config, service)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Failed to start SMTP server: %v", err)
+	}
+	t.Cleanup(server.Stop)
+	setup.addr = server.Addr()
+	return setup
+}
+
+func (s inboundEmailTestSetup) send(from, subject, body string) error {
+	message := fmt.Sprintf("From: %s\r\nTo: support@tasks.test\r\nSubject: %s\r\n\r\n%s\r\n", from, subject, body)
+	return smtp.SendMail(s.addr, nil, from, []string{"support@tasks.test"}, []byte(message))
+}
+
+func TestInboundEmail_CreatesTask(t *testing.T) {
+	setup := newInboundEmailTestSetup(t)
+
+	message := "From: jane@example.com\r\n" +
+		"Subject: Fwd: Printer on fire\r\n" +
+		"Content-Type: multipart/mixed; boundary=b\r\n" +
+		"\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"\r\n" +
+		"Floor 3, next to the kitchen.\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"Content-Disposition: attachment; filename=log.txt\r\n" +
+		"\r\n" +
+		"paper jam\r\n" +
+		"--b--\r\n"
+	if err := smtp.SendMail(setup.addr, nil, "jane@example.com", []string{"support@tasks.test"}, []byte(message)); err != nil {
+		t.Fatalf("SendMail failed: %v", err)
+	}
+
+	task, err := setup.tasks.GetTaskByID(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected a task to be created: %v", err)
+	}
+	if task.Title != "Printer on fire" || task.Description != "Floor 3, next to the kitchen." || task.Status != models.StatusPending {
+		t.Errorf("Unexpected task: %+v", task)
+	}
+
+	if len(setup.attachments.attachments) != 1 {
+		t.Fatalf("Expected one attachment, got %d", len(setup.attachments.attachments))
+	}
+	attachment := setup.attachments.attachments[0]
+	if attachment.TaskID != task.ID || attachment.Filename != "log.txt" || attachment.Source != "jane@example.com" {
+		t.Errorf("Unexpected attachment: %+v", attachment)
+	}
+}
+
+func TestInboundEmail_LongBodyIsKeptAsAttachment(t *testing.T) {
+	setup := newInboundEmailTestSetup(t)
+
+	body := strings.Repeat("word ", 300)
+	if err := setup.send("jane@example.com", "Long", body); err != nil {
+		t.Fatalf("SendMail failed: %v", err)
+	}
+
+	task, _ := setup.tasks.GetTaskByID(context.Background(), 1)
+	if length := len([]rune(task.Description)); length > maxTaskDescriptionLength {
+		t.Errorf("Expected the description to fit %d characters, got %d", maxTaskDescriptionLength, length)
+	}
+	if len(setup.attachments.attachments) != 1 || setup.attachments.attachments[0].Filename != "message.txt" {
+		t.Errorf("Expected the full message as an attachment, got %+v", setup.attachments.attachments)
+	}
+}
+
+func TestInboundEmail_ReplyUpdatesTask(t *testing.T) {
+	setup := newInboundEmailTestSetup(t)
+	task, _ := setup.tasks.CreateTask(context.Background(), "Printer on fire", "Floor 3", "in_progress", nil)
+
+	reply := "status: completed\r\nFire is out.\r\n\r\nOn Mon, Support wrote:\r\n> Floor 3"
+	if err := setup.send("bob@example.com", "Re: Printer on fire "+setup.tokens.Tag(task.ID), reply); err != nil {
+		t.Fatalf("SendMail failed: %v", err)
+	}
+
+	updated, _ := setup.tasks.GetTaskByID(context.Background(), task.ID)
+	if updated.Status != models.StatusCompleted {
+		t.Errorf("Expected status completed, got %s", updated.Status)
+	}
+	if !strings.HasPrefix(updated.Description, "Floor 3\n\nReply from bob@example.com") || !strings.HasSuffix(updated.Description, "Fire is out.") {
+		t.Errorf("Expected the reply appended without the quote, got %q", updated.Description)
+	}
+	if _, err := setup.tasks.GetTaskByID(context.Background(), task.ID+1); err == nil {
+		t.Error("Expected the reply not to create a task")
+	}
+}
+
+func TestInboundEmail_Rejections(t *testing.T) {
+	setup := newInboundEmailTestSetup(t)
+	task, _ := setup.tasks.CreateTask(context.Background(), "Task", "", "pending", nil)
+
+	if err := setup.send("mallory@evil.test", "Spam", "Buy now"); err == nil {
+		t.Error("Expected a sender outside the allowed domains to be rejected")
+	}
+
+	// A forged tag is not a reply, so it creates a task rather than updating one
+	forged := strings.Replace(setup.tokens.Tag(task.ID), fmt.Sprint(task.ID), "99", 1)
+	setup.send("jane@example.com", "Re: "+forged, "status: closed")
+	unchanged, _ := setup.tasks.GetTaskByID(context.Background(), task.ID)
+	if unchanged.Status != models.StatusPending {
+		t.Errorf("Forged reply changed the task to %s", unchanged.Status)
+	}
+
+	if err := setup.send("jane@example.com", "Re: "+setup.tokens.Tag(task.ID), "status: done"); err == nil {
+		t.Error("Expected an unknown status to be rejected")
+	}
+}
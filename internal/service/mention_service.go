@@ -0,0 +1,34 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type MentionService struct {
+	mentionRepo repository.MentionRepository
+}
+
+type MentionServiceInterface interface {
+	GetMentions(userID int, page, limit int) (*models.PaginatedMentionsResponse, error)
+}
+
+func NewMentionService(mentionRepo repository.MentionRepository) MentionServiceInterface {
+	return &MentionService{
+		mentionRepo: mentionRepo,
+	}
+}
+
+func (s *MentionService) GetMentions(userID int, page, limit int) (*models.PaginatedMentionsResponse, error) {
+	mentions, totalCount, err := s.mentionRepo.GetMentions(userID, limit, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+
+	return &models.PaginatedMentionsResponse{
+		Mentions:   mentions,
+		Pagination: paginationMeta(page, limit, totalCount),
+	}, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"@alice please review", []string{"alice"}},
+		{"cc @Alice, @bob and @alice again.", []string{"alice", "bob"}},
+		{"Thanks @carol.", []string{"carol"}},
+		{"mail bob@example.com instead", []string{}},
+		{"(@dave) and\n@erin.smith", []string{"dave", "erin.smith"}},
+		{"just an @ sign", []string{}},
+	}
+
+	for _, test := range tests {
+		handles := models.ParseMentions(test.text)
+		if len(handles) != len(test.expected) {
+			t.Errorf("ParseMentions(%q) = %v, expected %v", test.text, handles, test.expected)
+			continue
+		}
+		for i := range handles {
+			if handles[i] != test.expected[i] {
+				t.Errorf("ParseMentions(%q) = %v, expected %v", test.text, handles, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestTaskService_RecordsMentions(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	userRepo := newMockUserRepository()
+	mentionRepo := newMockMentionRepository(taskRepo)
+	userService := NewUserService(userRepo)
+	taskService := NewTaskService(taskRepo, WithMentions(userRepo, mentionRepo))
+	mentionService := NewMentionService(mentionRepo)
+
+	alice, _ := userService.CreateUser("alice", "alice@example.com", "")
+	bob, _ := userService.CreateUser("Bob", "bob@example.com", "")
+
+	// Unknown handles are ignored without failing the request
+	task, err := taskService.CreateTask(context.Background(), "Review", "@alice can you look at this? @nobody", "pending", nil)
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	result, err := mentionService.GetMentions(alice.ID, 1, 20)
+	if err != nil {
+		t.Fatalf("GetMentions failed: %v", err)
+	}
+	if len(result.Mentions) != 1 {
+		t.Fatalf("Expected 1 mention, got %d", len(result.Mentions))
+	}
+	mention := result.Mentions[0]
+	if mention.TaskID != task.ID || mention.TaskTitle != "Review" || mention.Source != models.MentionSourceDescription {
+		t.Errorf("Unexpected mention: %+v", mention)
+	}
+
+	// Only mentions added by an update are recorded
+	_, err = taskService.UpdateTask(context.Background(), task.ID, "", "@alice can you look at this? Also @bob", "", nil)
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+
+	result, _ = mentionService.GetMentions(alice.ID, 1, 20)
+	if len(result.Mentions) != 1 {
+		t.Errorf("Expected alice to still have 1 mention, got %d", len(result.Mentions))
+	}
+	result, _ = mentionService.GetMentions(bob.ID, 1, 20)
+	if len(result.Mentions) != 1 || result.Mentions[0].Excerpt != "@alice can you look at this? Also @bob" {
+		t.Errorf("Expected bob to be mentioned once, got %+v", result.Mentions)
+	}
+}
+
+func TestTaskService_RecordsMentionsInTheUnitOfWork(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	userRepo := newMockUserRepository()
+	mentionRepo := newMockMentionRepository(taskRepo)
+	txManager := &mockTxManager{tasks: taskRepo.(*repository.MemoryTaskRepository), mentions: mentionRepo}
+	taskService := NewTaskService(taskRepo, WithMentions(userRepo, mentionRepo), WithTransactions(txManager))
+
+	alice, _ := NewUserService(userRepo).CreateUser("alice", "alice@example.com", "")
+
+	// A create whose mentions fail to store is retried as a whole, and records them once
+	txManager.retries = 1
+	mentionRepo.failures = 1
+	task, err := taskService.CreateTask(context.Background(), "Review", "@alice can you look at this?", "pending", nil)
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if txManager.attempts != 2 {
+		t.Errorf("Expected the create to run twice, got %d attempts", txManager.attempts)
+	}
+	if _, total, _ := taskRepo.GetAllTasks(context.Background(), 10, 1, "", "created_at", "asc", nil); total != 1 {
+		t.Errorf("Expected one task to be kept, got %d", total)
+	}
+	if len(mentionRepo.mentions) != 1 || mentionRepo.mentions[0].UserID != alice.ID || mentionRepo.mentions[0].TaskID != task.ID {
+		t.Errorf("Expected one mention of alice on task %d, got %+v", task.ID, mentionRepo.mentions)
+	}
+
+	// So does a retried update
+	txManager.retries = 1
+	if _, err := taskService.UpdateTask(context.Background(), task.ID, "", "@alice can you look at this? @alice", "", nil); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if len(mentionRepo.mentions) != 1 {
+		t.Errorf("Expected no new mentions, got %+v", mentionRepo.mentions)
+	}
+}
+
+// User repository noting lookups made while a unit of work is running
+type txWatchingUserRepository struct {
+	repository.UserRepository
+	txManager *mockTxManager
+	inTx      int
+}
+
+func (r *txWatchingUserRepository) GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.User, error) {
+	if r.txManager.active {
+		r.inTx++
+	}
+	return r.UserRepository.GetUsersByUsernames(ctx, usernames)
+}
+
+// Custom field repository noting lookups made while a unit of work is running
+type txWatchingFieldRepository struct {
+	repository.CustomFieldRepository
+	txManager *mockTxManager
+	inTx      int
+}
+
+func (r *txWatchingFieldRepository) GetAllFields(ctx context.Context) ([]models.CustomFieldDefinition, error) {
+	if r.txManager.active {
+		r.inTx++
+	}
+	return r.CustomFieldRepository.GetAllFields(ctx)
+}
+
+func TestTaskService_ReadsUsersAndFieldsBeforeTheUnitOfWork(t *testing.T) {
+	taskRepo := newMockTaskRepository()
+	mentionRepo := newMockMentionRepository(taskRepo)
+	txManager := &mockTxManager{tasks: taskRepo.(*repository.MemoryTaskRepository), mentions: mentionRepo}
+	userRepo := &txWatchingUserRepository{UserRepository: newMockUserRepository(), txManager: txManager}
+	fieldRepo := &txWatchingFieldRepository{CustomFieldRepository: newMockCustomFieldRepository(), txManager: txManager}
+	taskService := NewTaskService(taskRepo, WithMentions(userRepo, mentionRepo), WithCustomFields(fieldRepo), WithTransactions(txManager))
+
+	NewUserService(userRepo).CreateUser("alice", "alice@example.com", "")
+	NewCustomFieldService(fieldRepo).CreateField("points", "Points", "number", nil, false)
+
+	// A unit of work holds its connection; reading through the pool from inside it could wait forever
+	task, err := taskService.CreateTask(context.Background(), "Review", "@alice please", "pending", map[string]any{"points": 3})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	_, err = taskService.UpdateTask(context.Background(), task.ID, "", "@alice please, @bob too", "", map[string]any{"points": 5})
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+
+	if userRepo.inTx != 0 || fieldRepo.inTx != 0 {
+		t.Errorf("Expected no reads inside the unit of work, got %d user and %d custom field reads", userRepo.inTx, fieldRepo.inTx)
+	}
+	if len(mentionRepo.mentions) != 1 {
+		t.Errorf("Expected alice to be mentioned once, got %+v", mentionRepo.mentions)
+	}
+}
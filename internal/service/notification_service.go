@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type NotificationService struct {
+	notificationRepo repository.NotificationRepository
+	watcherRepo      repository.WatcherRepository
+}
+
+type NotificationServiceInterface interface {
+	TaskEventHandler
+	GetNotifications(userID int, unreadOnly bool, page, limit int) (*models.PaginatedNotificationsResponse, error)
+	MarkRead(userID, id int) error
+	MarkAllRead(userID int) (int, error)
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, watcherRepo repository.WatcherRepository) NotificationServiceInterface {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		watcherRepo:      watcherRepo,
+	}
+}
+
+// Creates a notification for every watcher of the changed task
+func (s *NotificationService) HandleTaskEvent(event models.TaskEvent) error {
+	if event.Type == models.EventTaskCreated {
+		return nil // Nobody can be watching a task that did not exist yet
+	}
+
+	created, err := s.notificationRepo.CreateForWatchers(event.ID, &models.Notification{
+		TaskID:  event.Task.ID,
+		Event:   event.Type,
+		Message: event.Summary(),
+		Changes: event.Changes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to notify watchers of task %d: %w", event.Task.ID, err)
+	}
+	if created > 0 {
+		log.Printf("Notified %d watchers of %s on task %d", created, event.Type, event.Task.ID)
+	}
+
+	// Watchers of a deleted task have been told; there is nothing left to watch
+	if event.Type == models.EventTaskDeleted {
+		return s.watcherRepo.RemoveAllWatchers(event.Task.ID)
+	}
+	return nil
+}
+
+func (s *NotificationService) GetNotifications(userID int, unreadOnly bool, page, limit int) (*models.PaginatedNotificationsResponse, error) {
+	notifications, totalCount, err := s.notificationRepo.GetNotifications(userID, unreadOnly, limit, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return &models.PaginatedNotificationsResponse{
+		Notifications: notifications,
+		Unread:        unread,
+		Pagination:    paginationMeta(page, limit, totalCount),
+	}, nil
+}
+
+func (s *NotificationService) MarkRead(userID, id int) error {
+	found, err := s.notificationRepo.MarkRead(userID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return models.NotFoundError{Resource: "notification", ID: id}
+	}
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(userID int) (int, error) {
+	return s.notificationRepo.MarkAllRead(userID)
+}
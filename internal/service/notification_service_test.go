@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+type notificationTestEnv struct {
+	taskService         TaskServiceInterface
+	watcherService      WatcherServiceInterface
+	notificationService NotificationServiceInterface
+	userService         UserServiceInterface
+	watcherRepo         *mockWatcherRepository
+}
+
+func newNotificationTestEnv() notificationTestEnv {
+	taskRepo := newMockTaskRepository()
+	userRepo := newMockUserRepository()
+	watcherRepo := newMockWatcherRepository(userRepo)
+	notificationService := NewNotificationService(newMockNotificationRepository(watcherRepo), watcherRepo)
+
+	return notificationTestEnv{
+		taskService:         NewTaskService(taskRepo, WithEventHandlers(notificationService)),
+		watcherService:      NewWatcherService(taskRepo, watcherRepo),
+		notificationService: notificationService,
+		userService:         NewUserService(userRepo),
+		watcherRepo:         watcherRepo,
+	}
+}
+
+func TestNotificationService_WatchersAreNotified(t *testing.T) {
+	env := newNotificationTestEnv()
+	alice, _ := env.userService.CreateUser("alice", "alice@example.com", "")
+	bob, _ := env.userService.CreateUser("bob", "bob@example.com", "")
+
+	task, _ := env.taskService.CreateTask(context.Background(), "Ship it", "", "pending", nil)
+	if err := env.watcherService.Watch(context.Background(), task.ID, alice.ID); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	env.taskService.UpdateTask(context.Background(), task.ID, "", "", "in_progress", nil)
+
+	result, err := env.notificationService.GetNotifications(alice.ID, false, 1, 20)
+	if err != nil {
+		t.Fatalf("GetNotifications failed: %v", err)
+	}
+	if len(result.Notifications) != 1 || result.Unread != 1 {
+		t.Fatalf("Expected one unread notification, got %+v", result)
+	}
+
+	notification := result.Notifications[0]
+	if notification.Event != models.EventTaskStatusChanged || notification.TaskID != task.ID {
+		t.Errorf("Unexpected notification: %+v", notification)
+	}
+	if len(notification.Changes) == 0 || notification.Changes[0].Field != "status" {
+		t.Errorf("Expected the status change to be recorded, got %+v", notification.Changes)
+	}
+
+	// Bob is not watching
+	result, _ = env.notificationService.GetNotifications(bob.ID, false, 1, 20)
+	if len(result.Notifications) != 0 {
+		t.Errorf("Expected no notifications for a non-watcher, got %d", len(result.Notifications))
+	}
+}
+
+func TestNotificationService_UnchangedUpdateIsSilent(t *testing.T) {
+	env := newNotificationTestEnv()
+	alice, _ := env.userService.CreateUser("alice", "alice@example.com", "")
+
+	task, _ := env.taskService.CreateTask(context.Background(), "Ship it", "", "pending", nil)
+	env.watcherService.Watch(context.Background(), task.ID, alice.ID)
+
+	env.taskService.UpdateTask(context.Background(), task.ID, "Ship it", "", "", nil)
+
+	result, _ := env.notificationService.GetNotifications(alice.ID, false, 1, 20)
+	if len(result.Notifications) != 0 {
+		t.Errorf("Expected no notification when nothing changed, got %+v", result.Notifications)
+	}
+}
+
+func TestNotificationService_DeleteNotifiesAndClearsWatchers(t *testing.T) {
+	env := newNotificationTestEnv()
+	alice, _ := env.userService.CreateUser("alice", "alice@example.com", "")
+
+	task, _ := env.taskService.CreateTask(context.Background(), "Ship it", "", "pending", nil)
+	env.watcherService.Watch(context.Background(), task.ID, alice.ID)
+
+	if err := env.taskService.DeleteTask(context.Background(), task.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	result, _ := env.notificationService.GetNotifications(alice.ID, false, 1, 20)
+	if len(result.Notifications) != 1 || result.Notifications[0].Event != models.EventTaskDeleted {
+		t.Fatalf("Expected a deletion notification, got %+v", result.Notifications)
+	}
+	if len(env.watcherRepo.watchers[task.ID]) != 0 {
+		t.Errorf("Expected watchers of a deleted task to be removed")
+	}
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	env := newNotificationTestEnv()
+	alice, _ := env.userService.CreateUser("alice", "alice@example.com", "")
+	bob, _ := env.userService.CreateUser("bob", "bob@example.com", "")
+
+	task, _ := env.taskService.CreateTask(context.Background(), "Ship it", "", "pending", nil)
+	env.watcherService.Watch(context.Background(), task.ID, alice.ID)
+	env.taskService.UpdateTask(context.Background(), task.ID, "Renamed", "", "", nil)
+	env.taskService.UpdateTask(context.Background(), task.ID, "", "Details", "", nil)
+
+	result, _ := env.notificationService.GetNotifications(alice.ID, true, 1, 20)
+	if len(result.Notifications) != 2 {
+		t.Fatalf("Expected 2 unread notifications, got %d", len(result.Notifications))
+	}
+
+	// Another user's notification cannot be marked
+	var notFound models.NotFoundError
+	if err := env.notificationService.MarkRead(bob.ID, result.Notifications[0].ID); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError, got %v", err)
+	}
+
+	if err := env.notificationService.MarkRead(alice.ID, result.Notifications[0].ID); err != nil {
+		t.Fatalf("MarkRead failed: %v", err)
+	}
+	result, _ = env.notificationService.GetNotifications(alice.ID, true, 1, 20)
+	if len(result.Notifications) != 1 || result.Unread != 1 {
+		t.Errorf("Expected 1 unread notification, got %+v", result)
+	}
+
+	updated, err := env.notificationService.MarkAllRead(alice.ID)
+	if err != nil || updated != 1 {
+		t.Errorf("Expected MarkAllRead to update 1 notification, got %d (%v)", updated, err)
+	}
+}
+
+func TestWatcherService_UnknownTask(t *testing.T) {
+	env := newNotificationTestEnv()
+	alice, _ := env.userService.CreateUser("alice", "alice@example.com", "")
+
+	var notFound models.TaskNotFoundError
+	if err := env.watcherService.Watch(context.Background(), 999, alice.ID); !errors.As(err, &notFound) {
+		t.Errorf("Expected TaskNotFoundError, got %v", err)
+	}
+}
+
+func TestUserService_AuthenticateToken(t *testing.T) {
+	userService := NewUserService(newMockUserRepository())
+
+	created, err := userService.CreateUser("alice", "alice@example.com", "Alice")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if created.APIToken == "" {
+		t.Fatal("Expected an API token to be issued")
+	}
+
+	user, err := userService.AuthenticateToken(created.APIToken)
+	if err != nil || user.ID != created.ID {
+		t.Errorf("Expected token to resolve to user %d, got %+v (%v)", created.ID, user, err)
+	}
+
+	var unauthorized models.UnauthorizedError
+	if _, err := userService.AuthenticateToken("not-a-token"); !errors.As(err, &unauthorized) {
+		t.Errorf("Expected UnauthorizedError, got %v", err)
+	}
+
+	if _, err := userService.CreateUser("ALICE", "other@example.com", ""); err == nil {
+		t.Error("Expected usernames to be unique regardless of case")
+	}
+}
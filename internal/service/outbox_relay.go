@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/health"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type OutboxRelayConfig struct {
+	PollInterval time.Duration // How often the outbox is checked for unpublished messages
+	BatchSize    int           // Messages claimed per transaction
+	BaseBackoff  time.Duration // Delay before retrying a failed message, doubled for each later attempt
+	MaxBackoff   time.Duration
+	Retention    time.Duration // How long published messages are kept
+}
+
+func DefaultOutboxRelayConfig() OutboxRelayConfig {
+	return OutboxRelayConfig{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   10 * time.Minute,
+		Retention:    7 * 24 * time.Hour,
+	}
+}
+
+// OutboxRelay publishes the task events recorded in the outbox to its sinks. Any
+// TaskEventHandler can be a sink. A message is marked published once every sink has
+// accepted it; when one fails all of them see it again on the retry, so sinks must
+// tolerate duplicates (TaskEvent.ID identifies them). The notification and webhook sinks
+// skip rows they already created for a message.
+type OutboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	config     OutboxRelayConfig
+	sinks      []TaskEventHandler
+	done       chan struct{}
+	wg         sync.WaitGroup
+	heartbeat  health.Heartbeat
+}
+
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, config OutboxRelayConfig, sinks ...TaskEventHandler) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		config:     config,
+		sinks:      sinks,
+		done:       make(chan struct{}),
+	}
+}
+
+func (r *OutboxRelay) Start() {
+	r.heartbeat.Beat()
+	r.wg.Add(1)
+	go r.run()
+}
+
+// Stops the relay after the batch currently being published, if any, is finished
+func (r *OutboxRelay) Stop() {
+	close(r.done)
+	r.wg.Wait()
+}
+
+// Beats before every batch; see health.Heartbeat
+func (r *OutboxRelay) Heartbeat() *health.Heartbeat {
+	return &r.heartbeat
+}
+
+// Publishes one batch of messages, returning how many were published
+func (r *OutboxRelay) RelayBatch() (int, error) {
+	return r.outboxRepo.PublishBatch(r.config.BatchSize, r.backoff, r.publish)
+}
+
+func (r *OutboxRelay) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			// Keep going while full batches are published so a backlog drains quickly
+			for {
+				r.heartbeat.Beat()
+				published, err := r.RelayBatch()
+				if err != nil {
+					log.Printf("Outbox relay failed: %v", err)
+				}
+				if err != nil || published < r.config.BatchSize {
+					break
+				}
+				select {
+				case <-r.done:
+					return
+				default:
+				}
+			}
+
+			if time.Since(lastPrune) > time.Hour {
+				lastPrune = time.Now()
+				if pruned, err := r.outboxRepo.DeletePublished(lastPrune.Add(-r.config.Retention)); err != nil {
+					log.Printf("Outbox prune failed: %v", err)
+				} else if pruned > 0 {
+					log.Printf("Pruned %d published outbox messages", pruned)
+				}
+			}
+		}
+	}
+}
+
+func (r *OutboxRelay) publish(message models.OutboxMessage) error {
+	event, err := message.Event()
+	if err != nil {
+		return fmt.Errorf("failed to decode outbox message %d: %w", message.ID, err)
+	}
+
+	var errs []error
+	for _, sink := range r.sinks {
+		if err := sink.HandleTaskEvent(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		log.Printf("Outbox message %d (%s on task %d) failed on attempt %d: %v",
+			message.ID, message.EventType, message.AggregateID, message.Attempts+1, err)
+		return err
+	}
+	return nil
+}
+
+// Delay before retrying a message that has failed the given number of times
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.config.BaseBackoff
+	for i := 1; i < attempts && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.MaxBackoff)
+}
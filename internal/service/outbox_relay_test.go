@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+// Sink recording events, failing the first failures calls
+type recordingSink struct {
+	failures int
+	events   []models.TaskEvent
+}
+
+func (s *recordingSink) HandleTaskEvent(event models.TaskEvent) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestOutboxRelay_PublishesToAllSinks(t *testing.T) {
+	outboxRepo := newMockOutboxRepository()
+	first, second := &recordingSink{}, &recordingSink{}
+	relay := NewOutboxRelay(outboxRepo, DefaultOutboxRelayConfig(), first, second)
+
+	outboxRepo.add(models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: 1, Title: "A"}})
+	outboxRepo.add(models.TaskEvent{Type: models.EventTaskDeleted, Task: models.Task{ID: 1, Title: "A"}})
+
+	published, err := relay.RelayBatch()
+	if err != nil || published != 2 {
+		t.Fatalf("Expected 2 messages to be published, got %d (%v)", published, err)
+	}
+
+	for _, sink := range []*recordingSink{first, second} {
+		if len(sink.events) != 2 || sink.events[0].Type != models.EventTaskCreated || sink.events[1].Type != models.EventTaskDeleted {
+			t.Fatalf("Expected both events in order, got %+v", sink.events)
+		}
+		if sink.events[0].ID != 1 || sink.events[1].ID != 2 {
+			t.Errorf("Expected events to carry their outbox IDs, got %d and %d", sink.events[0].ID, sink.events[1].ID)
+		}
+	}
+
+	if published, _ := relay.RelayBatch(); published != 0 {
+		t.Errorf("Expected nothing left to publish, published %d", published)
+	}
+}
+
+func TestOutboxRelay_RetriesFailedMessages(t *testing.T) {
+	outboxRepo := newMockOutboxRepository()
+	config := DefaultOutboxRelayConfig()
+	config.BaseBackoff = 0 // Retries are due immediately
+	healthy, flaky := &recordingSink{}, &recordingSink{failures: 1}
+	relay := NewOutboxRelay(outboxRepo, config, healthy, flaky)
+
+	outboxRepo.add(models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: 1}})
+
+	if published, _ := relay.RelayBatch(); published != 0 {
+		t.Fatalf("Expected the message to fail, published %d", published)
+	}
+	if outboxRepo.messages[0].PublishedAt != nil || outboxRepo.messages[0].Attempts != 1 {
+		t.Fatalf("Expected the message to stay unpublished, got %+v", outboxRepo.messages[0])
+	}
+
+	if published, _ := relay.RelayBatch(); published != 1 {
+		t.Fatalf("Expected the retry to publish the message")
+	}
+
+	// Delivery is at least once: the healthy sink saw the message twice
+	if len(healthy.events) != 2 || len(flaky.events) != 1 {
+		t.Errorf("Expected 2 and 1 deliveries, got %d and %d", len(healthy.events), len(flaky.events))
+	}
+}
+
+func TestOutboxRelay_RetriesDoNotDuplicateSinkRows(t *testing.T) {
+	for _, failing := range []string{"notifications", "webhooks"} {
+		t.Run(failing, func(t *testing.T) {
+			userRepo := newMockUserRepository()
+			watcherRepo := newMockWatcherRepository(userRepo)
+			notificationRepo := newMockNotificationRepository(watcherRepo)
+			webhookRepo := newMockWebhookRepository()
+			outboxRepo := newMockOutboxRepository()
+			config := DefaultOutboxRelayConfig()
+			config.BaseBackoff = 0 // Retries are due immediately
+			relay := NewOutboxRelay(outboxRepo, config,
+				NewNotificationService(notificationRepo, watcherRepo), NewWebhookService(webhookRepo, localWebhookTargets))
+
+			alice, _ := NewUserService(userRepo).CreateUser("alice", "alice@example.com", "")
+			watcherRepo.AddWatcher(1, alice.ID)
+			webhookRepo.CreateWebhook(&models.Webhook{URL: "https://example.com/hook", Secret: "a-very-secret-value",
+				Active: true, EventTypes: []models.TaskEventType{models.EventTaskUpdated}})
+			if failing == "notifications" {
+				notificationRepo.failures = 1
+			} else {
+				webhookRepo.failures = 1
+			}
+
+			outboxRepo.add(models.TaskEvent{Type: models.EventTaskUpdated, Task: models.Task{ID: 1}})
+			if published, _ := relay.RelayBatch(); published != 0 {
+				t.Fatalf("Expected the message to fail, published %d", published)
+			}
+			if published, _ := relay.RelayBatch(); published != 1 {
+				t.Fatalf("Expected the retry to publish the message")
+			}
+
+			// Each sink created its rows once, whichever of them failed the first time
+			if len(notificationRepo.notifications) != 1 {
+				t.Errorf("Expected 1 notification, got %d", len(notificationRepo.notifications))
+			}
+			if len(webhookRepo.deliveries) != 1 {
+				t.Errorf("Expected 1 webhook delivery, got %d", len(webhookRepo.deliveries))
+			}
+		})
+	}
+}
+
+func TestOutboxRelay_Backoff(t *testing.T) {
+	config := DefaultOutboxRelayConfig()
+	config.BaseBackoff = time.Second
+	config.MaxBackoff = 5 * time.Second
+	relay := NewOutboxRelay(newMockOutboxRepository(), config)
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, delay := range expected {
+		if got := relay.backoff(i + 1); got != delay {
+			t.Errorf("backoff(%d) = %v, expected %v", i+1, got, delay)
+		}
+	}
+}
+
+func TestClassifyTaskChange(t *testing.T) {
+	base := models.Task{Title: "A", Status: models.StatusPending, Rank: "0:i"}
+
+	moved := base
+	moved.Rank = "0:r"
+	renamed := base
+	renamed.Title = "B"
+	renamed.Rank = "0:r"
+	completed := base
+	completed.Status = models.StatusCompleted
+
+	if got := models.ClassifyTaskChange(base, moved); got != models.EventTaskMoved {
+		t.Errorf("Expected a rank-only change to be a move, got %s", got)
+	}
+	if got := models.ClassifyTaskChange(base, renamed); got != models.EventTaskUpdated {
+		t.Errorf("Expected a rename to be an update, got %s", got)
+	}
+	if got := models.ClassifyTaskChange(base, completed); got != models.EventTaskStatusChanged {
+		t.Errorf("Expected a status change, got %s", got)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+const rankRebalanceBatchSize = 200
+
+// RankRebalancer respaces the ranks of status columns in the background.
+// Requests never block the caller and repeated requests for a column that is
+// already queued are collapsed into one.
+type RankRebalancer struct {
+	taskRepo repository.TaskRepository
+	requests chan models.TaskStatus
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	pending map[models.TaskStatus]bool
+}
+
+func NewRankRebalancer(taskRepo repository.TaskRepository) *RankRebalancer {
+	return &RankRebalancer{
+		taskRepo: taskRepo,
+		requests: make(chan models.TaskStatus, 16),
+		done:     make(chan struct{}),
+		pending:  make(map[models.TaskStatus]bool),
+	}
+}
+
+func (r *RankRebalancer) Start() {
+	r.wg.Add(1)
+	go r.run()
+}
+
+// Stops the worker after the column currently being rebalanced, if any, is finished
+func (r *RankRebalancer) Stop() {
+	close(r.done)
+	r.wg.Wait()
+}
+
+// Queues a column for rebalancing; safe to call on a nil rebalancer
+func (r *RankRebalancer) Request(status models.TaskStatus) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	if r.pending[status] {
+		r.mu.Unlock()
+		return
+	}
+	r.pending[status] = true
+	r.mu.Unlock()
+
+	select {
+	case r.requests <- status:
+	default:
+		// Queue is full; drop the request so the writer is never blocked. The next
+		// long rank written to this column will ask again.
+		r.mu.Lock()
+		delete(r.pending, status)
+		r.mu.Unlock()
+	}
+}
+
+func (r *RankRebalancer) run() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case status := <-r.requests:
+			r.mu.Lock()
+			delete(r.pending, status)
+			r.mu.Unlock()
+
+			migrated, err := r.taskRepo.RebalanceRanks(context.Background(), status, rankRebalanceBatchSize)
+			if err != nil {
+				log.Printf("Rank rebalance of column '%s' failed after %d tasks: %v", status, migrated, err)
+				continue
+			}
+			if migrated > 0 {
+				log.Printf("Rebalanced %d ranks in column '%s'", migrated, status)
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+const slashListLimit = 10
+
+type SlashCommandService struct {
+	taskService TaskServiceInterface
+}
+
+type SlashCommandServiceInterface interface {
+	Execute(ctx context.Context, cmd models.SlashCommandRequest) (*models.SlashResponse, error)
+}
+
+func NewSlashCommandService(taskService TaskServiceInterface) SlashCommandServiceInterface {
+	return &SlashCommandService{
+		taskService: taskService,
+	}
+}
+
+// Runs the subcommand in the command text, e.g. "create Fix login bug" or "list in_progress".
+// Mistakes in the text are returned as business errors whose message is meant for the user.
+func (s *SlashCommandService) Execute(ctx context.Context, cmd models.SlashCommandRequest) (*models.SlashResponse, error) {
+	subcommand, args, _ := strings.Cut(strings.TrimSpace(cmd.Text), " ")
+	args = strings.TrimSpace(args)
+
+	switch strings.ToLower(subcommand) {
+	case "", "help":
+		return slashHelp(cmd.Command), nil
+	case "create":
+		return s.create(ctx, cmd, args)
+	case "list":
+		return s.list(ctx, cmd, args)
+	case "show":
+		return s.show(ctx, args)
+	case "status":
+		return s.setStatus(ctx, cmd, args)
+	case "delete":
+		return s.delete(ctx, cmd, args)
+	default:
+		return nil, models.BusinessError{Message: fmt.Sprintf("unknown command '%s'; try `%s help`", subcommand, cmd.Command)}
+	}
+}
+
+// create <title> [| <description>]
+func (s *SlashCommandService) create(ctx context.Context, cmd models.SlashCommandRequest, args string) (*models.SlashResponse, error) {
+	title, description, _ := strings.Cut(args, "|")
+	title, description = strings.TrimSpace(title), strings.TrimSpace(description)
+	if title == "" {
+		return nil, models.BusinessError{Message: fmt.Sprintf("usage: `%s create <title> [| <description>]`", cmd.Command)}
+	}
+	if utf8.RuneCountInString(title) > maxTaskTitleLength || utf8.RuneCountInString(description) > maxTaskDescriptionLength {
+		return nil, models.BusinessError{Message: fmt.Sprintf("titles are limited to %d characters and descriptions to %d", maxTaskTitleLength, maxTaskDescriptionLength)}
+	}
+
+	task, err := s.taskService.CreateTask(ctx, title, description, string(models.StatusPending), nil)
+	if err != nil {
+		return nil, err
+	}
+	return slashTaskResponse(models.SlashResponseInChannel, fmt.Sprintf("%s created task", slashActor(cmd)), task), nil
+}
+
+// list [<status>] [<page>]
+func (s *SlashCommandService) list(ctx context.Context, cmd models.SlashCommandRequest, args string) (*models.SlashResponse, error) {
+	status, page := "", 1
+	for _, arg := range strings.Fields(args) {
+		if n, err := strconv.Atoi(arg); err == nil && n > 0 {
+			page = n
+		} else if models.TaskStatus(arg).IsValid() {
+			status = arg
+		} else {
+			return nil, models.BusinessError{Message: fmt.Sprintf("usage: `%s list [<status>] [<page>]`; statuses are %s", cmd.Command, slashStatuses())}
+		}
+	}
+
+	result, err := s.taskService.GetAllTasks(ctx, page, slashListLimit, status, "updated_at", "desc", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := "All tasks"
+	if status != "" {
+		scope = fmt.Sprintf("Tasks in `%s`", status)
+	}
+	if len(result.Tasks) == 0 {
+		text := fmt.Sprintf("%s: none found", scope)
+		return &models.SlashResponse{ResponseType: models.SlashResponseEphemeral, Text: text, Blocks: []models.MessageBlock{mrkdwnSection(text)}}, nil
+	}
+
+	lines := make([]string, len(result.Tasks))
+	for i, task := range result.Tasks {
+		lines[i] = fmt.Sprintf("`#%d` *%s* · %s", task.ID, models.EscapeMrkdwn(task.Title), task.Status)
+	}
+	footer := fmt.Sprintf("Page %d of %d · %d tasks, most recently updated first", result.Pagination.Page, result.Pagination.Pages, result.Pagination.Total)
+	if result.Pagination.HasNext {
+		footer += fmt.Sprintf(" · `%s list %s` for more", cmd.Command, strings.TrimSpace(fmt.Sprintf("%s %d", status, page+1)))
+	}
+
+	return &models.SlashResponse{
+		ResponseType: models.SlashResponseEphemeral,
+		Text:         fmt.Sprintf("%s (%d)", scope, result.Pagination.Total),
+		Blocks: []models.MessageBlock{
+			mrkdwnSection(fmt.Sprintf("*%s*", scope)),
+			mrkdwnSection(strings.Join(lines, "\n")),
+			{Type: "context", Elements: []models.BlockText{{Type: "mrkdwn", Text: footer}}},
+		},
+	}, nil
+}
+
+// show <id>
+func (s *SlashCommandService) show(ctx context.Context, args string) (*models.SlashResponse, error) {
+	id, err := parseSlashTaskID(args)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.taskService.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return slashTaskResponse(models.SlashResponseEphemeral, "Task", task), nil
+}
+
+// status <id> <status>
+func (s *SlashCommandService) setStatus(ctx context.Context, cmd models.SlashCommandRequest, args string) (*models.SlashResponse, error) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		return nil, models.BusinessError{Message: fmt.Sprintf("usage: `%s status <id> <status>`", cmd.Command)}
+	}
+	id, err := parseSlashTaskID(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	if !models.TaskStatus(fields[1]).IsValid() {
+		return nil, models.BusinessError{Message: fmt.Sprintf("unknown status '%s'; statuses are %s", fields[1], slashStatuses())}
+	}
+
+	task, err := s.taskService.UpdateTask(ctx, id, "", "", fields[1], nil)
+	if err != nil {
+		return nil, err
+	}
+	return slashTaskResponse(models.SlashResponseInChannel, fmt.Sprintf("%s moved task to `%s`", slashActor(cmd), task.Status), task), nil
+}
+
+// delete <id>
+func (s *SlashCommandService) delete(ctx context.Context, cmd models.SlashCommandRequest, args string) (*models.SlashResponse, error) {
+	id, err := parseSlashTaskID(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.taskService.DeleteTask(ctx, id); err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("%s deleted task #%d", slashActor(cmd), id)
+	return &models.SlashResponse{ResponseType: models.SlashResponseInChannel, Text: text, Blocks: []models.MessageBlock{mrkdwnSection(text)}}, nil
+}
+
+func slashHelp(command string) *models.SlashResponse {
+	usage := strings.Join([]string{
+		fmt.Sprintf("`%s create <title> [| <description>]` create a pending task", command),
+		fmt.Sprintf("`%s list [<status>] [<page>]` list tasks, most recently updated first", command),
+		fmt.Sprintf("`%s show <id>` show a task", command),
+		fmt.Sprintf("`%s status <id> <status>` change a task's status", command),
+		fmt.Sprintf("`%s delete <id>` delete a task", command),
+	}, "\n")
+
+	return &models.SlashResponse{
+		ResponseType: models.SlashResponseEphemeral,
+		Text:         "Task commands",
+		Blocks: []models.MessageBlock{
+			mrkdwnSection("*Task commands*\n" + models.EscapeMrkdwn(usage)),
+			{Type: "context", Elements: []models.BlockText{{Type: "mrkdwn", Text: "Statuses: " + slashStatuses()}}},
+		},
+	}
+}
+
+// A message describing one task, headed by what happened to it
+func slashTaskResponse(responseType, heading string, task *models.Task) *models.SlashResponse {
+	summary := fmt.Sprintf("%s #%d: %s", heading, task.ID, task.Title)
+	body := fmt.Sprintf("%s `#%d`\n*%s*", heading, task.ID, models.EscapeMrkdwn(task.Title))
+	if task.Description != "" {
+		body += "\n" + models.EscapeMrkdwn(task.Description)
+	}
+
+	return &models.SlashResponse{
+		ResponseType: responseType,
+		Text:         summary,
+		Blocks: []models.MessageBlock{
+			mrkdwnSection(body),
+			{Type: "section", Fields: []models.BlockText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Status*\n%s", task.Status)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Updated*\n%s", task.UpdatedAt.UTC().Format("Jan 2 15:04 UTC"))},
+			}},
+		},
+	}
+}
+
+func mrkdwnSection(text string) models.MessageBlock {
+	return models.MessageBlock{Type: "section", Text: &models.BlockText{Type: "mrkdwn", Text: text}}
+}
+
+// Mentions the user who ran the command
+func slashActor(cmd models.SlashCommandRequest) string {
+	if cmd.UserID != "" {
+		return fmt.Sprintf("<@%s>", cmd.UserID)
+	}
+	if cmd.UserName != "" {
+		return models.EscapeMrkdwn(cmd.UserName)
+	}
+	return "Someone"
+}
+
+func parseSlashTaskID(arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(arg), "#"))
+	if err != nil || id <= 0 {
+		return 0, models.BusinessError{Message: fmt.Sprintf("'%s' is not a task ID", arg)}
+	}
+	return id, nil
+}
+
+func slashStatuses() string {
+	statuses := make([]string, len(models.WorkflowStatuses))
+	for i, status := range models.WorkflowStatuses {
+		statuses[i] = "`" + string(status) + "`"
+	}
+	return strings.Join(statuses, ", ")
+}
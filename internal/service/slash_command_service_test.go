@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func runSlash(service SlashCommandServiceInterface, text string) (*models.SlashResponse, error) {
+	return service.Execute(context.Background(), models.SlashCommandRequest{Command: "/task", Text: text, UserID: "U123"})
+}
+
+func TestSlashCommand_CreateAndShow(t *testing.T) {
+	taskService := NewTaskService(newMockTaskRepository())
+	slash := NewSlashCommandService(taskService)
+
+	response, err := runSlash(slash, "create Fix login bug | Users get <script> errors")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.ResponseType != models.SlashResponseInChannel || response.Text != "<@U123> created task #1: Fix login bug" {
+		t.Errorf("Unexpected response %+v", response)
+	}
+
+	task, _ := taskService.GetTaskByID(context.Background(), 1)
+	if task.Title != "Fix login bug" || task.Description != "Users get <script> errors" || task.Status != models.StatusPending {
+		t.Errorf("Unexpected task %+v", task)
+	}
+
+	response, err = runSlash(slash, "show #1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.ResponseType != models.SlashResponseEphemeral || !strings.Contains(response.Blocks[0].Text.Text, "&lt;script&gt;") {
+		t.Errorf("Expected an ephemeral, escaped task message, got %+v", response.Blocks[0].Text)
+	}
+}
+
+func TestSlashCommand_ListAndStatus(t *testing.T) {
+	taskService := NewTaskService(newMockTaskRepository())
+	slash := NewSlashCommandService(taskService)
+	for i := 0; i < 12; i++ {
+		taskService.CreateTask(context.Background(), "Task", "", "in_progress", nil)
+	}
+	taskService.CreateTask(context.Background(), "Other", "", "pending", nil)
+
+	response, err := runSlash(slash, "list in_progress")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Text != "Tasks in `in_progress` (12)" {
+		t.Errorf("Unexpected list summary %q", response.Text)
+	}
+	if footer := response.Blocks[2].Elements[0].Text; !strings.Contains(footer, "`/task list in_progress 2` for more") {
+		t.Errorf("Expected a hint for the next page, got %q", footer)
+	}
+
+	if _, err := runSlash(slash, "status 13 completed"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	task, _ := taskService.GetTaskByID(context.Background(), 13)
+	if task.Status != models.StatusCompleted {
+		t.Errorf("Expected status completed, got %s", task.Status)
+	}
+}
+
+func TestSlashCommand_Errors(t *testing.T) {
+	slash := NewSlashCommandService(NewTaskService(newMockTaskRepository()))
+
+	tests := []struct {
+		text    string
+		message string
+	}{
+		{"frobnicate", "unknown command 'frobnicate'"},
+		{"create", "usage: `/task create"},
+		{"create " + strings.Repeat("x", 256), "titles are limited to 255 characters"},
+		{"list done", "usage: `/task list"},
+		{"show abc", "'abc' is not a task ID"},
+		{"status 1", "usage: `/task status"},
+		{"status 1 done", "unknown status 'done'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := runSlash(slash, tt.text)
+			var businessErr models.BusinessError
+			if !errors.As(err, &businessErr) || !strings.Contains(businessErr.Message, tt.message) {
+				t.Errorf("Expected a business error containing %q, got %v", tt.message, err)
+			}
+		})
+	}
+
+	if _, err := runSlash(slash, "delete 42"); !errors.As(err, &models.TaskNotFoundError{}) {
+		t.Errorf("Expected task not found, got %v", err)
+	}
+
+	response, err := runSlash(slash, "")
+	if err != nil || response.Text != "Task commands" {
+		t.Errorf("Expected help for empty text, got %+v (%v)", response, err)
+	}
+}
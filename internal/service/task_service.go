@@ -1,51 +1,158 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/AashishRichhariya/task-management-api/internal/models"
 	"github.com/AashishRichhariya/task-management-api/internal/repository"
 )
 
 type TaskService struct {
-	taskRepo repository.TaskRepository
+	taskRepo      repository.TaskRepository
+	fieldRepo     repository.CustomFieldRepository
+	rebalancer    *RankRebalancer
+	eventHandlers []TaskEventHandler
+	userRepo      repository.UserRepository
+	mentionRepo   repository.MentionRepository
+	automation    *AutomationEngine
+	txManager     repository.TxManager
+}
+
+// Receives every change made through TaskService, after it has been stored
+type TaskEventHandler interface {
+	HandleTaskEvent(event models.TaskEvent) error
 }
 
 type TaskServiceInterface interface {
-	CreateTask(title, description, status string) (*models.Task, error)
-	GetTaskByID(id int) (*models.Task, error)
-	GetAllTasks(page, limit int, status, sortBy, sortOrder string) (*models.PaginatedTasksResponse, error)
-	UpdateTask(id int, title, description, status string) (*models.Task, error)
-	DeleteTask(id int) error
+	CreateTask(ctx context.Context, title, description, status string, customFields map[string]any) (*models.Task, error)
+	GetTaskByID(ctx context.Context, id int) (*models.Task, error)
+	GetAllTasks(ctx context.Context, page, limit int, status, sortBy, sortOrder string, customFields map[string]string) (*models.PaginatedTasksResponse, error)
+	UpdateTask(ctx context.Context, id int, title, description, status string, customFields map[string]any) (*models.Task, error)
+	DeleteTask(ctx context.Context, id int) error
+	MoveTask(ctx context.Context, id, beforeID, afterID int, status string) (*models.Task, error)
+}
+
+// Optional dependencies for TaskService
+type TaskServiceOption func(*TaskService)
+
+// Enables custom field values, validated against the definitions in fieldRepo
+func WithCustomFields(fieldRepo repository.CustomFieldRepository) TaskServiceOption {
+	return func(s *TaskService) {
+		s.fieldRepo = fieldRepo
+	}
 }
 
+// Schedules background rank rebalancing when a column's ranks grow too long
+func WithRankRebalancer(rebalancer *RankRebalancer) TaskServiceOption {
+	return func(s *TaskService) {
+		s.rebalancer = rebalancer
+	}
+}
+
+// Registers handlers that are told about every task change
+func WithEventHandlers(handlers ...TaskEventHandler) TaskServiceOption {
+	return func(s *TaskService) {
+		s.eventHandlers = append(s.eventHandlers, handlers...)
+	}
+}
 
-func NewTaskService(taskRepo repository.TaskRepository) TaskServiceInterface {
-	return &TaskService{
+// Records @username mentions in task descriptions for the mentioned users
+func WithMentions(userRepo repository.UserRepository, mentionRepo repository.MentionRepository) TaskServiceOption {
+	return func(s *TaskService) {
+		s.userRepo = userRepo
+		s.mentionRepo = mentionRepo
+	}
+}
+
+// Evaluates automation rules after every change, running their actions through this service
+func WithAutomation(engine *AutomationEngine) TaskServiceOption {
+	return func(s *TaskService) {
+		s.automation = engine
+		engine.taskService = s
+	}
+}
+
+// Reads and writes each create, update, move and delete in one transaction, retried when it
+// conflicts with a concurrent one
+func WithTransactions(txManager repository.TxManager) TaskServiceOption {
+	return func(s *TaskService) {
+		s.txManager = txManager
+	}
+}
+
+func NewTaskService(taskRepo repository.TaskRepository, opts ...TaskServiceOption) TaskServiceInterface {
+	s := &TaskService{
 		taskRepo: taskRepo,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func (s *TaskService) CreateTask(title, description, status string) (*models.Task, error) {
-	// Create task model
-	task := &models.Task{
-		Title:       strings.TrimSpace(title),
-		Description: strings.TrimSpace(description),
-		Status:      models.TaskStatus(status),
+func (s *TaskService) CreateTask(ctx context.Context, title, description, status string, customFields map[string]any) (*models.Task, error) {
+	return s.createTask(ctx, title, description, status, customFields, nil)
+}
+
+// Creates a task as part of chain, which is nil unless an automation rule made the change
+func (s *TaskService) createTask(ctx context.Context, title, description, status string, customFields map[string]any, chain *automationChain) (*models.Task, error) {
+	template, err := s.newTask(ctx, title, description, status, customFields)
+	if err != nil {
+		return nil, err
+	}
+	mentioned, err := s.resolveMentions(ctx, template.Description)
+	if err != nil {
+		return nil, err
 	}
 	
-	// Delegate to repository
-	err := s.taskRepo.CreateTask(task)
+	var task *models.Task
+	err = s.unitOfWork(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		// A retried attempt starts again from the template, without the rank the last one gave it
+		candidate := *template
+		task = &candidate
+		if err := repos.Tasks.CreateTask(ctx, task); err != nil {
+			return err
+		}
+		return s.recordMentions(repos, mentioned, *task, "")
+	})
 	if err != nil {
 		return nil, err
 	}
 	
+	s.emit(ctx, models.EventTaskCreated, *task, nil, chain)
 	return task, nil
 }
 
-func (s *TaskService) GetTaskByID(id int) (*models.Task, error) {
-	task, err := s.taskRepo.GetTaskByID(id)
+// Builds a task to be created, validating its custom field values
+func (s *TaskService) newTask(ctx context.Context, title, description, status string, customFields map[string]any) (*models.Task, error) {
+	definitions, err := loadCustomFieldDefinitions(ctx, s.fieldRepo)
+	if err != nil {
+		return nil, err
+	}
+	values, err := applyCustomFieldValues(definitions, nil, customFields, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create task model
+	return &models.Task{
+		Title:        strings.TrimSpace(title),
+		Description:  strings.TrimSpace(description),
+		Status:       models.TaskStatus(status),
+		CustomFields: values,
+	}, nil
+}
+
+func (s *TaskService) GetTaskByID(ctx context.Context, id int) (*models.Task, error) {
+	return findTask(ctx, s.taskRepo, id)
+}
+
+func findTask(ctx context.Context, tasks repository.TaskRepository, id int) (*models.Task, error) {
+	task, err := tasks.GetTaskByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -57,67 +164,369 @@ func (s *TaskService) GetTaskByID(id int) (*models.Task, error) {
 	return task, nil
 }
 
-func (s *TaskService) GetAllTasks(page, limit int, status, sortBy, sortOrder string) (*models.PaginatedTasksResponse, error) {
-	// Get tasks from repository
-	tasks, totalCount, err := s.taskRepo.GetAllTasks(limit, page, status, sortBy, sortOrder)
+func (s *TaskService) GetAllTasks(ctx context.Context, page, limit int, status, sortBy, sortOrder string, customFields map[string]string) (*models.PaginatedTasksResponse, error) {
+	filters, err := s.resolveCustomFieldQuery(ctx, sortBy, customFields)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tasks: %w", err)
-	}
-
-	// Calculate pagination metadata
-	totalPages := (totalCount + limit - 1) / limit 
-	if totalPages == 0 {
-		totalPages = 1
+		return nil, err
 	}
 
-
-	pagination := models.PaginationMeta{
-		Page:    page,
-		Limit:   limit,
-		Total:   totalCount,
-		Pages:   totalPages,
-		HasNext: page < totalPages,
-		HasPrev: page > 1,
+	// Get tasks from repository
+	tasks, totalCount, err := s.taskRepo.GetAllTasks(ctx, limit, page, status, sortBy, sortOrder, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 
 	return &models.PaginatedTasksResponse{
 		Tasks:      tasks,
-		Pagination: pagination,
+		Pagination: paginationMeta(page, limit, totalCount),
 	}, nil
 }
 
-func (s *TaskService) UpdateTask(id int, title, description, status string) (*models.Task, error) {
-	existingTask, err := s.GetTaskByID(id)
+func (s *TaskService) UpdateTask(ctx context.Context, id int, title, description, status string, customFields map[string]any) (*models.Task, error) {
+	return s.updateTask(ctx, id, title, description, status, customFields, nil)
+}
+
+func (s *TaskService) updateTask(ctx context.Context, id int, title, description, status string, customFields map[string]any, chain *automationChain) (*models.Task, error) {
+	// Read before the transaction, which would otherwise hold its connection waiting for another
+	definitions, err := s.updateDefinitions(ctx, customFields)
+	if err != nil {
+		return nil, err
+	}
+	mentioned, err := s.resolveMentions(ctx, strings.TrimSpace(description))
+	if err != nil {
+		return nil, err
+	}
+
+	var existingTask *models.Task
+	var previous models.Task
+	err = s.unitOfWork(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		tasks := repos.Tasks
+		var err error
+		existingTask, err = findTask(ctx, tasks, id)
+		if err != nil {
+			return err
+		}
+		previous = *existingTask
+
+		if err := s.applyUpdate(existingTask, definitions, title, description, status, customFields); err != nil {
+			return err
+		}
+		if existingTask.Status != previous.Status {
+			// Changing column appends the task to the end of the new one
+			if err := s.assignRank(ctx, tasks, existingTask, "", ""); err != nil {
+				return err
+			}
+		}
+
+		// Update in repository
+		if err := tasks.UpdateTask(ctx, existingTask); err != nil {
+			return err
+		}
+		return s.recordMentions(repos, mentioned, *existingTask, previous.Description)
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	s.emit(ctx, models.ClassifyTaskChange(previous, *existingTask), *existingTask, &previous, chain)
+	return existingTask, nil
+}
+
+// Loads the custom field definitions an update of customFields is checked against
+func (s *TaskService) updateDefinitions(ctx context.Context, customFields map[string]any) (map[string]models.CustomFieldDefinition, error) {
+	if len(customFields) == 0 {
+		return nil, nil
+	}
+	return loadCustomFieldDefinitions(ctx, s.fieldRepo)
+}
+
+// Applies the non-empty fields of an update to task, checking custom field values against
+// definitions from updateDefinitions; the rank is left to the caller
+func (s *TaskService) applyUpdate(task *models.Task, definitions map[string]models.CustomFieldDefinition, title, description, status string, customFields map[string]any) error {
+	if len(customFields) > 0 {
+		var err error
+		task.CustomFields, err = applyCustomFieldValues(definitions, task.CustomFields, customFields, false)
+		if err != nil {
+			return err
+		}
+	}
+
 	if title != "" {
-		existingTask.Title = strings.TrimSpace(title)
+		task.Title = strings.TrimSpace(title)
 	}
 	if description != "" {
-		existingTask.Description = strings.TrimSpace(description)  
+		task.Description = strings.TrimSpace(description)
 	}
 	if status != "" {
-		existingTask.Status = models.TaskStatus(status)
+		task.Status = models.TaskStatus(status)
 	}
-	
-	// Update in repository
-	err = s.taskRepo.UpdateTask(existingTask)
+	return nil
+}
+
+// Places a task between two neighbors in its (optionally new) status column.
+// afterID is the task that should end up directly above it, beforeID the one directly below.
+func (s *TaskService) MoveTask(ctx context.Context, id, beforeID, afterID int, status string) (*models.Task, error) {
+	var task *models.Task
+	var previous models.Task
+	err := s.unitOfWork(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		tasks := repos.Tasks
+		var err error
+		task, err = findTask(ctx, tasks, id)
+		if err != nil {
+			return err
+		}
+		previous = *task
+
+		if beforeID == id || afterID == id {
+			return models.ValidationError{Field: "before/after", Message: "a task cannot be its own neighbor"}
+		}
+
+		if status != "" {
+			task.Status = models.TaskStatus(status)
+		}
+
+		prev, err := s.neighborRank(ctx, tasks, afterID, "after", task.Status)
+		if err != nil {
+			return err
+		}
+		next, err := s.neighborRank(ctx, tasks, beforeID, "before", task.Status)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case afterID != 0 && beforeID != 0:
+			if prev >= next {
+				return models.BusinessError{Message: fmt.Sprintf("task %d is not above task %d in column '%s'", afterID, beforeID, task.Status)}
+			}
+		case afterID != 0:
+			next, err = tasks.GetAdjacentRank(ctx, task.Status, prev, id, true)
+		case beforeID != 0:
+			prev, err = tasks.GetAdjacentRank(ctx, task.Status, next, id, false)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.assignRank(ctx, tasks, task, prev, next); err != nil {
+			return err
+		}
+
+		return tasks.UpdateTask(ctx, task)
+	})
 	if err != nil {
 		return nil, err
 	}
-	
-	return existingTask, nil
+
+	s.emit(ctx, models.ClassifyTaskChange(previous, *task), *task, &previous, nil)
+	return task, nil
 }
 
-func (s *TaskService) DeleteTask(id int) error {
-	// Check if task exists
-	_, err := s.GetTaskByID(id)
+func (s *TaskService) DeleteTask(ctx context.Context, id int) error {
+	var task *models.Task
+	err := s.unitOfWork(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		// Check if task exists
+		var err error
+		task, err = findTask(ctx, repos.Tasks, id)
+		if err != nil {
+			return err
+		}
+
+		// Delete from repository
+		return repos.Tasks.DeleteTask(ctx, id)
+	})
 	if err != nil {
-		return err 
+		return err
 	}
 	
-	// Delete from repository
-	return s.taskRepo.DeleteTask(id)
+	s.emit(ctx, models.EventTaskDeleted, *task, nil, nil)
+	return nil
+}
+
+// Runs fn against task and mention storage, as one transaction when the service has a
+// transaction manager. fn may then run more than once, so it must not have other side effects.
+func (s *TaskService) unitOfWork(ctx context.Context, fn func(ctx context.Context, repos repository.Repositories) error) error {
+	if s.txManager == nil {
+		return fn(ctx, repository.Repositories{Tasks: s.taskRepo, Mentions: s.mentionRepo})
+	}
+	return s.txManager.WithinTx(ctx, fn)
+}
+
+// Checks custom field filters and sorting against the definitions and converts filter values to their field types
+func (s *TaskService) resolveCustomFieldQuery(ctx context.Context, sortBy string, customFields map[string]string) (map[string]any, error) {
+	sortKey, sortsByField := models.CustomFieldSortKey(sortBy)
+	if len(customFields) == 0 && !sortsByField {
+		return nil, nil
+	}
+
+	definitions, err := loadCustomFieldDefinitions(ctx, s.fieldRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := definitions[sortKey]; sortsByField && !ok {
+		return nil, models.ValidationError{Field: "sort_by", Message: fmt.Sprintf("unknown custom field '%s'", sortKey)}
+	}
+
+	filters := make(map[string]any, len(customFields))
+	for key, raw := range customFields {
+		definition, ok := definitions[key]
+		if !ok {
+			return nil, models.ValidationError{Field: models.CustomFieldQueryPrefix + key, Message: "unknown custom field"}
+		}
+		value, err := parseCustomFieldFilter(definition, raw)
+		if err != nil {
+			return nil, err
+		}
+		filters[key] = value
+	}
+
+	return filters, nil
+}
+
+// Returns the rank of a neighbor task, checking that it lives in the target column
+func (s *TaskService) neighborRank(ctx context.Context, tasks repository.TaskRepository, neighborID int, field string, status models.TaskStatus) (string, error) {
+	if neighborID == 0 {
+		return "", nil
+	}
+
+	neighbor, err := findTask(ctx, tasks, neighborID)
+	if err != nil {
+		return "", err
+	}
+	if neighbor.Status != status {
+		return "", models.ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("task %d is in column '%s', not '%s'", neighborID, neighbor.Status, status),
+		}
+	}
+	return neighbor.Rank, nil
+}
+
+// Gives the task a rank between prev and next; with neither it goes to the end of its column
+func (s *TaskService) assignRank(ctx context.Context, tasks repository.TaskRepository, task *models.Task, prev, next string) error {
+	if prev == "" && next == "" {
+		last, err := tasks.GetLastRank(ctx, task.Status, task.ID)
+		if err != nil {
+			return err
+		}
+		prev = last
+	}
+
+	rank, err := models.RankBetween(prev, next)
+	if err != nil {
+		return err
+	}
+
+	if models.RankNeedsRebalance(rank) {
+		s.rebalancer.Request(task.Status)
+	}
+	if models.RankExhausted(rank) {
+		return models.RankExhaustedError{Status: task.Status}
+	}
+
+	task.Rank = rank
+	return nil
+}
+
+// Looks up the known users mentioned in a description, keyed by lowercased username.
+// Done before the unit of work, which only has the task's own storage to itself.
+func (s *TaskService) resolveMentions(ctx context.Context, description string) (map[string]models.User, error) {
+	if s.mentionRepo == nil {
+		return nil, nil
+	}
+
+	handles := models.ParseMentions(description)
+	if len(handles) == 0 {
+		return nil, nil
+	}
+
+	users, err := s.userRepo.GetUsersByUsernames(ctx, handles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve mentions: %w", err)
+	}
+	mentioned := make(map[string]models.User, len(users))
+	for _, user := range users {
+		mentioned[strings.ToLower(user.Username)] = user
+	}
+	return mentioned, nil
+}
+
+// Records mentions of the users from resolveMentions that were added to the task's
+// description, in the unit of work that stores the task, so a change is never kept
+// without its mentions. Handles that match no user are left as plain text.
+func (s *TaskService) recordMentions(repos repository.Repositories, mentioned map[string]models.User, task models.Task, previousDescription string) error {
+	if len(mentioned) == 0 {
+		return nil
+	}
+
+	mentions := []models.Mention{}
+	for _, handle := range models.NewMentions(previousDescription, task.Description) {
+		user, ok := mentioned[handle]
+		if !ok {
+			continue
+		}
+		mentions = append(mentions, models.Mention{
+			UserID:  user.ID,
+			TaskID:  task.ID,
+			Source:  models.MentionSourceDescription,
+			Excerpt: models.MentionExcerpt(task.Description, handle),
+		})
+	}
+
+	return repos.Mentions.CreateMentions(mentions)
+}
+
+// Tells every event handler about a stored change, then runs the automation rules it
+// triggers. The change has already been made, so failures are logged rather than
+// returned to the caller, and the actions outlive a cancelled request.
+func (s *TaskService) emit(ctx context.Context, eventType models.TaskEventType, task models.Task, previous *models.Task, chain *automationChain) {
+	if len(s.eventHandlers) == 0 && s.automation == nil {
+		return
+	}
+
+	event := newTaskEvent(eventType, task, previous)
+	if previous != nil && len(event.Changes) == 0 {
+		return // Nothing visible changed
+	}
+
+	for _, handler := range s.eventHandlers {
+		if err := handler.HandleTaskEvent(event); err != nil {
+			log.Printf("Task event handler failed for %s on task %d: %v", eventType, task.ID, err)
+		}
+	}
+
+	if s.automation != nil {
+		s.automation.run(context.WithoutCancel(ctx), event, chain)
+	}
+}
+
+func newTaskEvent(eventType models.TaskEventType, task models.Task, previous *models.Task) models.TaskEvent {
+	event := models.TaskEvent{
+		Type:       eventType,
+		Task:       task,
+		Previous:   previous,
+		OccurredAt: time.Now(),
+	}
+	if previous != nil {
+		event.Changes = models.DiffTasks(*previous, task)
+	}
+	return event
+}
+
+func paginationMeta(page, limit, totalCount int) models.PaginationMeta {
+	// Calculate pagination metadata
+	totalPages := (totalCount + limit - 1) / limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return models.PaginationMeta{
+		Page:    page,
+		Limit:   limit,
+		Total:   totalCount,
+		Pages:   totalPages,
+		HasNext: page < totalPages,
+		HasPrev: page > 1,
+	}
 }
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"testing"
 
 	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
 )
 
 // Test functions
@@ -13,7 +15,7 @@ func TestTaskService_CreateTask(t *testing.T) {
 	service := NewTaskService(mockRepo)
 	
 	// Test valid task creation
-	task, err := service.CreateTask("Test Task", "Description", "pending")
+	task, err := service.CreateTask(context.Background(), "Test Task", "Description", "pending", nil)
 	if err != nil {
 		t.Fatalf("CreateTask failed: %v", err)
 	}
@@ -32,10 +34,10 @@ func TestTaskService_GetTaskByID(t *testing.T) {
 	service := NewTaskService(mockRepo)
 	
 	// Create a task first
-	createdTask, _ := service.CreateTask("Test", "Description", "pending")
+	createdTask, _ := service.CreateTask(context.Background(), "Test", "Description", "pending", nil)
 	
 	// Get the task
-	retrievedTask, err := service.GetTaskByID(createdTask.ID)
+	retrievedTask, err := service.GetTaskByID(context.Background(), createdTask.ID)
 	if err != nil {
 		t.Fatalf("GetTaskByID failed: %v", err)
 	}
@@ -50,7 +52,7 @@ func TestTaskService_GetTaskByID_NotFound(t *testing.T) {
 	service := NewTaskService(mockRepo)
 	
 	// Test non-existent task
-	_, err := service.GetTaskByID(999)
+	_, err := service.GetTaskByID(context.Background(), 999)
 	if err == nil {
 		t.Error("Expected TaskNotFoundError")
 	}
@@ -66,10 +68,10 @@ func TestTaskService_UpdateTask_Success(t *testing.T) {
 	service := NewTaskService(mockRepo)
 	
 	// Create a task first
-	task, _ := service.CreateTask("Original", "Description", "pending")
+	task, _ := service.CreateTask(context.Background(), "Original", "Description", "pending", nil)
 	
 	// Update the task
-	updatedTask, err := service.UpdateTask(task.ID, "Updated Title", "Updated Description", "in_progress")
+	updatedTask, err := service.UpdateTask(context.Background(), task.ID, "Updated Title", "Updated Description", "in_progress", nil)
 	if err != nil {
 		t.Fatalf("UpdateTask failed: %v", err)
 	}
@@ -88,16 +90,16 @@ func TestTaskService_DeleteTask_Success(t *testing.T) {
 	service := NewTaskService(mockRepo)
 	
 	// Create a task first
-	task, _ := service.CreateTask("To Delete", "Description", "pending")
+	task, _ := service.CreateTask(context.Background(), "To Delete", "Description", "pending", nil)
 	
 	// Delete the task
-	err := service.DeleteTask(task.ID)
+	err := service.DeleteTask(context.Background(), task.ID)
 	if err != nil {
 		t.Fatalf("DeleteTask failed: %v", err)
 	}
 	
 	// Verify task is gone
-	_, err = service.GetTaskByID(task.ID)
+	_, err = service.GetTaskByID(context.Background(), task.ID)
 	if err == nil {
 		t.Error("Expected TaskNotFoundError after deletion")
 	}
@@ -108,7 +110,7 @@ func TestTaskService_DeleteTask_NotFound(t *testing.T) {
 	service := NewTaskService(mockRepo)
 	
 	// Try to delete non-existent task
-	err := service.DeleteTask(999)
+	err := service.DeleteTask(context.Background(), 999)
 	if err == nil {
 		t.Error("Expected TaskNotFoundError")
 	}
@@ -123,12 +125,12 @@ func TestTaskService_GetAllTasks(t *testing.T) {
 	service := NewTaskService(mockRepo)
 	
 	// Create multiple tasks
-	service.CreateTask("Task 1", "", "pending")
-	service.CreateTask("Task 2", "", "completed")
-	service.CreateTask("Task 3", "", "in_progress")
+	service.CreateTask(context.Background(), "Task 1", "", "pending", nil)
+	service.CreateTask(context.Background(), "Task 2", "", "completed", nil)
+	service.CreateTask(context.Background(), "Task 3", "", "in_progress", nil)
 	
 	// Get all tasks
-	response, err := service.GetAllTasks(1, 10, "", "", "")
+	response, err := service.GetAllTasks(context.Background(), 1, 10, "", "created_at", "desc", nil)
 	if err != nil {
 		t.Fatalf("GetAllTasks failed: %v", err)
 	}
@@ -136,4 +138,129 @@ func TestTaskService_GetAllTasks(t *testing.T) {
 	if len(response.Tasks) != 3 {
 		t.Errorf("Expected 3 tasks, got %d", len(response.Tasks))
 	}
-}
\ No newline at end of file
+}
+
+func TestTaskService_MoveTask(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo)
+
+	first, _ := service.CreateTask(context.Background(), "First", "", "pending", nil)
+	second, _ := service.CreateTask(context.Background(), "Second", "", "pending", nil)
+	third, _ := service.CreateTask(context.Background(), "Third", "", "pending", nil)
+
+	// Move third between first and second
+	moved, err := service.MoveTask(context.Background(), third.ID, second.ID, first.ID, "")
+	if err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+
+	if !(first.Rank < moved.Rank && moved.Rank < second.Rank) {
+		t.Errorf("Expected rank between %s and %s, got %s", first.Rank, second.Rank, moved.Rank)
+	}
+
+	// Move first before third using only one neighbor
+	moved, err = service.MoveTask(context.Background(), first.ID, third.ID, 0, "")
+	if err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+
+	third, _ = service.GetTaskByID(context.Background(), third.ID)
+	if moved.Rank >= third.Rank {
+		t.Errorf("Expected %s to sort before %s", moved.Rank, third.Rank)
+	}
+}
+
+func TestTaskService_MoveTask_ChangesStatus(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	service := NewTaskService(mockRepo)
+
+	task, _ := service.CreateTask(context.Background(), "Task", "", "pending", nil)
+	done, _ := service.CreateTask(context.Background(), "Done", "", "completed", nil)
+
+	// Neighbor must be in the target column
+	if _, err := service.MoveTask(context.Background(), task.ID, done.ID, 0, "in_progress"); err == nil {
+		t.Error("Expected error for neighbor in another column")
+	}
+
+	moved, err := service.MoveTask(context.Background(), task.ID, done.ID, 0, "completed")
+	if err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+
+	if moved.Status != models.StatusCompleted {
+		t.Errorf("Expected status completed, got %s", moved.Status)
+	}
+
+	if moved.Rank >= done.Rank {
+		t.Errorf("Expected %s to sort before %s", moved.Rank, done.Rank)
+	}
+}
+
+func TestTaskService_MoveTask_RequestsRebalance(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	rebalancer := NewRankRebalancer(mockRepo)
+	service := NewTaskService(mockRepo, WithRankRebalancer(rebalancer))
+
+	top, _ := service.CreateTask(context.Background(), "Top", "", "pending", nil)
+	bottom, _ := service.CreateTask(context.Background(), "Bottom", "", "pending", nil)
+
+	// Repeatedly inserting directly below the top task grows the rank until a rebalance is needed
+	for i := 0; i < 200 && len(rebalancer.requests) == 0; i++ {
+		task, _ := service.CreateTask(context.Background(), "Filler", "", "pending", nil)
+		if _, err := service.MoveTask(context.Background(), task.ID, bottom.ID, top.ID, ""); err != nil {
+			t.Fatalf("MoveTask failed: %v", err)
+		}
+		bottom = task
+	}
+
+	if len(rebalancer.requests) != 1 {
+		t.Errorf("Expected one queued rebalance request, got %d", len(rebalancer.requests))
+	}
+}
+
+func TestTaskService_Transactions(t *testing.T) {
+	mockRepo := newMockTaskRepository()
+	txManager := &mockTxManager{tasks: mockRepo.(*repository.MemoryTaskRepository)}
+	events := &recordingSink{}
+	service := NewTaskService(mockRepo, WithTransactions(txManager), WithEventHandlers(events))
+
+	task, _ := service.CreateTask(context.Background(), "Original", "", "pending", nil)
+	other, _ := service.CreateTask(context.Background(), "Other", "", "pending", nil)
+	if txManager.attempts != 2 {
+		t.Errorf("Expected each create to run as a unit of work, got %d attempts", txManager.attempts)
+	}
+	txManager.attempts = 0
+
+	// A retried update starts again from the stored task and is reported once
+	txManager.retries = 1
+	updated, err := service.UpdateTask(context.Background(), task.ID, "Updated", "", "in_progress", nil)
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if txManager.attempts != 2 {
+		t.Errorf("Expected the update to run twice, got %d attempts", txManager.attempts)
+	}
+	if updated.Title != "Updated" || updated.Status != models.StatusInProgress {
+		t.Errorf("Unexpected updated task %+v", updated)
+	}
+	last := events.events[len(events.events)-1]
+	if len(events.events) != 3 || last.Previous == nil || last.Previous.Status != models.StatusPending {
+		t.Errorf("Expected one status change from pending, got %+v", events.events)
+	}
+
+	// Moves and deletes run as units of work too
+	if _, err := service.MoveTask(context.Background(), other.ID, 0, 0, "in_progress"); err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+	if err := service.DeleteTask(context.Background(), other.ID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if txManager.attempts != 4 {
+		t.Errorf("Expected 4 attempts in all, got %d", txManager.attempts)
+	}
+
+	// Failures inside the unit of work reach the caller
+	if err := service.DeleteTask(context.Background(), other.ID); err == nil {
+		t.Error("Expected TaskNotFoundError for a deleted task")
+	}
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+const (
+	taskStreamBufferSize = 64
+	taskStreamReplayPage = 500
+	taskStreamQueueSize  = 1024
+
+	// Outbox IDs are assigned on insert, not commit, so a message can commit after others
+	// with higher IDs while the transaction writing it is still open. Catching up after a
+	// message therefore also looks at those created this long before it.
+	taskStreamLookback = time.Minute
+)
+
+// TaskStreamBroker fans task events out to the stream subscribers of this instance.
+// Events come from the outbox, which every instance sees, so a change made on any
+// replica reaches subscribers on all of them. Event IDs are outbox message IDs; they
+// do not follow commit order, so events can arrive out of ID order, and one replayed
+// after a reconnect may have been seen before. Notifications are handled on the
+// broker's own goroutine, so a slow outbox query never holds up the notification bus.
+type TaskStreamBroker struct {
+	outboxRepo repository.OutboxRepository
+	notified   chan int64    // IDs passed to Notify, waiting to be loaded
+	resyncs    chan struct{} // Pending Resync, at most one
+	done       chan struct{}
+	stopped    chan struct{}
+
+	mu          sync.Mutex
+	subscribers map[*TaskStreamSubscription]struct{}
+	lastID      int64
+	sent        map[int64]time.Time // Messages broadcast within the lookback, which are not sent again
+	pruned      time.Time
+	started     bool
+	closed      bool
+}
+
+// A live feed of events matching a filter. Closed is closed when the subscriber fell too
+// far behind and was dropped, or the server is shutting down; it should reconnect and
+// resume from the last event it saw.
+type TaskStreamSubscription struct {
+	Events chan models.TaskEvent
+	Closed chan struct{}
+	filter models.TaskEventFilter
+}
+
+func NewTaskStreamBroker(outboxRepo repository.OutboxRepository) *TaskStreamBroker {
+	return &TaskStreamBroker{
+		outboxRepo:  outboxRepo,
+		notified:    make(chan int64, taskStreamQueueSize),
+		resyncs:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+		subscribers: make(map[*TaskStreamSubscription]struct{}),
+		sent:        make(map[int64]time.Time),
+	}
+}
+
+// Records where the outbox currently ends, so a resync after the listener reconnects
+// knows where to catch up from, and starts handling notifications. Call before starting
+// the listener.
+func (b *TaskStreamBroker) Start() error {
+	latest, err := b.outboxRepo.GetLatestMessageID()
+	if err != nil {
+		return fmt.Errorf("failed to read the end of the outbox: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID = max(b.lastID, latest)
+	if !b.started && !b.closed {
+		b.started = true
+		go b.run()
+	}
+	return nil
+}
+
+func (b *TaskStreamBroker) Subscribe(filter models.TaskEventFilter) *TaskStreamSubscription {
+	subscription := &TaskStreamSubscription{
+		Events: make(chan models.TaskEvent, taskStreamBufferSize),
+		Closed: make(chan struct{}),
+		filter: filter,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(subscription.Closed)
+		return subscription
+	}
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// Ends every subscription, and any made later, so streams let the server shut down,
+// and stops handling notifications
+func (b *TaskStreamBroker) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for subscription := range b.subscribers {
+		b.drop(subscription)
+	}
+	started := b.started
+	b.mu.Unlock()
+
+	close(b.done)
+	if started {
+		<-b.stopped
+	}
+}
+
+// Whether Close has been called, for subscribers telling shutdown from being dropped
+func (b *TaskStreamBroker) Closing() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+func (b *TaskStreamBroker) Unsubscribe(subscription *TaskStreamSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.drop(subscription)
+}
+
+// Returns the recorded events after afterID that match the filter, in ID order, for a
+// subscriber resuming a stream. Events created shortly before afterID are sent again, as
+// they may have committed after it; subscribers skip IDs they have already seen. Only
+// events still retained in the outbox can be replayed.
+func (b *TaskStreamBroker) Replay(afterID int64, filter models.TaskEventFilter, send func(models.TaskEvent) error) error {
+	from, err := b.outboxRepo.GetLookbackStart(afterID, taskStreamLookback)
+	if err != nil {
+		return fmt.Errorf("failed to replay task events: %w", err)
+	}
+
+	return b.eachMessageFrom(from, func(message models.OutboxMessage) error {
+		if message.ID == afterID {
+			return nil
+		}
+		event, err := message.Event()
+		if err != nil {
+			log.Printf("Skipping undecodable outbox message %d: %v", message.ID, err)
+			return nil
+		}
+		if !filter.Matches(event) {
+			return nil
+		}
+		return send(event)
+	})
+}
+
+// Handles a notification that an outbox message was committed; the payload is its ID.
+// The message is loaded and broadcast on the broker's goroutine.
+func (b *TaskStreamBroker) Notify(payload string) {
+	id, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		log.Printf("Ignoring malformed task event notification %q", payload)
+		return
+	}
+
+	select {
+	case b.notified <- id:
+	default:
+		// Too far behind to queue it; catching up from the outbox finds it
+		b.Resync()
+	}
+}
+
+// Catches up on messages that may have been missed while notifications were not being
+// received, such as after the listener reconnects. Runs on the broker's goroutine.
+func (b *TaskStreamBroker) Resync() {
+	select {
+	case b.resyncs <- struct{}{}:
+	default:
+	}
+}
+
+func (b *TaskStreamBroker) run() {
+	defer close(b.stopped)
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-b.resyncs:
+			b.catchUp()
+		case id := <-b.notified:
+			b.load(append(b.queuedIDs(), id))
+		}
+	}
+}
+
+// Takes the IDs already waiting behind one just received, so they load in one query
+func (b *TaskStreamBroker) queuedIDs() []int64 {
+	var ids []int64
+	for len(ids) < taskStreamReplayPage-1 {
+		select {
+		case id := <-b.notified:
+			ids = append(ids, id)
+		default:
+			return ids
+		}
+	}
+	return ids
+}
+
+func (b *TaskStreamBroker) load(ids []int64) {
+	messages, err := b.outboxRepo.GetMessagesByIDs(ids)
+	if err != nil {
+		log.Printf("Failed to load task events %v: %v", ids, err)
+		return
+	}
+	b.broadcast(messages)
+}
+
+func (b *TaskStreamBroker) catchUp() {
+	b.mu.Lock()
+	afterID := b.lastID
+	b.mu.Unlock()
+
+	from, err := b.outboxRepo.GetLookbackStart(afterID, taskStreamLookback)
+	if err != nil {
+		log.Printf("Failed to resync task events: %v", err)
+		return
+	}
+	err = b.eachPageFrom(from, func(messages []models.OutboxMessage) error {
+		b.broadcast(slices.DeleteFunc(messages, func(message models.OutboxMessage) bool {
+			return message.ID == afterID
+		}))
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to resync task events: %v", err)
+	}
+}
+
+// Hands each message from ID from onwards to fn, in ID order
+func (b *TaskStreamBroker) eachMessageFrom(from int64, fn func(models.OutboxMessage) error) error {
+	return b.eachPageFrom(from, func(messages []models.OutboxMessage) error {
+		for _, message := range messages {
+			if err := fn(message); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *TaskStreamBroker) eachPageFrom(from int64, fn func([]models.OutboxMessage) error) error {
+	afterID := from - 1
+	for {
+		messages, err := b.outboxRepo.GetMessagesAfter(afterID, taskStreamReplayPage)
+		if err != nil {
+			return fmt.Errorf("failed to read task events: %w", err)
+		}
+		if err := fn(messages); err != nil {
+			return err
+		}
+		if len(messages) < taskStreamReplayPage {
+			return nil
+		}
+		afterID = messages[len(messages)-1].ID
+	}
+}
+
+func (b *TaskStreamBroker) broadcast(messages []models.OutboxMessage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(b.pruned) > taskStreamLookback {
+		b.pruneSent(now)
+	}
+
+	for _, message := range messages {
+		if _, ok := b.sent[message.ID]; ok {
+			continue
+		}
+		b.sent[message.ID] = now
+		b.lastID = max(b.lastID, message.ID)
+
+		event, err := message.Event()
+		if err != nil {
+			log.Printf("Skipping undecodable outbox message %d: %v", message.ID, err)
+			continue
+		}
+
+		for subscription := range b.subscribers {
+			if !subscription.filter.Matches(event) {
+				continue
+			}
+			select {
+			case subscription.Events <- event:
+			default:
+				// The subscriber cannot keep up; drop it rather than block everyone else.
+				// It resumes from its last event when it reconnects.
+				b.drop(subscription)
+			}
+		}
+	}
+}
+
+// Must be called with mu held
+func (b *TaskStreamBroker) drop(subscription *TaskStreamSubscription) {
+	if _, ok := b.subscribers[subscription]; ok {
+		delete(b.subscribers, subscription)
+		close(subscription.Closed)
+	}
+}
+
+// Forgets messages broadcast long enough ago that catching up no longer looks at them.
+// Must be called with mu held.
+func (b *TaskStreamBroker) pruneSent(now time.Time) {
+	for id, sentAt := range b.sent {
+		if now.Sub(sentAt) > 2*taskStreamLookback {
+			delete(b.sent, id)
+		}
+	}
+	b.pruned = now
+}
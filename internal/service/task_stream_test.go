@@ -0,0 +1,172 @@
+package service
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+func TestTaskStreamBroker_FiltersAndBroadcasts(t *testing.T) {
+	outboxRepo := newMockOutboxRepository()
+	broker := NewTaskStreamBroker(outboxRepo)
+	if err := broker.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer broker.Close()
+
+	all := broker.Subscribe(models.TaskEventFilter{})
+	completed := broker.Subscribe(models.TaskEventFilter{Status: models.StatusCompleted})
+	defer broker.Unsubscribe(all)
+	defer broker.Unsubscribe(completed)
+
+	pending := models.Task{ID: 1, Status: models.StatusPending}
+	done := models.Task{ID: 1, Status: models.StatusCompleted}
+	outboxRepo.add(models.TaskEvent{Type: models.EventTaskCreated, Task: pending})
+	outboxRepo.add(models.TaskEvent{Type: models.EventTaskStatusChanged, Task: done, Previous: &pending})
+
+	broker.Notify("1")
+	broker.Notify("2")
+	broker.Notify("not-an-id") // Ignored
+	broker.Notify("2")         // Already sent
+
+	if events := receiveEvents(t, all, 2); events[0].ID != 1 || events[1].ID != 2 {
+		t.Errorf("Expected events 1 and 2 for the unfiltered subscriber, got %+v", events)
+	}
+	if events := receiveEvents(t, completed, 1); events[0].ID != 2 || events[0].Type != models.EventTaskStatusChanged {
+		t.Errorf("Unexpected event: %+v", events[0])
+	}
+}
+
+func TestTaskStreamBroker_ReplayAndResync(t *testing.T) {
+	outboxRepo := newMockOutboxRepository()
+	broker := NewTaskStreamBroker(outboxRepo)
+
+	for i := 1; i <= 3; i++ {
+		outboxRepo.add(models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: i, Status: models.StatusPending}})
+	}
+	// Long enough ago that they committed before message 3
+	outboxRepo.messages[0].CreatedAt = time.Now().Add(-2 * taskStreamLookback)
+	outboxRepo.messages[1].CreatedAt = time.Now().Add(-2 * taskStreamLookback)
+	if err := broker.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	var replayed []int64
+	err := broker.Replay(1, models.TaskEventFilter{}, func(event models.TaskEvent) error {
+		replayed = append(replayed, event.ID)
+		return nil
+	})
+	if err != nil || len(replayed) != 2 || replayed[0] != 2 || replayed[1] != 3 {
+		t.Fatalf("Expected events 2 and 3 to be replayed, got %v (%v)", replayed, err)
+	}
+
+	// Messages committed while the listener was disconnected arrive on resync
+	subscription := broker.Subscribe(models.TaskEventFilter{})
+	defer broker.Unsubscribe(subscription)
+	outboxRepo.add(models.TaskEvent{Type: models.EventTaskDeleted, Task: models.Task{ID: 1}})
+	broker.Resync()
+
+	if events := receiveEvents(t, subscription, 1); events[0].ID != 4 {
+		t.Errorf("Expected event 4, got %d", events[0].ID)
+	}
+}
+
+func TestTaskStreamBroker_CatchesUpOnEventsCommittedOutOfOrder(t *testing.T) {
+	outboxRepo := newMockOutboxRepository()
+	broker := NewTaskStreamBroker(outboxRepo)
+	if err := broker.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer broker.Close()
+	subscription := broker.Subscribe(models.TaskEventFilter{})
+	defer broker.Unsubscribe(subscription)
+
+	// Message 1 was inserted first but its transaction committed after message 2's
+	for i := 1; i <= 2; i++ {
+		outboxRepo.add(models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: i}})
+	}
+	broker.Notify("2")
+	receiveEvents(t, subscription, 1)
+
+	// A client that saw only message 2 gets message 1 when it resumes after it
+	var replayed []int64
+	err := broker.Replay(2, models.TaskEventFilter{}, func(event models.TaskEvent) error {
+		replayed = append(replayed, event.ID)
+		return nil
+	})
+	if err != nil || len(replayed) != 1 || replayed[0] != 1 {
+		t.Errorf("Expected event 1 to be replayed, got %v (%v)", replayed, err)
+	}
+
+	// and so do subscribers when the listener missed its notification
+	broker.Resync()
+	if events := receiveEvents(t, subscription, 1); events[0].ID != 1 {
+		t.Errorf("Expected event 1 on resync, got %d", events[0].ID)
+	}
+}
+
+// Waits for the next n events of a subscription
+func receiveEvents(t *testing.T, subscription *TaskStreamSubscription, n int) []models.TaskEvent {
+	t.Helper()
+	events := make([]models.TaskEvent, 0, n)
+	for len(events) < n {
+		select {
+		case event := <-subscription.Events:
+			events = append(events, event)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %d events, got %d", n, len(events))
+		}
+	}
+	select {
+	case event := <-subscription.Events:
+		t.Fatalf("Expected only %d events, also got %+v", n, event)
+	case <-time.After(20 * time.Millisecond):
+	}
+	return events
+}
+
+func TestTaskStreamBroker_DropsSlowSubscribers(t *testing.T) {
+	outboxRepo := newMockOutboxRepository()
+	broker := NewTaskStreamBroker(outboxRepo)
+	subscription := broker.Subscribe(models.TaskEventFilter{})
+
+	for i := 1; i <= taskStreamBufferSize+1; i++ {
+		outboxRepo.add(models.TaskEvent{Type: models.EventTaskCreated, Task: models.Task{ID: i}})
+		broker.Notify(strconv.Itoa(i))
+	}
+	if err := broker.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer broker.Close()
+
+	select {
+	case <-subscription.Closed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a subscriber with a full buffer to be dropped")
+	}
+
+	// Unsubscribing a dropped subscriber is harmless
+	broker.Unsubscribe(subscription)
+}
+
+func TestTaskStreamBroker_CloseEndsSubscriptions(t *testing.T) {
+	broker := NewTaskStreamBroker(newMockOutboxRepository())
+	before := broker.Subscribe(models.TaskEventFilter{})
+
+	broker.Close()
+	after := broker.Subscribe(models.TaskEventFilter{})
+
+	for _, subscription := range []*TaskStreamSubscription{before, after} {
+		select {
+		case <-subscription.Closed:
+		default:
+			t.Fatal("Expected subscriptions to be closed once the broker is")
+		}
+		broker.Unsubscribe(subscription)
+	}
+	if !broker.Closing() {
+		t.Error("Expected the broker to report that it is closing")
+	}
+}
@@ -1,115 +1,741 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/AashishRichhariya/task-management-api/internal/models"
 	"github.com/AashishRichhariya/task-management-api/internal/repository"
 )
 
-// Mock repository implementation
-type mockTaskRepository struct {
-	tasks  map[int]*models.Task
-	nextID int
+// Runs each unit of work against the in-memory task repository, running it a second
+// time after the first retries units to mimic serialization failures. A retried
+// attempt's writes are rolled back first.
+type mockTxManager struct {
+	tasks    *repository.MemoryTaskRepository
+	mentions *mockMentionRepository
+	retries  int
+	attempts int
+	active   bool // Whether a unit of work is running
+}
+
+func (m *mockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context, repos repository.Repositories) error) error {
+	repos := repository.Repositories{Tasks: m.tasks}
+	saved := m.tasks.Snapshot()
+	var savedMentions int
+	if m.mentions != nil {
+		repos.Mentions = m.mentions
+		savedMentions = len(m.mentions.mentions)
+	}
+
+	m.active = true
+	defer func() { m.active = false }()
+
+	m.attempts++
+	err := fn(ctx, repos)
+	if m.retries > 0 {
+		m.retries--
+		m.tasks.Restore(saved)
+		if m.mentions != nil {
+			m.mentions.mentions = m.mentions.mentions[:savedMentions]
+		}
+		m.attempts++
+		err = fn(ctx, repos)
+	}
+	return err
 }
 
 func newMockTaskRepository() repository.TaskRepository {
-	return &mockTaskRepository{
-		tasks:  make(map[int]*models.Task),
+	return repository.NewMemoryTaskRepository()
+}
+
+// Mock custom field repository implementation
+type mockCustomFieldRepository struct {
+	fields map[int]*models.CustomFieldDefinition
+	nextID int
+}
+
+func newMockCustomFieldRepository() *mockCustomFieldRepository {
+	return &mockCustomFieldRepository{
+		fields: make(map[int]*models.CustomFieldDefinition),
 		nextID: 1,
 	}
 }
 
-func (m *mockTaskRepository) CreateTask(task *models.Task) error {
-	// Copy the task to avoid pointer issues
-	taskCopy := *task
-	taskCopy.ID = m.nextID
+func (m *mockCustomFieldRepository) CreateField(field *models.CustomFieldDefinition) error {
+	field.ID = m.nextID
 	m.nextID++
-	taskCopy.CreatedAt = time.Now()
-	taskCopy.UpdatedAt = time.Now()
-	
-	// Store the copy
-	m.tasks[taskCopy.ID] = &taskCopy
-	
-	// Update the original task with ID and timestamps
-	task.ID = taskCopy.ID
-	task.CreatedAt = taskCopy.CreatedAt
-	task.UpdatedAt = taskCopy.UpdatedAt
-	
-	return nil
-}
-
-func (m *mockTaskRepository) GetTaskByID(id int) (*models.Task, error) {
-	task, exists := m.tasks[id]
+	field.CreatedAt = time.Now()
+	field.UpdatedAt = field.CreatedAt
+
+	fieldCopy := *field
+	m.fields[field.ID] = &fieldCopy
+	return nil
+}
+
+func (m *mockCustomFieldRepository) GetFieldByID(id int) (*models.CustomFieldDefinition, error) {
+	field, exists := m.fields[id]
 	if !exists {
-		return nil, nil // Task not found
+		return nil, nil
 	}
-	
-	// Return a copy to avoid pointer issues
-	taskCopy := *task
-	return &taskCopy, nil
+	fieldCopy := *field
+	return &fieldCopy, nil
 }
 
-func (m *mockTaskRepository) GetAllTasks(limit, page int, status, sortBy, sortOrder string) ([]models.Task, int, error) {
-	// Apply filtering first
-	var filteredTasks []*models.Task
-	for _, task := range m.tasks {
-		if status == "" || string(task.Status) == status {
-			filteredTasks = append(filteredTasks, task)
+func (m *mockCustomFieldRepository) GetFieldByKey(key string) (*models.CustomFieldDefinition, error) {
+	for _, field := range m.fields {
+		if field.Key == key {
+			fieldCopy := *field
+			return &fieldCopy, nil
 		}
 	}
-	
-	// Convert to slice for return
-	var tasks []models.Task
-	for _, task := range filteredTasks {
-		tasks = append(tasks, *task)
+	return nil, nil
+}
+
+func (m *mockCustomFieldRepository) GetAllFields(ctx context.Context) ([]models.CustomFieldDefinition, error) {
+	fields := []models.CustomFieldDefinition{}
+	for _, field := range m.fields {
+		fields = append(fields, *field)
 	}
-	
-	totalCount := len(tasks)
-	
-	// Apply pagination (simple implementation for testing)
-	if page <= 0 {
-		page = 1
+	return fields, nil
+}
+
+func (m *mockCustomFieldRepository) DeleteField(id int) error {
+	delete(m.fields, id)
+	return nil
+}
+
+// Mock board repository implementation
+type mockBoardRepository struct {
+	settings map[models.TaskStatus]models.BoardColumnSettings
+}
+
+func newMockBoardRepository() *mockBoardRepository {
+	return &mockBoardRepository{settings: make(map[models.TaskStatus]models.BoardColumnSettings)}
+}
+
+func (m *mockBoardRepository) GetColumnSettings() ([]models.BoardColumnSettings, error) {
+	settings := []models.BoardColumnSettings{}
+	for _, column := range m.settings {
+		settings = append(settings, column)
 	}
-	if limit <= 0 {
-		limit = 10
+	return settings, nil
+}
+
+func (m *mockBoardRepository) UpdateColumnSettings(settings *models.BoardColumnSettings) error {
+	m.settings[settings.Status] = *settings
+	return nil
+}
+// Mock user repository implementation
+type mockUserRepository struct {
+	users       map[int]*models.User
+	tokenHashes map[string]int
+	nextID      int
+}
+
+func newMockUserRepository() *mockUserRepository {
+	return &mockUserRepository{
+		users:       make(map[int]*models.User),
+		tokenHashes: make(map[string]int),
+		nextID:      1,
 	}
-	
-	start := (page - 1) * limit
-	end := start + limit
-	
-	if start >= len(tasks) {
-		return []models.Task{}, totalCount, nil
+}
+
+func (m *mockUserRepository) CreateUser(user *models.User, tokenHash string) error {
+	user.ID = m.nextID
+	m.nextID++
+	user.CreatedAt = time.Now()
+	userCopy := *user
+	m.users[user.ID] = &userCopy
+	m.tokenHashes[tokenHash] = user.ID
+	return nil
+}
+
+func (m *mockUserRepository) GetUserByID(id int) (*models.User, error) {
+	user, exists := m.users[id]
+	if !exists {
+		return nil, nil
 	}
-	if end > len(tasks) {
-		end = len(tasks)
+	userCopy := *user
+	return &userCopy, nil
+}
+
+func (m *mockUserRepository) GetUserByUsername(username string) (*models.User, error) {
+	for _, user := range m.users {
+		if strings.EqualFold(user.Username, username) {
+			userCopy := *user
+			return &userCopy, nil
+		}
 	}
-	
-	return tasks[start:end], totalCount, nil
+	return nil, nil
 }
 
-func (m *mockTaskRepository) UpdateTask(task *models.Task) error {
-	_, exists := m.tasks[task.ID]
+func (m *mockUserRepository) GetUserByTokenHash(tokenHash string) (*models.User, error) {
+	id, exists := m.tokenHashes[tokenHash]
 	if !exists {
-		return nil // Simulate sql.ErrNoRows behavior
+		return nil, nil
+	}
+	return m.GetUserByID(id)
+}
+
+func (m *mockUserRepository) GetUsersByUsernames(ctx context.Context, usernames []string) ([]models.User, error) {
+	users := []models.User{}
+	for _, username := range usernames {
+		if user, _ := m.GetUserByUsername(username); user != nil {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+// Mock watcher repository implementation
+type mockWatcherRepository struct {
+	users    *mockUserRepository
+	watchers map[int][]int
+}
+
+func newMockWatcherRepository(users *mockUserRepository) *mockWatcherRepository {
+	return &mockWatcherRepository{users: users, watchers: make(map[int][]int)}
+}
+
+func (m *mockWatcherRepository) AddWatcher(taskID, userID int) error {
+	if !slices.Contains(m.watchers[taskID], userID) {
+		m.watchers[taskID] = append(m.watchers[taskID], userID)
+	}
+	return nil
+}
+
+func (m *mockWatcherRepository) RemoveWatcher(taskID, userID int) error {
+	m.watchers[taskID] = slices.DeleteFunc(m.watchers[taskID], func(id int) bool { return id == userID })
+	return nil
+}
+
+func (m *mockWatcherRepository) RemoveAllWatchers(taskID int) error {
+	delete(m.watchers, taskID)
+	return nil
+}
+
+func (m *mockWatcherRepository) GetWatchers(taskID int) ([]models.User, error) {
+	users := []models.User{}
+	for _, id := range m.watchers[taskID] {
+		user, _ := m.users.GetUserByID(id)
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+// Mock notification repository implementation
+type mockNotificationRepository struct {
+	watchers      *mockWatcherRepository
+	notifications []models.Notification
+	notified      map[[2]int64]bool // Outbox message and user pairs, as the unique index
+	failures      int               // Calls to CreateForWatchers that fail before it works
+}
+
+func newMockNotificationRepository(watchers *mockWatcherRepository) *mockNotificationRepository {
+	return &mockNotificationRepository{watchers: watchers, notified: make(map[[2]int64]bool)}
+}
+
+func (m *mockNotificationRepository) CreateForWatchers(eventID int64, notification *models.Notification) (int, error) {
+	if m.failures > 0 {
+		m.failures--
+		return 0, errors.New("notifications unavailable")
+	}
+
+	notification.CreatedAt = time.Now()
+	created := 0
+	for _, userID := range m.watchers.watchers[notification.TaskID] {
+		key := [2]int64{eventID, int64(userID)}
+		if eventID != 0 && m.notified[key] {
+			continue
+		}
+		m.notified[key] = true
+		row := *notification
+		row.ID = len(m.notifications) + 1
+		row.UserID = userID
+		m.notifications = append(m.notifications, row)
+		created++
+	}
+	return created, nil
+}
+
+func (m *mockNotificationRepository) GetNotifications(userID int, unreadOnly bool, limit, page int) ([]models.Notification, int, error) {
+	matching := []models.Notification{}
+	for i := len(m.notifications) - 1; i >= 0; i-- {
+		notification := m.notifications[i]
+		if notification.UserID != userID || (unreadOnly && notification.ReadAt != nil) {
+			continue
+		}
+		matching = append(matching, notification)
+	}
+
+	start := min((page-1)*limit, len(matching))
+	end := min(start+limit, len(matching))
+	return matching[start:end], len(matching), nil
+}
+
+func (m *mockNotificationRepository) CountUnread(userID int) (int, error) {
+	_, count, err := m.GetNotifications(userID, true, 1, 1)
+	return count, err
+}
+
+func (m *mockNotificationRepository) MarkRead(userID, id int) (bool, error) {
+	for i := range m.notifications {
+		if m.notifications[i].ID == id && m.notifications[i].UserID == userID {
+			if m.notifications[i].ReadAt == nil {
+				now := time.Now()
+				m.notifications[i].ReadAt = &now
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockNotificationRepository) MarkAllRead(userID int) (int, error) {
+	updated := 0
+	for i := range m.notifications {
+		if m.notifications[i].UserID == userID && m.notifications[i].ReadAt == nil {
+			now := time.Now()
+			m.notifications[i].ReadAt = &now
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// Mock mention repository implementation
+type mockMentionRepository struct {
+	tasks    repository.TaskRepository
+	mentions []models.Mention
+	failures int // Calls to CreateMentions that fail before it works
+}
+
+func newMockMentionRepository(tasks repository.TaskRepository) *mockMentionRepository {
+	return &mockMentionRepository{tasks: tasks}
+}
+
+func (m *mockMentionRepository) CreateMentions(mentions []models.Mention) error {
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("mention storage unavailable")
+	}
+	for i := range mentions {
+		mentions[i].ID = len(m.mentions) + 1
+		mentions[i].CreatedAt = time.Now()
+		m.mentions = append(m.mentions, mentions[i])
+	}
+	return nil
+}
+
+func (m *mockMentionRepository) GetMentions(userID int, limit, page int) ([]models.Mention, int, error) {
+	matching := []models.Mention{}
+	for i := len(m.mentions) - 1; i >= 0; i-- {
+		mention := m.mentions[i]
+		if mention.UserID != userID {
+			continue
+		}
+		if task, _ := m.tasks.GetTaskByID(context.Background(), mention.TaskID); task != nil {
+			mention.TaskTitle = task.Title
+		}
+		matching = append(matching, mention)
 	}
-	
-	// Update timestamp
-	task.UpdatedAt = time.Now()
-	
-	// Store updated task
-	taskCopy := *task
-	m.tasks[task.ID] = &taskCopy
-	
+
+	start := min((page-1)*limit, len(matching))
+	end := min(start+limit, len(matching))
+	return matching[start:end], len(matching), nil
+}
+
+// Mock webhook repository implementation
+type mockWebhookRepository struct {
+	webhooks   map[int]*models.Webhook
+	deliveries []models.WebhookDelivery
+	nextID     int
+	queued     map[[2]int64]bool // Outbox message and webhook pairs, as the unique index
+	failures   int               // Calls to EnqueueDeliveries that fail before it works
+}
+
+func newMockWebhookRepository() *mockWebhookRepository {
+	return &mockWebhookRepository{webhooks: make(map[int]*models.Webhook), nextID: 1, queued: make(map[[2]int64]bool)}
+}
+
+func (m *mockWebhookRepository) CreateWebhook(webhook *models.Webhook) error {
+	webhook.ID = m.nextID
+	m.nextID++
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+	webhookCopy := *webhook
+	m.webhooks[webhook.ID] = &webhookCopy
 	return nil
 }
 
-func (m *mockTaskRepository) DeleteTask(id int) error {
-	_, exists := m.tasks[id]
+func (m *mockWebhookRepository) GetWebhookByID(id int) (*models.Webhook, error) {
+	webhook, exists := m.webhooks[id]
 	if !exists {
-		return nil // Simulate sql.ErrNoRows behavior
+		return nil, nil
+	}
+	webhookCopy := *webhook
+	webhookCopy.Secret = ""
+	return &webhookCopy, nil
+}
+
+func (m *mockWebhookRepository) GetWebhooksByOwner(ownerID int) ([]models.Webhook, error) {
+	webhooks := []models.Webhook{}
+	for id, webhook := range m.webhooks {
+		if webhook.CreatedBy != ownerID {
+			continue
+		}
+		webhook, _ := m.GetWebhookByID(id)
+		webhooks = append(webhooks, *webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+func (m *mockWebhookRepository) DeleteWebhook(id, ownerID int) error {
+	if webhook, exists := m.webhooks[id]; !exists || webhook.CreatedBy != ownerID {
+		return sql.ErrNoRows
 	}
-	
-	delete(m.tasks, id)
+	delete(m.webhooks, id)
 	return nil
 }
+
+func (m *mockWebhookRepository) EnqueueDeliveries(eventID int64, eventType models.TaskEventType, payload []byte) (int, error) {
+	if m.failures > 0 {
+		m.failures--
+		return 0, errors.New("webhook queue unavailable")
+	}
+
+	queued := 0
+	for _, webhook := range m.webhooks {
+		key := [2]int64{eventID, int64(webhook.ID)}
+		if !webhook.Active || !webhook.Subscribes(eventType) || (eventID != 0 && m.queued[key]) {
+			continue
+		}
+		m.queued[key] = true
+		now := time.Now()
+		m.deliveries = append(m.deliveries, models.WebhookDelivery{
+			ID:            len(m.deliveries) + 1,
+			WebhookID:     webhook.ID,
+			Event:         eventType,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: &now,
+			CreatedAt:     now,
+		})
+		queued++
+	}
+	return queued, nil
+}
+
+func (m *mockWebhookRepository) EnqueueDelivery(webhookID int, event string, payload []byte) (bool, error) {
+	webhook, exists := m.webhooks[webhookID]
+	if !exists || !webhook.Active {
+		return false, nil
+	}
+	now := time.Now()
+	m.deliveries = append(m.deliveries, models.WebhookDelivery{
+		ID:            len(m.deliveries) + 1,
+		WebhookID:     webhookID,
+		Event:         models.TaskEventType(event),
+		Payload:       payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+	})
+	return true, nil
+}
+
+func (m *mockWebhookRepository) ClaimDueDeliveries(limit int, leaseUntil time.Time) ([]models.WebhookDeliveryJob, error) {
+	jobs := []models.WebhookDeliveryJob{}
+	for i := range m.deliveries {
+		delivery := &m.deliveries[i]
+		if len(jobs) == limit || delivery.Status != models.DeliveryPending || delivery.NextAttemptAt.After(time.Now()) {
+			continue
+		}
+		lease := leaseUntil
+		delivery.NextAttemptAt = &lease
+		webhook := m.webhooks[delivery.WebhookID]
+		jobs = append(jobs, models.WebhookDeliveryJob{Delivery: *delivery, URL: webhook.URL, Secret: webhook.Secret})
+	}
+	return jobs, nil
+}
+
+func (m *mockWebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	m.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+func (m *mockWebhookRepository) GetDeliveries(webhookID int, limit, page int) ([]models.WebhookDelivery, int, error) {
+	matching := []models.WebhookDelivery{}
+	for i := len(m.deliveries) - 1; i >= 0; i-- {
+		if m.deliveries[i].WebhookID == webhookID {
+			matching = append(matching, m.deliveries[i])
+		}
+	}
+
+	start := min((page-1)*limit, len(matching))
+	end := min(start+limit, len(matching))
+	return matching[start:end], len(matching), nil
+}
+
+// Mock outbox repository implementation
+type mockOutboxRepository struct {
+	messages    []models.OutboxMessage
+	availableAt map[int64]time.Time
+}
+
+func newMockOutboxRepository() *mockOutboxRepository {
+	return &mockOutboxRepository{availableAt: make(map[int64]time.Time)}
+}
+
+func (m *mockOutboxRepository) add(event models.TaskEvent) {
+	payload, _ := json.Marshal(event)
+	m.messages = append(m.messages, models.OutboxMessage{
+		ID:          int64(len(m.messages) + 1),
+		AggregateID: event.Task.ID,
+		EventType:   event.Type,
+		Payload:     payload,
+		CreatedAt:   time.Now(),
+	})
+}
+
+func (m *mockOutboxRepository) PublishBatch(limit int, retryAfter func(attempts int) time.Duration, publish func(message models.OutboxMessage) error) (int, error) {
+	published, claimed := 0, 0
+	for i := range m.messages {
+		message := &m.messages[i]
+		if claimed == limit || message.PublishedAt != nil || m.availableAt[message.ID].After(time.Now()) {
+			continue
+		}
+		claimed++
+		err := publish(*message)
+		message.Attempts++
+		if err != nil {
+			m.availableAt[message.ID] = time.Now().Add(retryAfter(message.Attempts))
+			continue
+		}
+		now := time.Now()
+		message.PublishedAt = &now
+		published++
+	}
+	return published, nil
+}
+
+func (m *mockOutboxRepository) DeletePublished(before time.Time) (int, error) {
+	kept := m.messages[:0]
+	for _, message := range m.messages {
+		if message.PublishedAt == nil || !message.PublishedAt.Before(before) {
+			kept = append(kept, message)
+		}
+	}
+	deleted := len(m.messages) - len(kept)
+	m.messages = kept
+	return deleted, nil
+}
+
+func (m *mockOutboxRepository) GetMessagesAfter(afterID int64, limit int) ([]models.OutboxMessage, error) {
+	messages := []models.OutboxMessage{}
+	for _, message := range m.messages {
+		if message.ID > afterID && len(messages) < limit {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+func (m *mockOutboxRepository) GetMessagesByIDs(ids []int64) ([]models.OutboxMessage, error) {
+	messages := []models.OutboxMessage{}
+	for _, message := range m.messages {
+		if slices.Contains(ids, message.ID) {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+func (m *mockOutboxRepository) GetLookbackStart(id int64, window time.Duration) (int64, error) {
+	var createdAt time.Time
+	for _, message := range m.messages {
+		if message.ID == id {
+			createdAt = message.CreatedAt
+		}
+	}
+	if createdAt.IsZero() {
+		return id, nil
+	}
+	for _, message := range m.messages {
+		if !message.CreatedAt.Before(createdAt.Add(-window)) {
+			return min(message.ID, id), nil
+		}
+	}
+	return id, nil
+}
+
+func (m *mockOutboxRepository) GetLatestMessageID() (int64, error) {
+	if len(m.messages) == 0 {
+		return 0, nil
+	}
+	return m.messages[len(m.messages)-1].ID, nil
+}
+
+// Mock automation rule repository implementation
+type mockAutomationRuleRepository struct {
+	rules  []models.AutomationRule
+	nextID int
+}
+
+func newMockAutomationRuleRepository() *mockAutomationRuleRepository {
+	return &mockAutomationRuleRepository{nextID: 1}
+}
+
+func (m *mockAutomationRuleRepository) CreateRule(rule *models.AutomationRule) error {
+	rule.ID = m.nextID
+	m.nextID++
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+	m.rules = append(m.rules, *rule)
+	return nil
+}
+
+func (m *mockAutomationRuleRepository) GetRuleByID(id int) (*models.AutomationRule, error) {
+	for _, rule := range m.rules {
+		if rule.ID == id {
+			return &rule, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAutomationRuleRepository) GetAllRules() ([]models.AutomationRule, error) {
+	return append([]models.AutomationRule{}, m.rules...), nil
+}
+
+func (m *mockAutomationRuleRepository) GetEnabledRules(trigger models.TaskEventType) ([]models.AutomationRule, error) {
+	rules := []models.AutomationRule{}
+	for _, rule := range m.rules {
+		if rule.Enabled && rule.Trigger == trigger {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m *mockAutomationRuleRepository) DeleteRule(id int) error {
+	m.rules = slices.DeleteFunc(m.rules, func(rule models.AutomationRule) bool { return rule.ID == id })
+	return nil
+}
+
+// Mock attachment repository implementation
+type mockAttachmentRepository struct {
+	attachments []models.TaskAttachment
+}
+
+func (m *mockAttachmentRepository) CreateAttachment(attachment *models.TaskAttachment) error {
+	attachment.ID = len(m.attachments) + 1
+	attachment.Size = len(attachment.Data)
+	attachment.CreatedAt = time.Now()
+	m.attachments = append(m.attachments, *attachment)
+	return nil
+}
+
+func (m *mockAttachmentRepository) GetAttachments(taskID int) ([]models.TaskAttachment, error) {
+	attachments := []models.TaskAttachment{}
+	for _, attachment := range m.attachments {
+		if attachment.TaskID == taskID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (m *mockAttachmentRepository) GetAttachment(taskID, id int) (*models.TaskAttachment, error) {
+	for _, attachment := range m.attachments {
+		if attachment.TaskID == taskID && attachment.ID == id {
+			return &attachment, nil
+		}
+	}
+	return nil, nil
+}
+
+// Mock digest repository implementation
+type mockDigestRepository struct {
+	preferences map[int]*models.DigestPreferences
+	users       map[int]models.User
+	tasks       []models.Task
+	sent        map[int]time.Time
+}
+
+func newMockDigestRepository() *mockDigestRepository {
+	return &mockDigestRepository{
+		preferences: make(map[int]*models.DigestPreferences),
+		users:       make(map[int]models.User),
+		sent:        make(map[int]time.Time),
+	}
+}
+
+func (m *mockDigestRepository) GetPreferences(userID int) (*models.DigestPreferences, error) {
+	if preferences, ok := m.preferences[userID]; ok {
+		saved := *preferences
+		return &saved, nil
+	}
+	return nil, nil
+}
+
+func (m *mockDigestRepository) SavePreferences(preferences *models.DigestPreferences) error {
+	preferences.UpdatedAt = time.Now()
+	saved := *preferences
+	m.preferences[preferences.UserID] = &saved
+	return nil
+}
+
+func (m *mockDigestRepository) ClaimDueDigests(now time.Time, limit int, leaseUntil time.Time) ([]models.DigestJob, error) {
+	jobs := []models.DigestJob{}
+	for userID, preferences := range m.preferences {
+		if len(jobs) == limit || preferences.Frequency == models.DigestOff || preferences.NextDigestAt == nil || preferences.NextDigestAt.After(now) {
+			continue
+		}
+		lease := leaseUntil
+		preferences.NextDigestAt = &lease
+		jobs = append(jobs, models.DigestJob{Preferences: *preferences, Recipient: m.users[userID]})
+	}
+	return jobs, nil
+}
+
+func (m *mockDigestRepository) MarkDigestSent(userID int, sentAt, nextDigestAt time.Time) error {
+	m.preferences[userID].LastSentAt = &sentAt
+	m.preferences[userID].NextDigestAt = &nextDigestAt
+	m.sent[userID] = sentAt
+	return nil
+}
+
+func (m *mockDigestRepository) GetChangedTasks(preferences models.DigestPreferences, since, until time.Time, limit int) ([]models.Task, error) {
+	tasks := []models.Task{}
+	for _, task := range m.tasks {
+		if !task.UpdatedAt.Before(since) && task.UpdatedAt.Before(until) && digestIncludes(preferences, task) && len(tasks) < limit {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *mockDigestRepository) GetDueTasks(preferences models.DigestPreferences, dueField string, dueBy time.Time, limit int) ([]models.Task, error) {
+	tasks := []models.Task{}
+	for _, task := range m.tasks {
+		due, ok := task.CustomFields[dueField].(string)
+		open := task.Status != models.StatusCompleted && task.Status != models.StatusClosed
+		if ok && open && due <= dueBy.Format("2006-01-02") && digestIncludes(preferences, task) && len(tasks) < limit {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func digestIncludes(preferences models.DigestPreferences, task models.Task) bool {
+	return len(preferences.Statuses) == 0 || slices.Contains(preferences.Statuses, task.Status)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+type UserService struct {
+	userRepo repository.UserRepository
+}
+
+type UserServiceInterface interface {
+	CreateUser(username, email, displayName string) (*models.UserWithToken, error)
+	GetUserByID(id int) (*models.User, error)
+	AuthenticateToken(token string) (*models.User, error)
+}
+
+func NewUserService(userRepo repository.UserRepository) UserServiceInterface {
+	return &UserService{
+		userRepo: userRepo,
+	}
+}
+
+// Creates a user and issues their API token. The token is only returned here.
+func (s *UserService) CreateUser(username, email, displayName string) (*models.UserWithToken, error) {
+	user := models.User{
+		Username:    strings.TrimSpace(username),
+		Email:       strings.TrimSpace(email),
+		DisplayName: strings.TrimSpace(displayName),
+	}
+
+	if !usernamePattern.MatchString(user.Username) {
+		return nil, models.ValidationError{
+			Field:   "username",
+			Message: "may only contain letters, digits, '_', '.' and '-', and cannot start with '.' or '-'",
+		}
+	}
+
+	existing, err := s.userRepo.GetUserByUsername(user.Username)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, models.BusinessError{Message: fmt.Sprintf("username '%s' is already taken", user.Username)}
+	}
+
+	token, err := generateAPIToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.CreateUser(&user, hashAPIToken(token)); err != nil {
+		return nil, err
+	}
+
+	return &models.UserWithToken{User: user, APIToken: token}, nil
+}
+
+func (s *UserService) GetUserByID(id int) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, models.NotFoundError{Resource: "user", ID: id}
+	}
+
+	return user, nil
+}
+
+// Resolves an API token to its user
+func (s *UserService) AuthenticateToken(token string) (*models.User, error) {
+	if token == "" {
+		return nil, models.UnauthorizedError{Message: "missing API token"}
+	}
+
+	user, err := s.userRepo.GetUserByTokenHash(hashAPIToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, models.UnauthorizedError{Message: "invalid API token"}
+	}
+
+	return user, nil
+}
+
+func generateAPIToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
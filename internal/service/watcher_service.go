@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type WatcherService struct {
+	taskRepo    repository.TaskRepository
+	watcherRepo repository.WatcherRepository
+}
+
+type WatcherServiceInterface interface {
+	Watch(ctx context.Context, taskID, userID int) error
+	Unwatch(ctx context.Context, taskID, userID int) error
+	GetWatchers(ctx context.Context, taskID int) ([]models.PublicUser, error)
+}
+
+func NewWatcherService(taskRepo repository.TaskRepository, watcherRepo repository.WatcherRepository) WatcherServiceInterface {
+	return &WatcherService{
+		taskRepo:    taskRepo,
+		watcherRepo: watcherRepo,
+	}
+}
+
+func (s *WatcherService) Watch(ctx context.Context, taskID, userID int) error {
+	if err := s.ensureTaskExists(ctx, taskID); err != nil {
+		return err
+	}
+	return s.watcherRepo.AddWatcher(taskID, userID)
+}
+
+func (s *WatcherService) Unwatch(ctx context.Context, taskID, userID int) error {
+	if err := s.ensureTaskExists(ctx, taskID); err != nil {
+		return err
+	}
+	return s.watcherRepo.RemoveWatcher(taskID, userID)
+}
+
+// Lists the users watching a task, without their email addresses
+func (s *WatcherService) GetWatchers(ctx context.Context, taskID int) ([]models.PublicUser, error) {
+	if err := s.ensureTaskExists(ctx, taskID); err != nil {
+		return nil, err
+	}
+	users, err := s.watcherRepo.GetWatchers(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	watchers := make([]models.PublicUser, 0, len(users))
+	for _, user := range users {
+		watchers = append(watchers, user.Public())
+	}
+	return watchers, nil
+}
+
+func (s *WatcherService) ensureTaskExists(ctx context.Context, taskID int) error {
+	task, err := s.taskRepo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return models.TaskNotFoundError{ID: taskID}
+	}
+	return nil
+}
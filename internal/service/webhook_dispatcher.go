@@ -0,0 +1,192 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/health"
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+type WebhookDispatcherConfig struct {
+	PollInterval time.Duration // How often the queue is checked for due deliveries
+	BatchSize    int           // Deliveries claimed per poll
+	Timeout      time.Duration // Per request
+	MaxAttempts  int           // Attempts before a delivery is dead-lettered
+	BaseBackoff  time.Duration // Delay before the first retry, doubled for each later one
+	MaxBackoff   time.Duration
+	Targets      WebhookTargetPolicy // Addresses deliveries may connect to
+}
+
+func DefaultWebhookDispatcherConfig() WebhookDispatcherConfig {
+	return WebhookDispatcherConfig{
+		PollInterval: 2 * time.Second,
+		BatchSize:    20,
+		Timeout:      10 * time.Second,
+		MaxAttempts:  8,
+		BaseBackoff:  30 * time.Second,
+		MaxBackoff:   time.Hour,
+	}
+}
+
+// WebhookDispatcher sends queued webhook deliveries in the background, retrying
+// failures with exponential backoff until they succeed or run out of attempts.
+// Any number of instances can run against the same queue.
+type WebhookDispatcher struct {
+	webhookRepo repository.WebhookRepository
+	config      WebhookDispatcherConfig
+	client      *http.Client
+	done        chan struct{}
+	wg          sync.WaitGroup
+	heartbeat   health.Heartbeat
+}
+
+func NewWebhookDispatcher(webhookRepo repository.WebhookRepository, config WebhookDispatcherConfig) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookRepo: webhookRepo,
+		config:      config,
+		client:      config.Targets.client(config.Timeout),
+		done:        make(chan struct{}),
+	}
+}
+
+func (d *WebhookDispatcher) Start() {
+	d.heartbeat.Beat()
+	d.wg.Add(1)
+	go d.run()
+}
+
+// Stops polling after the batch currently being sent, if any, is finished
+func (d *WebhookDispatcher) Stop() {
+	close(d.done)
+	d.wg.Wait()
+}
+
+// Beats before every batch; see health.Heartbeat
+func (d *WebhookDispatcher) Heartbeat() *health.Heartbeat {
+	return &d.heartbeat
+}
+
+// Claims and sends one batch of due deliveries, returning how many were attempted
+func (d *WebhookDispatcher) DispatchDue() (int, error) {
+	// Leave room for the whole batch to be sent before another instance may reclaim it
+	lease := time.Now().Add(time.Duration(d.config.BatchSize+1) * d.config.Timeout)
+	jobs, err := d.webhookRepo.ClaimDueDeliveries(d.config.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		delivery := job.Delivery
+		d.attempt(&delivery, job.URL, job.Secret)
+		if err := d.webhookRepo.UpdateDelivery(&delivery); err != nil {
+			log.Printf("Failed to record webhook delivery %d: %v", delivery.ID, err)
+		}
+	}
+
+	return len(jobs), nil
+}
+
+func (d *WebhookDispatcher) run() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+			// Keep going while full batches come back so a backlog drains quickly
+			for {
+				d.heartbeat.Beat()
+				attempted, err := d.DispatchDue()
+				if err != nil {
+					log.Printf("Webhook dispatch failed: %v", err)
+				}
+				if err != nil || attempted < d.config.BatchSize {
+					break
+				}
+				select {
+				case <-d.done:
+					return
+				default:
+				}
+			}
+		}
+	}
+}
+
+// Sends a delivery once and updates it with the outcome
+func (d *WebhookDispatcher) attempt(delivery *models.WebhookDelivery, url, secret string) {
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+
+	statusCode, err := d.send(delivery, url, secret)
+	if statusCode != 0 {
+		delivery.ResponseStatus = &statusCode
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	case delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+		log.Printf("Webhook delivery %d to %s is dead after %d attempts: %v", delivery.ID, url, delivery.Attempts, err)
+	default:
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.Status = models.DeliveryPending
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+	}
+}
+
+func (d *WebhookDispatcher) send(delivery *models.WebhookDelivery, url, secret string) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-management-api-webhooks")
+	req.Header.Set(models.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(models.WebhookDeliveryHeader, strconv.Itoa(delivery.ID))
+	req.Header.Set(models.WebhookTimestampHeader, timestamp)
+	req.Header.Set(models.WebhookSignatureHeader, SignWebhookPayload(secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Delay before the retry following the given attempt
+func (d *WebhookDispatcher) backoff(attempt int) time.Duration {
+	delay := d.config.BaseBackoff
+	for i := 1; i < attempt && delay < d.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.config.MaxBackoff)
+}
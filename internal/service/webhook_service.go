@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+	"github.com/AashishRichhariya/task-management-api/internal/repository"
+)
+
+const minWebhookSecretLength = 16
+
+// Bound on resolving a webhook's host when it is registered
+const webhookResolveTimeout = 5 * time.Second
+
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	targets     WebhookTargetPolicy
+}
+
+type WebhookServiceInterface interface {
+	TaskEventHandler
+	CreateWebhook(userID int, url, secret string, eventTypes []string) (*models.Webhook, error)
+	GetWebhookByID(userID, id int) (*models.Webhook, error)
+	GetAllWebhooks(userID int) ([]models.Webhook, error)
+	DeleteWebhook(userID, id int) error
+	GetDeliveries(userID, webhookID int, page, limit int) (*models.PaginatedWebhookDeliveriesResponse, error)
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, targets WebhookTargetPolicy) WebhookServiceInterface {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		targets:     targets,
+	}
+}
+
+// Queues a delivery of the event for every subscribed webhook. Sending happens in
+// the WebhookDispatcher, so a slow receiver never holds up the change itself.
+func (s *WebhookService) HandleTaskEvent(event models.TaskEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+
+	queued, err := s.webhookRepo.EnqueueDeliveries(event.ID, event.Type, payload)
+	if err != nil {
+		return fmt.Errorf("failed to queue webhook deliveries for task %d: %w", event.Task.ID, err)
+	}
+	if queued > 0 {
+		log.Printf("Queued %d webhook deliveries of %s on task %d", queued, event.Type, event.Task.ID)
+	}
+	return nil
+}
+
+// Creates a subscription owned by the user. Without a secret one is generated;
+// either way it is only returned here. Hosts that resolve to non-public addresses
+// are refused.
+func (s *WebhookService) CreateWebhook(userID int, rawURL, secret string, eventTypes []string) (*models.Webhook, error) {
+	webhook := &models.Webhook{
+		URL:       strings.TrimSpace(rawURL),
+		Secret:    secret,
+		Active:    true,
+		CreatedBy: userID,
+	}
+
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, models.ValidationError{Field: "url", Message: "must be an absolute http or https URL"}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+	defer cancel()
+	if err := s.targets.checkURL(ctx, parsed); err != nil {
+		return nil, models.ValidationError{Field: "url", Message: err.Error()}
+	}
+
+	if webhook.Secret == "" {
+		if webhook.Secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	} else if len(webhook.Secret) < minWebhookSecretLength {
+		return nil, models.ValidationError{
+			Field:   "secret",
+			Message: fmt.Sprintf("must be at least %d characters", minWebhookSecretLength),
+		}
+	}
+
+	seen := make(map[models.TaskEventType]bool)
+	for _, eventType := range eventTypes {
+		value := models.TaskEventType(strings.TrimSpace(eventType))
+		if !value.IsValid() {
+			return nil, models.ValidationError{Field: "event_types", Message: fmt.Sprintf("unknown event type %q", eventType)}
+		}
+		if !seen[value] {
+			seen[value] = true
+			webhook.EventTypes = append(webhook.EventTypes, value)
+		}
+	}
+	if len(webhook.EventTypes) == 0 {
+		return nil, models.ValidationError{Field: "event_types", Message: "at least one event type is required"}
+	}
+
+	if err := s.webhookRepo.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// Webhooks of other users are reported as not found
+func (s *WebhookService) GetWebhookByID(userID, id int) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetWebhookByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook == nil || webhook.CreatedBy != userID {
+		return nil, models.NotFoundError{Resource: "webhook", ID: id}
+	}
+
+	return webhook, nil
+}
+
+func (s *WebhookService) GetAllWebhooks(userID int) ([]models.Webhook, error) {
+	webhooks, err := s.webhookRepo.GetWebhooksByOwner(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) DeleteWebhook(userID, id int) error {
+	if _, err := s.GetWebhookByID(userID, id); err != nil {
+		return err
+	}
+
+	return s.webhookRepo.DeleteWebhook(id, userID)
+}
+
+func (s *WebhookService) GetDeliveries(userID, webhookID int, page, limit int) (*models.PaginatedWebhookDeliveriesResponse, error) {
+	if _, err := s.GetWebhookByID(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, totalCount, err := s.webhookRepo.GetDeliveries(webhookID, limit, page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+
+	return &models.PaginatedWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Pagination: paginationMeta(page, limit, totalCount),
+	}, nil
+}
+
+// Computes the X-Webhook-Signature value for a payload sent at timestamp (Unix seconds).
+// Receivers recompute it with their copy of the secret and compare in constant time.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
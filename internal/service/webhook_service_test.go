@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AashishRichhariya/task-management-api/internal/models"
+)
+
+// Local receiver recording what it was sent and verifying signatures
+type webhookReceiver struct {
+	server *httptest.Server
+	secret string
+	status int
+
+	mu       sync.Mutex
+	received []models.TaskEvent
+	invalid  int
+}
+
+func newWebhookReceiver(t *testing.T, secret string, status int) *webhookReceiver {
+	receiver := &webhookReceiver{secret: secret, status: status}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		expected := SignWebhookPayload(receiver.secret, r.Header.Get(models.WebhookTimestampHeader), body)
+
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		if !hmac.Equal([]byte(expected), []byte(r.Header.Get(models.WebhookSignatureHeader))) {
+			receiver.invalid++
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event models.TaskEvent
+		json.Unmarshal(body, &event)
+		receiver.received = append(receiver.received, event)
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+func testWebhookDispatcherConfig() WebhookDispatcherConfig {
+	config := DefaultWebhookDispatcherConfig()
+	config.MaxAttempts = 3
+	config.BaseBackoff = 0 // Retries are due immediately
+	config.Timeout = 2 * time.Second
+	config.Targets = localWebhookTargets
+	return config
+}
+
+// The test receivers listen on loopback
+var localWebhookTargets = WebhookTargetPolicy{AllowPrivate: true}
+
+// Resolves hosts from a fixed table instead of DNS
+func fakeWebhookTargets(hosts map[string]string) WebhookTargetPolicy {
+	return WebhookTargetPolicy{lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+		addr, ok := hosts[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		return []netip.Addr{netip.MustParseAddr(addr)}, nil
+	}}
+}
+
+func TestWebhookService_SignedDelivery(t *testing.T) {
+	webhookRepo := newMockWebhookRepository()
+	webhookService := NewWebhookService(webhookRepo, localWebhookTargets)
+	taskService := NewTaskService(newMockTaskRepository(), WithEventHandlers(webhookService))
+	dispatcher := NewWebhookDispatcher(webhookRepo, testWebhookDispatcherConfig())
+
+	receiver := newWebhookReceiver(t, "a-very-secret-value", http.StatusOK)
+	webhook, err := webhookService.CreateWebhook(1, receiver.server.URL, receiver.secret, []string{"task.created", "task.deleted"})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+
+	task, _ := taskService.CreateTask(context.Background(), "Hooked", "", "pending", nil)
+	taskService.UpdateTask(context.Background(), task.ID, "", "", "completed", nil) // Not subscribed
+	taskService.DeleteTask(context.Background(), task.ID)
+
+	attempted, err := dispatcher.DispatchDue()
+	if err != nil || attempted != 2 {
+		t.Fatalf("Expected 2 deliveries to be attempted, got %d (%v)", attempted, err)
+	}
+
+	if receiver.invalid != 0 {
+		t.Errorf("Receiver rejected %d signatures", receiver.invalid)
+	}
+	if len(receiver.received) != 2 || receiver.received[0].Type != models.EventTaskCreated ||
+		receiver.received[1].Type != models.EventTaskDeleted || receiver.received[0].Task.Title != "Hooked" {
+		t.Errorf("Unexpected events received: %+v", receiver.received)
+	}
+
+	result, err := webhookService.GetDeliveries(1, webhook.ID, 1, 20)
+	if err != nil {
+		t.Fatalf("GetDeliveries failed: %v", err)
+	}
+	for _, delivery := range result.Deliveries {
+		if delivery.Status != models.DeliveryDelivered || delivery.Attempts != 1 || delivery.DeliveredAt == nil {
+			t.Errorf("Expected delivery to succeed on the first attempt, got %+v", delivery)
+		}
+	}
+
+	// Nothing is left to send
+	if attempted, _ := dispatcher.DispatchDue(); attempted != 0 {
+		t.Errorf("Expected an empty queue, %d deliveries were attempted", attempted)
+	}
+}
+
+func TestWebhookDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	webhookRepo := newMockWebhookRepository()
+	webhookService := NewWebhookService(webhookRepo, localWebhookTargets)
+	taskService := NewTaskService(newMockTaskRepository(), WithEventHandlers(webhookService))
+	dispatcher := NewWebhookDispatcher(webhookRepo, testWebhookDispatcherConfig())
+
+	receiver := newWebhookReceiver(t, "a-very-secret-value", http.StatusInternalServerError)
+	webhook, _ := webhookService.CreateWebhook(1, receiver.server.URL, receiver.secret, []string{"task.created"})
+	taskService.CreateTask(context.Background(), "Flaky", "", "pending", nil)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if attempted, _ := dispatcher.DispatchDue(); attempted != 1 {
+			t.Fatalf("Expected attempt %d to be made, %d deliveries were attempted", attempt, attempted)
+		}
+	}
+
+	result, _ := webhookService.GetDeliveries(1, webhook.ID, 1, 20)
+	delivery := result.Deliveries[0]
+	if delivery.Status != models.DeliveryDead || delivery.Attempts != 3 {
+		t.Fatalf("Expected a dead delivery after 3 attempts, got %+v", delivery)
+	}
+	if delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusInternalServerError || delivery.LastError == "" {
+		t.Errorf("Expected the failure to be logged, got %+v", delivery)
+	}
+
+	// Dead deliveries are not retried
+	if attempted, _ := dispatcher.DispatchDue(); attempted != 0 {
+		t.Errorf("Expected no more attempts, %d were made", attempted)
+	}
+}
+
+func TestWebhookDispatcher_Backoff(t *testing.T) {
+	config := DefaultWebhookDispatcherConfig()
+	config.BaseBackoff = time.Second
+	config.MaxBackoff = 10 * time.Second
+	dispatcher := NewWebhookDispatcher(newMockWebhookRepository(), config)
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, delay := range expected {
+		if got := dispatcher.backoff(i + 1); got != delay {
+			t.Errorf("backoff(%d) = %v, expected %v", i+1, got, delay)
+		}
+	}
+}
+
+func TestWebhookService_CreateWebhookValidation(t *testing.T) {
+	webhookService := NewWebhookService(newMockWebhookRepository(), fakeWebhookTargets(map[string]string{"example.com": "93.184.215.14"}))
+
+	tests := []struct {
+		url, secret string
+		events      []string
+	}{
+		{"ftp://example.com/hook", "", []string{"task.created"}},
+		{"https://example.com/hook", "short", []string{"task.created"}},
+		{"https://example.com/hook", "", []string{"task.exploded"}},
+		{"https://example.com/hook", "", nil},
+	}
+	for _, test := range tests {
+		var validationErr models.ValidationError
+		if _, err := webhookService.CreateWebhook(1, test.url, test.secret, test.events); !errors.As(err, &validationErr) {
+			t.Errorf("Expected ValidationError for %+v, got %v", test, err)
+		}
+	}
+
+	webhook, err := webhookService.CreateWebhook(1, "https://example.com/hook", "", []string{"task.created"})
+	if err != nil || len(webhook.Secret) < minWebhookSecretLength {
+		t.Errorf("Expected a generated secret, got %+v (%v)", webhook, err)
+	}
+}
+
+func TestWebhookService_RefusesNonPublicTargets(t *testing.T) {
+	webhookService := NewWebhookService(newMockWebhookRepository(), fakeWebhookTargets(map[string]string{
+		"example.com":      "93.184.215.14",
+		"intranet.example": "192.168.1.10",
+	}))
+
+	for _, url := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook", // Not in the table, so unresolvable
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook",
+		"http://100.64.0.1/hook",
+		"http://[::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://[fd00::1]/hook",
+		"https://intranet.example/hook",
+	} {
+		var validationErr models.ValidationError
+		if _, err := webhookService.CreateWebhook(1, url, "", []string{"task.created"}); !errors.As(err, &validationErr) || validationErr.Field != "url" {
+			t.Errorf("Expected %s to be refused, got %v", url, err)
+		}
+	}
+
+	if _, err := webhookService.CreateWebhook(1, "https://example.com/hook", "", []string{"task.created"}); err != nil {
+		t.Errorf("Expected a public host to be accepted, got %v", err)
+	}
+}
+
+func TestWebhookDispatcher_RefusesNonPublicAddressesWhenSending(t *testing.T) {
+	// As if the host had resolved to a public address when it was registered
+	webhookRepo := newMockWebhookRepository()
+	receiver := newWebhookReceiver(t, "a-very-secret-value", http.StatusOK)
+	webhookRepo.CreateWebhook(&models.Webhook{URL: receiver.server.URL, Secret: receiver.secret, Active: true, EventTypes: []models.TaskEventType{models.EventTaskCreated}})
+	webhookRepo.EnqueueDeliveries(0, models.EventTaskCreated, []byte(`{}`))
+
+	config := testWebhookDispatcherConfig()
+	config.Targets = WebhookTargetPolicy{}
+	dispatcher := NewWebhookDispatcher(webhookRepo, config)
+	if attempted, _ := dispatcher.DispatchDue(); attempted != 1 {
+		t.Fatalf("Expected 1 delivery to be attempted, got %d", attempted)
+	}
+
+	delivery := webhookRepo.deliveries[0]
+	if delivery.Status != models.DeliveryPending || !strings.Contains(delivery.LastError, "non-public address 127.0.0.1") {
+		t.Errorf("Expected the connection to be refused, got %+v", delivery)
+	}
+	if len(receiver.received) != 0 {
+		t.Errorf("Expected nothing to reach the receiver, got %+v", receiver.received)
+	}
+}
+
+func TestWebhookDispatcher_DoesNotFollowRedirects(t *testing.T) {
+	var followed atomic.Bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed.Store(true)
+	}))
+	defer target.Close()
+	redirector := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer redirector.Close()
+
+	webhookRepo := newMockWebhookRepository()
+	webhookService := NewWebhookService(webhookRepo, localWebhookTargets)
+	webhookService.CreateWebhook(1, redirector.URL, "", []string{"task.created"})
+	webhookRepo.EnqueueDeliveries(0, models.EventTaskCreated, []byte(`{}`))
+
+	dispatcher := NewWebhookDispatcher(webhookRepo, testWebhookDispatcherConfig())
+	dispatcher.DispatchDue()
+
+	delivery := webhookRepo.deliveries[0]
+	if followed.Load() {
+		t.Error("Expected the redirect not to be followed")
+	}
+	if delivery.Status != models.DeliveryPending || delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusTemporaryRedirect {
+		t.Errorf("Expected the redirect to fail the delivery, got %+v", delivery)
+	}
+}
+
+func TestWebhookService_ScopedToOwner(t *testing.T) {
+	webhookService := NewWebhookService(newMockWebhookRepository(), fakeWebhookTargets(map[string]string{"example.com": "93.184.215.14"}))
+
+	webhook, err := webhookService.CreateWebhook(1, "https://example.com/hook", "", []string{"task.created"})
+	if err != nil {
+		t.Fatalf("CreateWebhook failed: %v", err)
+	}
+	if webhook.CreatedBy != 1 {
+		t.Errorf("Expected the webhook to belong to user 1, got %d", webhook.CreatedBy)
+	}
+
+	// Another user can neither see nor touch it
+	var notFound models.NotFoundError
+	if _, err := webhookService.GetWebhookByID(2, webhook.ID); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError reading another user's webhook, got %v", err)
+	}
+	if _, err := webhookService.GetDeliveries(2, webhook.ID, 1, 20); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError reading another user's deliveries, got %v", err)
+	}
+	if err := webhookService.DeleteWebhook(2, webhook.ID); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError deleting another user's webhook, got %v", err)
+	}
+	if webhooks, _ := webhookService.GetAllWebhooks(2); len(webhooks) != 0 {
+		t.Errorf("Expected no webhooks for user 2, got %v", webhooks)
+	}
+
+	if webhooks, _ := webhookService.GetAllWebhooks(1); len(webhooks) != 1 {
+		t.Errorf("Expected the owner to see their webhook, got %v", webhooks)
+	}
+	if err := webhookService.DeleteWebhook(1, webhook.ID); err != nil {
+		t.Errorf("Expected the owner to delete their webhook, got %v", err)
+	}
+}
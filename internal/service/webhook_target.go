@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// Address ranges that are not reachable on the public internet but that
+// netip.Addr's own predicates do not cover
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This network"
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+}
+
+// WebhookTargetPolicy decides which hosts webhooks may be sent to. Loopback, private,
+// link-local and other non-public addresses are refused, so subscribers cannot use the
+// server to reach internal services or cloud metadata endpoints. It is checked when a
+// webhook is registered and again on every connection, since DNS answers can change.
+type WebhookTargetPolicy struct {
+	AllowPrivate bool // Allows every address, for development and internal receivers
+
+	lookup func(ctx context.Context, host string) ([]netip.Addr, error) // Replaced in tests
+}
+
+func (p WebhookTargetPolicy) allows(addr netip.Addr) bool {
+	if p.AllowPrivate {
+		return true
+	}
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolves the URL's host and fails unless every address it resolves to is allowed
+func (p WebhookTargetPolicy) checkURL(ctx context.Context, target *url.URL) error {
+	if p.AllowPrivate {
+		return nil
+	}
+
+	host := target.Hostname()
+	addrs, err := p.resolve(ctx, host)
+	if err != nil {
+		return fmt.Errorf("host %s could not be resolved", host)
+	}
+	for _, addr := range addrs {
+		if !p.allows(addr) {
+			return fmt.Errorf("host %s resolves to the non-public address %s", host, addr.Unmap())
+		}
+	}
+	return nil
+}
+
+func (p WebhookTargetPolicy) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+	if p.lookup != nil {
+		return p.lookup(ctx, host)
+	}
+	return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+}
+
+// Refuses connections to addresses the policy does not allow; for net.Dialer.Control,
+// which sees the address actually dialled after resolution
+func (p WebhookTargetPolicy) control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !p.allows(addrPort.Addr()) {
+		return fmt.Errorf("refusing to connect to non-public address %s", addrPort.Addr().Unmap())
+	}
+	return nil
+}
+
+// An HTTP client for webhook deliveries: it only dials allowed addresses, never goes
+// through a proxy and does not follow redirects, which count as failed deliveries.
+func (p WebhookTargetPolicy) client(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   p.control,
+	}).DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -12,7 +12,7 @@ CREATE TABLE IF NOT EXISTS tasks (
 );
 
 -- Index for filtering by status
-CREATE INDEX idx_tasks_status ON tasks(status);
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
 
 -- Index for sorting by created_at
-CREATE INDEX idx_tasks_created_at ON tasks(created_at DESC);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at DESC);
\ No newline at end of file
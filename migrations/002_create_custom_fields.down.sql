@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS custom_field_definitions;
+
+-- Also drops the per-field expression indexes
+ALTER TABLE tasks DROP COLUMN IF EXISTS custom_fields;
@@ -0,0 +1,24 @@
+-- Custom field values live on the task as JSONB, keyed by definition key
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- Index for containment queries over all custom fields
+CREATE INDEX IF NOT EXISTS idx_tasks_custom_fields ON tasks USING GIN (custom_fields);
+
+-- Create custom field definitions table
+CREATE TABLE IF NOT EXISTS custom_field_definitions (
+    id SERIAL PRIMARY KEY,
+    key VARCHAR(63) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Keys are used in expression index names and JSONB paths
+    CONSTRAINT valid_field_key CHECK (key ~ '^[a-z][a-z0-9_]{0,62}$'),
+    CONSTRAINT valid_field_type CHECK (type IN ('text', 'number', 'date', 'enum', 'boolean'))
+);
+
+-- Per-field expression indexes (tasks.custom_fields -> '<key>') are created by the
+-- application when a definition is added and dropped when it is removed.
@@ -0,0 +1,17 @@
+-- Manual ordering within a status column (see models/rank.go for the format).
+-- The "C" collation makes comparisons byte-wise, matching the rank arithmetic.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank TEXT COLLATE "C";
+
+-- Rank existing tasks in creation order within their column
+UPDATE tasks t
+SET rank = '0:' || lpad(to_hex(ranked.position), 8, '0') || 'i'
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY status ORDER BY created_at, id) AS position
+    FROM tasks
+) ranked
+WHERE t.id = ranked.id AND t.rank IS NULL;
+
+ALTER TABLE tasks ALTER COLUMN rank SET NOT NULL;
+
+-- Index for board ordering and neighbor lookups
+CREATE INDEX IF NOT EXISTS idx_tasks_status_rank ON tasks(status, rank, id);
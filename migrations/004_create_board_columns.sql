@@ -0,0 +1,12 @@
+-- Per-column board settings, keyed by task status
+CREATE TABLE IF NOT EXISTS board_columns (
+    status VARCHAR(50) PRIMARY KEY,
+    wip_limit INTEGER,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT valid_column_status CHECK (status IN ('pending', 'in_progress', 'completed', 'closed')),
+    CONSTRAINT positive_wip_limit CHECK (wip_limit IS NULL OR wip_limit > 0)
+);
+
+INSERT INTO board_columns (status) VALUES ('pending'), ('in_progress'), ('completed'), ('closed')
+ON CONFLICT (status) DO NOTHING;
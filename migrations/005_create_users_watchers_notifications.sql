@@ -0,0 +1,40 @@
+-- Create users table; API tokens are stored as SHA-256 hashes
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(50) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    api_token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT valid_username CHECK (username ~ '^[A-Za-z0-9_][A-Za-z0-9_.-]*$')
+);
+
+-- Users following a task. No foreign key on task_id: watchers of a deleted task
+-- are notified of the deletion first and removed afterwards.
+CREATE TABLE IF NOT EXISTS task_watchers (
+    task_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    PRIMARY KEY (task_id, user_id)
+);
+
+-- Index for listing what a user watches
+CREATE INDEX IF NOT EXISTS idx_task_watchers_user ON task_watchers(user_id);
+
+-- Per-user notifications; task_id is kept after the task is deleted
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]'::jsonb,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Index for a user's inbox, newest first, and for unread counts
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
@@ -0,0 +1,15 @@
+-- Users mentioned with @username in a task's text
+CREATE TABLE IF NOT EXISTS mentions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    source VARCHAR(50) NOT NULL,
+    excerpt TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Index for a user's mentions, newest first
+CREATE INDEX IF NOT EXISTS idx_mentions_user_created ON mentions(user_id, created_at DESC);
+
+-- Handles are resolved case-insensitively
+CREATE INDEX IF NOT EXISTS idx_users_username_lower ON users(LOWER(username));
@@ -0,0 +1,13 @@
+-- Announce every outbox message on the task_events channel once its transaction
+-- commits, so each replica can stream it to its connected clients
+CREATE OR REPLACE FUNCTION notify_outbox_insert() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('task_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS outbox_notify ON outbox;
+CREATE TRIGGER outbox_notify
+    AFTER INSERT ON outbox
+    FOR EACH ROW EXECUTE FUNCTION notify_outbox_insert();
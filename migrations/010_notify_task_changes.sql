@@ -0,0 +1,20 @@
+-- Announce every change to a task on the task_changes channel once its transaction
+-- commits, so each replica can drop what it has cached for that task
+CREATE OR REPLACE FUNCTION notify_task_change() RETURNS trigger AS $$
+DECLARE
+    task_id INTEGER;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        task_id := OLD.id;
+    ELSE
+        task_id := NEW.id;
+    END IF;
+    PERFORM pg_notify('task_changes', json_build_object('op', TG_OP, 'id', task_id)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tasks_notify ON tasks;
+CREATE TRIGGER tasks_notify
+    AFTER INSERT OR UPDATE OR DELETE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION notify_task_change();
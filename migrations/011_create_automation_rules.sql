@@ -0,0 +1,14 @@
+-- Automation rules: when an event of trigger_event matches every condition, run the actions
+CREATE TABLE IF NOT EXISTS automation_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    trigger_event VARCHAR(50) NOT NULL,
+    conditions JSONB NOT NULL DEFAULT '[]',
+    actions JSONB NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Index for loading the enabled rules of an event type after each change
+CREATE INDEX IF NOT EXISTS idx_automation_rules_trigger ON automation_rules(trigger_event) WHERE enabled;
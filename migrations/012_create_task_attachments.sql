@@ -0,0 +1,14 @@
+-- Files attached to tasks, such as those of emails that created or updated them
+CREATE TABLE IF NOT EXISTS task_attachments (
+    id SERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size INTEGER NOT NULL,
+    source VARCHAR(255) NOT NULL DEFAULT '',
+    data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Index for listing a task's attachments
+CREATE INDEX IF NOT EXISTS idx_task_attachments_task ON task_attachments(task_id, id);
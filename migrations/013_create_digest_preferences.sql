@@ -0,0 +1,15 @@
+-- Per-user email digest settings; an empty statuses array means every status
+CREATE TABLE IF NOT EXISTS digest_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(10) NOT NULL DEFAULT 'off',
+    statuses TEXT[] NOT NULL DEFAULT '{}',
+    watched_only BOOLEAN NOT NULL DEFAULT FALSE,
+    last_sent_at TIMESTAMP WITH TIME ZONE,
+    next_digest_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT valid_digest_frequency CHECK (frequency IN ('off', 'daily', 'weekly'))
+);
+
+-- Index for the scheduler's scan of digests that are due
+CREATE INDEX IF NOT EXISTS idx_digest_preferences_due ON digest_preferences(next_digest_at) WHERE frequency <> 'off';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_outbox_webhook;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS outbox_id;
+
+DROP INDEX IF EXISTS idx_notifications_outbox_user;
+ALTER TABLE notifications DROP COLUMN IF EXISTS outbox_id;
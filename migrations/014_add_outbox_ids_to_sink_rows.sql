@@ -0,0 +1,8 @@
+-- The outbox message notifications and webhook deliveries were created for, so a relay
+-- retrying a message does not create them twice. Rows created outside the outbox have
+-- none and are never deduplicated.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS outbox_id BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_outbox_user ON notifications(outbox_id, user_id);
+
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS outbox_id BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_outbox_webhook ON webhook_deliveries(outbox_id, webhook_id);
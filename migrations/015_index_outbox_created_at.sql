@@ -0,0 +1,3 @@
+-- Outbox IDs are assigned on insert, so messages can commit out of ID order. Streams
+-- catching up look back a short time before the last message they saw.
+CREATE INDEX IF NOT EXISTS idx_outbox_created_at ON outbox(created_at);
@@ -0,0 +1,3 @@
+-- Usernames are looked up case-insensitively, so they must be unique regardless of case.
+-- Fails if the table already holds usernames differing only in case; rename one first.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users (LOWER(username));
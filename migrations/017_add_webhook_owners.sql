@@ -0,0 +1,6 @@
+-- Webhooks belong to the user who registered them and are only visible to that user.
+-- Webhooks registered before owners were recorded have none and stay hidden, though
+-- they keep receiving deliveries; delete them directly in the database if unwanted.
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS created_by INTEGER REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_created_by ON webhooks(created_by);
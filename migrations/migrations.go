@@ -0,0 +1,12 @@
+// Package migrations embeds the SQL schema migrations so the server binary can
+// apply them itself (see database.Migrator).
+//
+// Each migration is a NNN_name.sql file with an optional NNN_name.down.sql that
+// reverts it. Migrations must be safe to re-run: databases created before
+// migrations were tracked have no record of what was applied.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,22 @@
+-- Tasks, mirroring the Postgres schema after its own migrations.
+-- AUTOINCREMENT keeps ids of deleted tasks from being reused, like SERIAL.
+CREATE TABLE IF NOT EXISTS tasks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    custom_fields TEXT NOT NULL DEFAULT '{}',
+    -- Compared byte-wise by the default BINARY collation, like "C" in Postgres
+    rank TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+
+    CONSTRAINT valid_status CHECK (status IN ('pending', 'in_progress', 'completed', 'closed')),
+    CONSTRAINT valid_custom_fields CHECK (json_valid(custom_fields) AND json_type(custom_fields) = 'object')
+);
+
+-- Index for board ordering and neighbor lookups
+CREATE INDEX IF NOT EXISTS idx_tasks_status_rank ON tasks(status, rank, id);
+
+-- Index for sorting by created_at
+CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at DESC);
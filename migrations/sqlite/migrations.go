@@ -0,0 +1,8 @@
+// Package sqlite embeds the schema migrations of the SQLite backend, which only
+// stores tasks. They follow the same file conventions as the Postgres migrations.
+package sqlite
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS